@@ -0,0 +1,26 @@
+package conf
+
+type ReconcilerAppConf struct {
+	ReconcileGapSeconds     int `yaml:"reconcileGapSeconds"`
+	MisfireThresholdSeconds int `yaml:"misfireThresholdSeconds"`
+	LookbackHours           int `yaml:"lookbackHours"`
+	DispatchLockSeconds     int `yaml:"dispatchLockSeconds"`
+}
+
+var defaultReconcilerAppConfProvider *ReconcilerAppConfProvider
+
+type ReconcilerAppConfProvider struct {
+	conf *ReconcilerAppConf
+}
+
+func NewReconcilerAppConfProvider(conf *ReconcilerAppConf) *ReconcilerAppConfProvider {
+	return &ReconcilerAppConfProvider{conf: conf}
+}
+
+func (r *ReconcilerAppConfProvider) Get() *ReconcilerAppConf {
+	return r.conf
+}
+
+func DefaultReconcilerAppConfProvider() *ReconcilerAppConfProvider {
+	return defaultReconcilerAppConfProvider
+}
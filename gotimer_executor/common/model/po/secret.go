@@ -0,0 +1,14 @@
+package po
+
+import "gorm.io/gorm"
+
+// AppSecret app 的回调签名密钥，同一 app 至多两个密钥同时生效以支持轮换
+type AppSecret struct {
+	gorm.Model
+	App    string `gorm:"column:app;NOT NULL"`    // 应用名
+	Secret string `gorm:"column:secret;NOT NULL"` // 签名密钥
+}
+
+func (a *AppSecret) TableName() string {
+	return "app_secret"
+}
@@ -9,18 +9,38 @@ import (
 // Task 运行流水记录
 type Task struct {
 	gorm.Model
-	App      string    `gorm:"column:app;NOT NULL"`           // 定义ID
-	TimerID  uint      `gorm:"column:timer_id;NOT NULL"`      // 定义ID
-	Output   string    `gorm:"column:output;default:null"`    // 执行结果
-	RunTimer time.Time `gorm:"column:run_timer;default:null"` // 执行时间
-	CostTime int       `gorm:"column:cost_time"`              // 执行耗时
-	Status   int       `gorm:"column:status;NOT NULL"`        // 当前状态
+	App          string    `gorm:"column:app;NOT NULL"`           // 定义ID
+	TimerID      uint      `gorm:"column:timer_id;NOT NULL"`      // 定义ID
+	Output       string    `gorm:"column:output;default:null"`    // 执行结果，响应体的前 OutputExcerptLen 个字符
+	RunTimer     time.Time `gorm:"column:run_timer;default:null"` // 执行时间
+	CostTime     int       `gorm:"column:cost_time"`              // 执行耗时，单位：ms
+	Status       int       `gorm:"column:status;NOT NULL"`        // 当前状态
+	Attempt      int       `gorm:"column:attempt"`                // 已执行次数，包含重试
+	FailReason   string    `gorm:"column:fail_reason"`            // 最近一次执行的错误信息，成功时为空
+	HTTPStatus   int       `gorm:"column:http_status"`            // 最近一次回调的 http 状态码，请求未得到响应时为 0
+	Truncated    bool      `gorm:"column:output_truncated"`       // 响应体超出 Output 长度时截断，完整内容存放于 task_output
+	Source       int       `gorm:"column:source;default:1"`       // 触发来源，1:调度触发, 2:手动触发
+	TimerVersion int       `gorm:"column:timer_version"`          // 生成该 task 的定时器版本，0 表示版本记录上线前生成
 }
 
 func (t *Task) TableName() string {
 	return "task"
 }
 
+// Output 列宽，超出部分截断
+const OutputExcerptLen = 256
+
+// TaskOutput 超出 Output 列宽的完整响应体，与 task 一一对应
+type TaskOutput struct {
+	gorm.Model
+	TaskID uint   `gorm:"column:task_id;NOT NULL"` // 任务 ID
+	Body   string `gorm:"column:body"`             // 完整响应体
+}
+
+func (t *TaskOutput) TableName() string {
+	return "task_output"
+}
+
 type MinuteTaskCnt struct {
 	Minute string `gorm:"column:minute"`
 	Cnt    int64  `gorm:"column:cnt"`
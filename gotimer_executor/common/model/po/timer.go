@@ -1,6 +1,9 @@
 package po
 
 import (
+	"fmt"
+	"hash/fnv"
+	"strings"
 	"time"
 
 	"gotimer_executor/common/consts"
@@ -11,25 +14,91 @@ import (
 // Timer 定时器定义
 type Timer struct {
 	gorm.Model
-	App             string `gorm:"column:app;NOT NULL" json:"app,omitempty"`                             // 定时器定义名称
-	Name            string `gorm:"column:name;NOT NULL" json:"name,omitempty"`                           // 定时器定义名称
-	Status          int    `gorm:"column:status;NOT NULL" json:"status,omitempty"`                       // 定时器定义状态，1:未激活, 2:已激活
-	Cron            string `gorm:"column:cron;NOT NULL" json:"cron,omitempty"`                           // 定时器定时配置
-	NotifyHTTPParam string `gorm:"column:notify_http_param;NOT NULL" json:"notify_http_param,omitempty"` // Http 回调参数
+	App               string     `gorm:"column:app;NOT NULL" json:"app,omitempty"`                                // 定时器定义名称
+	Name              string     `gorm:"column:name;NOT NULL" json:"name,omitempty"`                              // 定时器定义名称
+	Status            int        `gorm:"column:status;NOT NULL" json:"status,omitempty"`                          // 定时器定义状态，1:未激活, 2:已激活, 3:已结束
+	Type              int        `gorm:"column:type;NOT NULL" json:"type,omitempty"`                              // 定时器类型，1:cron 周期执行, 2:runAt 单次执行
+	Cron              string     `gorm:"column:cron;NOT NULL" json:"cron,omitempty"`                              // 定时器定时配置
+	RunAt             *time.Time `gorm:"column:run_at;default:null" json:"run_at,omitempty"`                      // 单次执行的时刻
+	Timezone          string     `gorm:"column:timezone" json:"timezone,omitempty"`                               // 解析 cron 使用的 IANA 时区，为空时使用服务器本地时区
+	NotifyHTTPParam   string     `gorm:"column:notify_http_param;NOT NULL" json:"notify_http_param,omitempty"`    // Http 回调参数
+	RetryPolicy       string     `gorm:"column:retry_policy;default:null" json:"retry_policy,omitempty"`          // 回调失败的重试策略
+	SuccessCriteria   string     `gorm:"column:success_criteria;default:null" json:"success_criteria,omitempty"`  // 回调成功的判定条件
+	MisfirePolicy     int        `gorm:"column:misfire_policy;default:1" json:"misfire_policy,omitempty"`         // 错过执行时机后的补偿策略，1:补偿一次, 2:全部补偿, 3:跳过
+	ConcurrencyPolicy int        `gorm:"column:concurrency_policy;default:1" json:"concurrency_policy,omitempty"` // 上一次执行未结束时的并发策略，1:允许, 2:跳过, 3:替代
+	IncludeCalendars  string     `gorm:"column:include_calendars" json:"include_calendars,omitempty"`             // 只在这些日历内执行，逗号分隔的日历名
+	ExcludeCalendars  string     `gorm:"column:exclude_calendars" json:"exclude_calendars,omitempty"`             // 不在这些日历内执行，逗号分隔的日历名
+	JitterSeconds     int        `gorm:"column:jitter_seconds;default:0" json:"jitter_seconds,omitempty"`         // 执行时机的最大后移秒数，0 表示不后移
+	Version           int        `gorm:"column:version;default:1" json:"version,omitempty"`                       // 定义的版本号，调度、回调或策略配置每变更一次加一
 }
 
 func (t *Timer) TableName() string {
 	return "timer"
 }
 
+// CalendarNames 定时器引用的全部日历名
+func (t *Timer) CalendarNames() []string {
+	var names []string
+	for _, field := range []string{t.IncludeCalendars, t.ExcludeCalendars} {
+		if field != "" {
+			names = append(names, strings.Split(field, ",")...)
+		}
+	}
+	return names
+}
+
+func (t *Timer) IsOnce() bool {
+	return t.Type == consts.OnceTimer.ToInt()
+}
+
+// Location cron 表达式按定时器所属时区的墙上时间解析
+func (t *Timer) Location() (*time.Location, error) {
+	if t.Timezone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(t.Timezone)
+}
+
+// RunAtBetween 一次性定时器在 [start, end) 内的执行时机
+func (t *Timer) RunAtBetween(start, end time.Time) []time.Time {
+	if t.RunAt == nil || t.RunAt.Before(start) || !t.RunAt.Before(end) {
+		return nil
+	}
+	return []time.Time{*t.RunAt}
+}
+
+// JitteredTimes 将 cron 执行时机后移 [0, JitterSeconds] 秒，用于错开同一时刻扎堆的定时器.
+// 偏移由定时器 id 和原执行时机哈希得到，重复生成时结果不变，可依赖 timer_id + run_timer 唯一键去重.
+// 创建时已校验抖动窗口小于 cron 的最小间隔；对校验之前创建的定时器，不晚于上一个执行时机的结果顺延到其后 1s，
+// 保证返回的执行时机严格递增且不重复，避免整批写入因唯一键冲突失败
+func (t *Timer) JitteredTimes(executeTimes []time.Time) []time.Time {
+	if t.JitterSeconds <= 0 || t.IsOnce() {
+		return executeTimes
+	}
+
+	jittered := make([]time.Time, 0, len(executeTimes))
+	for _, executeTime := range executeTimes {
+		hasher := fnv.New64a()
+		_, _ = fmt.Fprintf(hasher, "%d:%d", t.ID, executeTime.Unix())
+		offset := time.Duration(hasher.Sum64()%uint64(t.JitterSeconds+1)) * time.Second
+		next := executeTime.Add(offset)
+		if len(jittered) > 0 && !next.After(jittered[len(jittered)-1]) {
+			next = jittered[len(jittered)-1].Add(time.Second)
+		}
+		jittered = append(jittered, next)
+	}
+	return jittered
+}
+
 func (t *Timer) BatchTasksFromTimer(executeTimes []time.Time) []*Task {
 	tasks := make([]*Task, 0, len(executeTimes))
 	for _, executeTime := range executeTimes {
 		tasks = append(tasks, &Task{
-			App:      t.App,
-			TimerID:  t.Model.ID,
-			Status:   consts.NotRunned.ToInt(),
-			RunTimer: executeTime,
+			App:          t.App,
+			TimerID:      t.Model.ID,
+			Status:       consts.NotRunned.ToInt(),
+			RunTimer:     executeTime,
+			TimerVersion: t.Version,
 		})
 	}
 	return tasks
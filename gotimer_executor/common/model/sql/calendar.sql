@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS `calendar`
+(
+    `id`          bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `app`         varchar(255) NOT NULL COMMENT '所属应用名',
+    `name`        varchar(255) NOT NULL COMMENT '日历名',
+    `description` varchar(1024) NOT NULL DEFAULT '' COMMENT '日历说明',
+    `timezone`    varchar(64)  NOT NULL DEFAULT '' COMMENT '按日期配置的条目所在时区，空为服务器本地时区',
+    `entries`     json         DEFAULT NULL COMMENT '日期或时间段列表',
+    `created_at`  datetime     NOT NULL COMMENT '创建时间',
+    `updated_at`  datetime     NOT NULL ON UPDATE CURRENT_TIMESTAMP COMMENT '修改时间',
+    `deleted_at`  datetime     DEFAULT NULL COMMENT '删除时间',
+    PRIMARY KEY (`id`) USING BTREE COMMENT '主键索引',
+    UNIQUE KEY `uni_app_name` (`app`,`name`) USING BTREE COMMENT 'app name 索引'
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4;
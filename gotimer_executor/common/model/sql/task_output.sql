@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS `task_output`
+(
+    `id`         bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `task_id`    bigint(20) unsigned NOT NULL COMMENT '任务ID',
+    `body`       mediumtext   DEFAULT NULL COMMENT '完整响应体',
+    `created_at` datetime     NOT NULL COMMENT '创建时间',
+    `updated_at` datetime     NOT NULL ON UPDATE CURRENT_TIMESTAMP COMMENT '修改时间',
+    `deleted_at` datetime     DEFAULT NULL COMMENT '删除时间',
+    PRIMARY KEY (`id`) USING BTREE COMMENT '主键索引',
+    UNIQUE KEY `uni_task` (`task_id`) USING BTREE COMMENT '任务索引'
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4;
\ No newline at end of file
@@ -3,9 +3,20 @@ CREATE TABLE IF NOT EXISTS `timer`
     `id`                bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
     `app`               varchar(255) NOT NULL COMMENT '应用名',
     `name`              varchar(255) NOT NULL COMMENT '定时器name',
-    `status`            smallint(255) NOT NULL COMMENT '定时器状态 1未激活 2激活',
-    `cron`              varchar(255) NOT NULL COMMENT '定时表达式',
+    `status`            smallint(255) NOT NULL COMMENT '定时器状态 1未激活 2激活 3已结束',
+    `type`              smallint(255) NOT NULL DEFAULT 1 COMMENT '定时器类型 1cron周期执行 2runAt单次执行',
+    `cron`              varchar(255) NOT NULL DEFAULT '' COMMENT '定时表达式',
+    `run_at`            datetime     DEFAULT NULL COMMENT '单次执行时刻',
+    `timezone`          varchar(64)  NOT NULL DEFAULT '' COMMENT 'cron 解析时区，空为服务器本地时区',
     `notify_http_param` json         DEFAULT NULL COMMENT 'http 参数',
+    `retry_policy`      json         DEFAULT NULL COMMENT '回调失败的重试策略',
+    `success_criteria`  json         DEFAULT NULL COMMENT '回调成功的判定条件',
+    `misfire_policy`    smallint(255) NOT NULL DEFAULT 1 COMMENT '错过执行时机后的补偿策略 1补偿一次 2全部补偿 3跳过',
+    `concurrency_policy` smallint(255) NOT NULL DEFAULT 1 COMMENT '上一次执行未结束时的并发策略 1允许 2跳过 3替代',
+    `include_calendars` varchar(1024) NOT NULL DEFAULT '' COMMENT '只在这些日历内执行，逗号分隔的日历名',
+    `exclude_calendars` varchar(1024) NOT NULL DEFAULT '' COMMENT '不在这些日历内执行，逗号分隔的日历名',
+    `jitter_seconds`    int(11)      NOT NULL DEFAULT 0 COMMENT '执行时机的最大随机后移秒数，0 不后移',
+    `version`           int(11)      NOT NULL DEFAULT 1 COMMENT '定义的版本号，调度、回调或策略配置每变更一次加一',
     `deleted_at`        datetime     DEFAULT NULL COMMENT '删除时间',
     `created_at`        datetime     NOT NULL COMMENT '创建时间',
     `updated_at`        datetime     DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `timer_version`
+(
+    `id`         bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `app`        varchar(255) NOT NULL COMMENT '定时器所属应用名',
+    `timer_id`   bigint(20) unsigned NOT NULL COMMENT '定时器ID',
+    `version`    int(11)      NOT NULL COMMENT '版本号',
+    `snapshot`   json         NOT NULL COMMENT '该版本的定时器定义',
+    `actor`      varchar(255) NOT NULL COMMENT '操作人',
+    `created_at` datetime     NOT NULL COMMENT '创建时间',
+    PRIMARY KEY (`id`) USING BTREE COMMENT '主键索引',
+    UNIQUE KEY `uni_timer_version` (`timer_id`,`version`) USING BTREE COMMENT '定时器ID 版本号索引'
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4;
\ No newline at end of file
@@ -0,0 +1,141 @@
+package vo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gotimer_executor/common/consts"
+	"gotimer_executor/common/model/po"
+)
+
+const calendarDateFormat = "2006-01-02"
+
+// CalendarEntry 日历条目，Date 与 Start/End 二选一
+type CalendarEntry struct {
+	Date   string     `json:"date,omitempty"`   // 整天，格式 2006-01-02，按日历所在时区解析
+	Start  *time.Time `json:"start,omitempty"`  // 时间段起点，包含
+	End    *time.Time `json:"end,omitempty"`    // 时间段终点，不包含
+	Reason string     `json:"reason,omitempty"` // 说明，如 国庆节、机房维护
+}
+
+// Calendar 节假日/停机窗口日历
+type Calendar struct {
+	ID          uint             `json:"id,omitempty"`
+	App         string           `json:"app" binding:"required"`  // 所属应用名
+	Name        string           `json:"name" binding:"required"` // 日历名，同一应用下唯一
+	Description string           `json:"description,omitempty"`   // 日历说明
+	Timezone    string           `json:"timezone,omitempty"`      // 按日期配置的条目所在时区，默认服务器本地时区
+	Entries     []*CalendarEntry `json:"entries"`                 // 日期或时间段列表
+	CreatedAt   time.Time        `json:"createdAt"`
+	UpdatedAt   time.Time        `json:"updatedAt"`
+}
+
+func NewCalendar(calendar *po.Calendar) (*Calendar, error) {
+	var entries []*CalendarEntry
+	if calendar.Entries != "" {
+		if err := json.Unmarshal([]byte(calendar.Entries), &entries); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Calendar{
+		ID:          calendar.ID,
+		App:         calendar.App,
+		Name:        calendar.Name,
+		Description: calendar.Description,
+		Timezone:    calendar.Timezone,
+		Entries:     entries,
+		CreatedAt:   calendar.CreatedAt,
+		UpdatedAt:   calendar.UpdatedAt,
+	}, nil
+}
+
+func (c *Calendar) location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(c.Timezone)
+}
+
+// Match 返回 t 命中的第一个条目
+func (c *Calendar) Match(t time.Time) (*CalendarEntry, bool) {
+	loc, err := c.location()
+	if err != nil {
+		return nil, false
+	}
+
+	for _, entry := range c.Entries {
+		if entry.Date != "" {
+			day, err := time.ParseInLocation(calendarDateFormat, entry.Date, loc)
+			if err == nil && !t.Before(day) && t.Before(day.AddDate(0, 0, 1)) {
+				return entry, true
+			}
+			continue
+		}
+		if entry.Start != nil && entry.End != nil && !t.Before(*entry.Start) && t.Before(*entry.End) {
+			return entry, true
+		}
+	}
+	return nil, false
+}
+
+// ApplyCalendars 按定时器引用的日历过滤执行时机，被过滤的 task 置为已跳过并记录原因.
+// 不在任一 include 日历内，或命中任一 exclude 日历的执行时机会被过滤；找不到的日历视为空日历
+func ApplyCalendars(timer *po.Timer, tasks []*po.Task, calendars []*po.Calendar) error {
+	if timer.IncludeCalendars == "" && timer.ExcludeCalendars == "" {
+		return nil
+	}
+
+	byName := make(map[string]*Calendar, len(calendars))
+	for _, calendar := range calendars {
+		vCalendar, err := NewCalendar(calendar)
+		if err != nil {
+			return fmt.Errorf("parse calendar: %s failed, err: %w", calendar.Name, err)
+		}
+		byName[calendar.Name] = vCalendar
+	}
+
+	for _, task := range tasks {
+		if reason, skipped := skipReason(timer, byName, task.RunTimer); skipped {
+			task.Status = consts.Skipped.ToInt()
+			task.FailReason = reason
+		}
+	}
+	return nil
+}
+
+func skipReason(timer *po.Timer, calendars map[string]*Calendar, runTime time.Time) (string, bool) {
+	if timer.IncludeCalendars != "" {
+		included := false
+		for _, name := range strings.Split(timer.IncludeCalendars, ",") {
+			if calendar, ok := calendars[name]; ok {
+				if _, ok := calendar.Match(runTime); ok {
+					included = true
+					break
+				}
+			}
+		}
+		if !included {
+			return fmt.Sprintf("not in include calendars: %s", timer.IncludeCalendars), true
+		}
+	}
+
+	if timer.ExcludeCalendars == "" {
+		return "", false
+	}
+	for _, name := range strings.Split(timer.ExcludeCalendars, ",") {
+		calendar, ok := calendars[name]
+		if !ok {
+			continue
+		}
+		if entry, ok := calendar.Match(runTime); ok {
+			if entry.Reason != "" {
+				return fmt.Sprintf("excluded by calendar %s: %s", name, entry.Reason), true
+			}
+			return fmt.Sprintf("excluded by calendar %s", name), true
+		}
+	}
+	return "", false
+}
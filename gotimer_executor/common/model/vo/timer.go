@@ -3,9 +3,13 @@ package vo
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"time"
 
 	"gotimer_executor/common/consts"
 	"gotimer_executor/common/model/po"
+	"gotimer_executor/pkg/jsonpath"
 )
 
 type GetAppTimersReq struct {
@@ -63,12 +67,19 @@ func NewGetTimerResp(timer *Timer, codeMsg CodeMsg) *GetTimerResp {
 }
 
 type Timer struct {
-	ID              uint               `json:"id,omitempty"`
-	App             string             `json:"app,omitempty" binding:"required"`             // 定时器定义名称
-	Name            string             `json:"name,omitempty" binding:"required"`            // 定时器定义名称
-	Status          consts.TimerStatus `json:"status"`                                       // 定时器定义状态，1:未激活, 2:已激活
-	Cron            string             `json:"cron,omitempty" binding:"required"`            // 定时器定时配置
-	NotifyHTTPParam *NotifyHTTPParam   `json:"notifyHTTPParam,omitempty" binding:"required"` // http 回调参数
+	ID                uint                     `json:"id,omitempty"`
+	App               string                   `json:"app,omitempty" binding:"required"`             // 定时器定义名称
+	Name              string                   `json:"name,omitempty" binding:"required"`            // 定时器定义名称
+	Status            consts.TimerStatus       `json:"status"`                                       // 定时器定义状态，1:未激活, 2:已激活, 3:已结束
+	Type              consts.TimerType         `json:"type,omitempty"`                               // 定时器类型，1:cron 周期执行(默认), 2:runAt 单次执行
+	Cron              string                   `json:"cron,omitempty"`                               // 定时器定时配置，cron 类型必填
+	RunAt             *time.Time               `json:"runAt,omitempty"`                              // 单次执行的时刻，runAt 类型必填
+	Timezone          string                   `json:"timezone,omitempty"`                           // 解析 cron 使用的 IANA 时区，如 Asia/Shanghai，默认服务器本地时区
+	NotifyHTTPParam   *NotifyHTTPParam         `json:"notifyHTTPParam,omitempty" binding:"required"` // http 回调参数
+	RetryPolicy       *RetryPolicy             `json:"retryPolicy,omitempty"`                        // 回调失败的重试策略，为空时不重试
+	SuccessCriteria   *SuccessCriteria         `json:"successCriteria,omitempty"`                    // 回调成功的判定条件，为空时 2xx 即成功
+	MisfirePolicy     consts.MisfirePolicy     `json:"misfirePolicy,omitempty"`                      // 错过执行时机后的补偿策略，1:补偿一次(默认), 2:全部补偿, 3:跳过
+	ConcurrencyPolicy consts.ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`                  // 上一次执行未结束时的并发策略，1:允许(默认), 2:跳过, 3:替代
 }
 
 type NotifyHTTPParam struct {
@@ -76,6 +87,120 @@ type NotifyHTTPParam struct {
 	URL    string            `json:"url,omitempty" binding:"required"`    // URL 路径
 	Header map[string]string `json:"header,omitempty"`                    // header 请求头
 	Body   string            `json:"body,omitempty"`                      // 请求参数体
+	// 回调超时时间，单位：s，为 0 时使用执行器默认的超时时间
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+}
+
+const (
+	// 重试策略允许的最大执行次数，包含首次执行
+	maxRetryAttempts = 20
+	// 单次重试前的最大等待时间，按倍数放大后超出时以此为准，单位：s
+	maxRetryBackoffSeconds = 3600
+	// 重试等待时间允许的最大放大倍数
+	maxRetryMultiplier = 10
+)
+
+type RetryPolicy struct {
+	MaxAttempts           int     `json:"maxAttempts"`                    // 最大执行次数，包含首次执行，不超过 20
+	InitialBackoffSeconds int     `json:"initialBackoffSeconds"`          // 首次重试前的等待时间，单位：s，不超过 3600
+	Multiplier            float64 `json:"multiplier,omitempty"`           // 每次重试等待时间的放大倍数，取值 [1, 10]，默认为 1. 放大后的等待时间不超过 3600s
+	RetryableStatusCodes  []int   `json:"retryableStatusCodes,omitempty"` // 可重试的 http 状态码，为空时任意失败都重试；请求未得到响应时总是重试
+}
+
+// Retryable 第 attempt 次执行失败后是否还能重试，statusCode 为 0 表示请求未得到响应
+func (r *RetryPolicy) Retryable(attempt, statusCode int) bool {
+	if r == nil || attempt >= r.MaxAttempts {
+		return false
+	}
+	if statusCode == 0 || len(r.RetryableStatusCodes) == 0 {
+		return true
+	}
+	for _, code := range r.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// Backoff 第 attempt 次执行失败后，距离下一次重试的等待时间，不超过 maxRetryBackoffSeconds
+func (r *RetryPolicy) Backoff(attempt int) time.Duration {
+	multiplier := r.Multiplier
+	if multiplier == 0 {
+		multiplier = 1
+	}
+	seconds := float64(r.InitialBackoffSeconds) * math.Pow(multiplier, float64(attempt-1))
+	if seconds > maxRetryBackoffSeconds {
+		seconds = maxRetryBackoffSeconds
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+type SuccessCriteria struct {
+	StatusRanges []StatusRange `json:"statusRanges,omitempty"` // 视为成功的 http 状态码区间，为空时默认 [200, 299]
+	Assertion    string        `json:"assertion,omitempty"`    // 针对 json 响应体的断言，如 $.code == 0
+}
+
+type StatusRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+var defaultStatusRanges = []StatusRange{{Min: 200, Max: 299}}
+
+// Evaluate 判定回调是否成功，失败时返回原因. criteria 为空时按默认条件判定
+func (c *SuccessCriteria) Evaluate(statusCode int, body []byte) error {
+	ranges := defaultStatusRanges
+	if c != nil && len(c.StatusRanges) > 0 {
+		ranges = c.StatusRanges
+	}
+
+	accepted := false
+	for _, r := range ranges {
+		if statusCode >= r.Min && statusCode <= r.Max {
+			accepted = true
+			break
+		}
+	}
+	if !accepted {
+		return fmt.Errorf("unexpected http status: %d", statusCode)
+	}
+
+	if c == nil || c.Assertion == "" {
+		return nil
+	}
+	assertion, err := jsonpath.Parse(c.Assertion)
+	if err != nil {
+		return err
+	}
+	return assertion.Evaluate(body)
+}
+
+func (c *SuccessCriteria) Check() error {
+	for _, r := range c.StatusRanges {
+		if r.Min < 100 || r.Max > 599 || r.Min > r.Max {
+			return fmt.Errorf("invalid status range of success criteria: [%d, %d]", r.Min, r.Max)
+		}
+	}
+	if c.Assertion == "" {
+		return nil
+	}
+	_, err := jsonpath.Parse(c.Assertion)
+	return err
+}
+
+func (r *RetryPolicy) Check() error {
+	if r.MaxAttempts < 1 || r.MaxAttempts > maxRetryAttempts {
+		return fmt.Errorf("invalid maxAttempts of retry policy: %d, should be in [1, %d]", r.MaxAttempts, maxRetryAttempts)
+	}
+	if r.InitialBackoffSeconds < 0 || r.InitialBackoffSeconds > maxRetryBackoffSeconds {
+		return fmt.Errorf("invalid initialBackoffSeconds of retry policy: %d, should be in [0, %d]", r.InitialBackoffSeconds, maxRetryBackoffSeconds)
+	}
+	// 0 表示未指定，按 1 处理
+	if r.Multiplier != 0 && (r.Multiplier < 1 || r.Multiplier > maxRetryMultiplier) {
+		return fmt.Errorf("invalid multiplier of retry policy: %v, should be in [1, %d]", r.Multiplier, maxRetryMultiplier)
+	}
+	return nil
 }
 
 func NewTimer(timer *po.Timer) (*Timer, error) {
@@ -84,13 +209,36 @@ func NewTimer(timer *po.Timer) (*Timer, error) {
 		return nil, err
 	}
 
+	var retryPolicy *RetryPolicy
+	if timer.RetryPolicy != "" {
+		retryPolicy = &RetryPolicy{}
+		if err := json.Unmarshal([]byte(timer.RetryPolicy), retryPolicy); err != nil {
+			return nil, err
+		}
+	}
+
+	var successCriteria *SuccessCriteria
+	if timer.SuccessCriteria != "" {
+		successCriteria = &SuccessCriteria{}
+		if err := json.Unmarshal([]byte(timer.SuccessCriteria), successCriteria); err != nil {
+			return nil, err
+		}
+	}
+
 	return &Timer{
-		ID:              timer.ID,
-		App:             timer.App,
-		Name:            timer.Name,
-		Status:          consts.TimerStatus(timer.Status),
-		Cron:            timer.Cron,
-		NotifyHTTPParam: &param,
+		ID:                timer.ID,
+		App:               timer.App,
+		Name:              timer.Name,
+		Status:            consts.TimerStatus(timer.Status),
+		Type:              consts.TimerType(timer.Type),
+		Cron:              timer.Cron,
+		RunAt:             timer.RunAt,
+		Timezone:          timer.Timezone,
+		NotifyHTTPParam:   &param,
+		RetryPolicy:       retryPolicy,
+		SuccessCriteria:   successCriteria,
+		MisfirePolicy:     consts.MisfirePolicy(timer.MisfirePolicy),
+		ConcurrencyPolicy: consts.ConcurrencyPolicy(timer.ConcurrencyPolicy),
 	}, nil
 }
 
@@ -110,10 +258,53 @@ func (t *Timer) Check() error {
 	if t.NotifyHTTPParam == nil {
 		return errors.New("empty notify http params")
 	}
+
+	switch t.Type {
+	case consts.CronTimer:
+		if t.Cron == "" {
+			return errors.New("empty cron of cron timer")
+		}
+	case consts.OnceTimer:
+		if t.RunAt == nil {
+			return errors.New("empty runAt of once timer")
+		}
+	default:
+		return fmt.Errorf("invalid timer type: %d", t.Type)
+	}
+
+	switch t.MisfirePolicy {
+	case consts.MisfireFireOnce, consts.MisfireFireAll, consts.MisfireSkip:
+	default:
+		return fmt.Errorf("invalid misfire policy: %d", t.MisfirePolicy)
+	}
+
+	switch t.ConcurrencyPolicy {
+	case consts.ConcurrencyAllow, consts.ConcurrencyForbid, consts.ConcurrencyReplace:
+	default:
+		return fmt.Errorf("invalid concurrency policy: %d", t.ConcurrencyPolicy)
+	}
+
+	if t.RetryPolicy != nil {
+		if err := t.RetryPolicy.Check(); err != nil {
+			return err
+		}
+	}
+	if t.SuccessCriteria != nil {
+		return t.SuccessCriteria.Check()
+	}
 	return nil
 }
 
 func (t *Timer) ToPO() (*po.Timer, error) {
+	if t.Type == 0 {
+		t.Type = consts.CronTimer
+	}
+	if t.MisfirePolicy == 0 {
+		t.MisfirePolicy = consts.MisfireFireOnce
+	}
+	if t.ConcurrencyPolicy == 0 {
+		t.ConcurrencyPolicy = consts.ConcurrencyAllow
+	}
 	if err := t.Check(); err != nil {
 		return nil, err
 	}
@@ -124,11 +315,30 @@ func (t *Timer) ToPO() (*po.Timer, error) {
 	}
 
 	timer := po.Timer{
-		App:             t.App,
-		Name:            t.Name,
-		Status:          t.Status.ToInt(),
-		Cron:            t.Cron,
-		NotifyHTTPParam: string(param),
+		App:               t.App,
+		Name:              t.Name,
+		Status:            t.Status.ToInt(),
+		Type:              t.Type.ToInt(),
+		Cron:              t.Cron,
+		RunAt:             t.RunAt,
+		Timezone:          t.Timezone,
+		NotifyHTTPParam:   string(param),
+		MisfirePolicy:     t.MisfirePolicy.ToInt(),
+		ConcurrencyPolicy: t.ConcurrencyPolicy.ToInt(),
+	}
+	if t.RetryPolicy != nil {
+		retryPolicy, err := json.Marshal(t.RetryPolicy)
+		if err != nil {
+			return nil, err
+		}
+		timer.RetryPolicy = string(retryPolicy)
+	}
+	if t.SuccessCriteria != nil {
+		successCriteria, err := json.Marshal(t.SuccessCriteria)
+		if err != nil {
+			return nil, err
+		}
+		timer.SuccessCriteria = string(successCriteria)
 	}
 	if timer.Status == 0 {
 		timer.Status = consts.Unabled.ToInt()
@@ -40,6 +40,22 @@ func GetMigratorLockKey(t time.Time) string {
 	return fmt.Sprintf("migrator_lock_%s", t.Format(consts.HourFormat))
 }
 
+func GetReconcilerLockKey(t time.Time) string {
+	return fmt.Sprintf("reconciler_lock_%s", t.Format(consts.MinuteFormat))
+}
+
+func GetMisfireDispatchLockKey(timerIDUnixKey string) string {
+	return "misfire_dispatch_lock_" + timerIDUnixKey
+}
+
+func GetTimerRunningLockKey(timerID uint) string {
+	return fmt.Sprintf("timer_running_lock_%d", timerID)
+}
+
+func GetTimerRunningOwnerKey(timerID uint) string {
+	return fmt.Sprintf("timer_running_owner_%d", timerID)
+}
+
 func GetMonitorLockKey(t time.Time) string {
 	return fmt.Sprintf("monitor_lock_%s", t.Format(consts.MinuteFormat))
 }
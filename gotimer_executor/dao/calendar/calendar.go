@@ -0,0 +1,29 @@
+package calendar
+
+import (
+	"context"
+
+	"gotimer_executor/common/model/po"
+	"gotimer_executor/pkg/mysql"
+)
+
+type CalendarDAO struct {
+	client *mysql.Client
+}
+
+func NewCalendarDAO(client *mysql.Client) *CalendarDAO {
+	return &CalendarDAO{
+		client: client,
+	}
+}
+
+// GetTimerCalendars 获取定时器引用的日历
+func (c *CalendarDAO) GetTimerCalendars(ctx context.Context, timer *po.Timer) ([]*po.Calendar, error) {
+	names := timer.CalendarNames()
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	var calendars []*po.Calendar
+	return calendars, c.client.DB.WithContext(ctx).Where("app = ? AND name IN ?", timer.App, names).Find(&calendars).Error
+}
@@ -0,0 +1,27 @@
+package secret
+
+import (
+	"context"
+
+	"gotimer_executor/common/model/po"
+	"gotimer_executor/pkg/mysql"
+)
+
+// MaxActiveSecrets 同一 app 同时生效的密钥数量，与 web 端轮换逻辑保持一致
+const MaxActiveSecrets = 2
+
+type SecretDAO struct {
+	client *mysql.Client
+}
+
+func NewSecretDAO(client *mysql.Client) *SecretDAO {
+	return &SecretDAO{
+		client: client,
+	}
+}
+
+// GetActiveSecrets 获取 app 下生效的密钥，新密钥在前
+func (s *SecretDAO) GetActiveSecrets(ctx context.Context, app string) ([]*po.AppSecret, error) {
+	var secrets []*po.AppSecret
+	return secrets, s.client.DB.WithContext(ctx).Where("app = ?", app).Order("id DESC").Limit(MaxActiveSecrets).Find(&secrets).Error
+}
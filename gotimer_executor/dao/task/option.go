@@ -50,6 +50,19 @@ func WithStatuses(statuses []int32) Option {
 	}
 }
 
+// WithIDAfter id 大于 id 的 task，配合 WithIDAsc 按 id 游标分批读取
+func WithIDAfter(id uint) Option {
+	return func(d *gorm.DB) *gorm.DB {
+		return d.Where("id > ?", id)
+	}
+}
+
+func WithIDAsc() Option {
+	return func(d *gorm.DB) *gorm.DB {
+		return d.Order("id ASC")
+	}
+}
+
 func WithAsc() Option {
 	return func(d *gorm.DB) *gorm.DB {
 		return d.Order("created_at ASC")
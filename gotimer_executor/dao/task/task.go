@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 
+	"gorm.io/gorm/clause"
+
+	"gotimer_executor/common/consts"
 	"gotimer_executor/common/model/po"
 	"gotimer_executor/pkg/mysql"
 )
@@ -38,8 +41,43 @@ func (t *TaskDAO) GetTasks(ctx context.Context, opts ...Option) ([]*po.Task, err
 	return tasks, db.Model(&po.Task{}).Scan(&tasks).Error
 }
 
+// UpdateTask 更新任务的全部字段，保证零值（如清空的失败原因）同样落库
 func (t *TaskDAO) UpdateTask(ctx context.Context, task *po.Task) error {
-	return t.client.DB.WithContext(ctx).Updates(task).Error
+	return t.client.DB.WithContext(ctx).Select("*").Updates(task).Error
+}
+
+// SaveTaskOutput 保存任务的完整响应体，重试时覆盖上一次执行的结果
+func (t *TaskDAO) SaveTaskOutput(ctx context.Context, output *po.TaskOutput) error {
+	return t.client.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "task_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"body", "updated_at"}),
+	}).Create(output).Error
+}
+
+// ClaimTask 将未执行或重试中的 task 置为执行中，返回是否抢占成功. 同一 task 被重复投递时只有一个 executor 能抢占成功
+func (t *TaskDAO) ClaimTask(ctx context.Context, id uint) (bool, error) {
+	res := t.client.DB.WithContext(ctx).Model(&po.Task{}).
+		Where("id = ? AND status IN ?", id, []int{consts.NotRunned.ToInt(), consts.Retrying.ToInt()}).
+		Update("status", consts.Running.ToInt())
+	return res.RowsAffected == 1, res.Error
+}
+
+// MarkMissed 将仍未执行的 task 记为错过，已被执行的 task 不受影响
+func (t *TaskDAO) MarkMissed(ctx context.Context, ids []uint) error {
+	return t.client.DB.WithContext(ctx).Model(&po.Task{}).
+		Where("id IN ? AND status = ?", ids, consts.NotRunned.ToInt()).
+		Update("status", consts.Missed.ToInt()).Error
+}
+
+// MarkCancelled 将仍未执行的 task 记为已取消并记录原因，已被执行的 task 不受影响
+func (t *TaskDAO) MarkCancelled(ctx context.Context, ids []uint, reason string) error {
+	return t.client.DB.WithContext(ctx).Model(&po.Task{}).
+		Where("id IN ? AND status = ?", ids, consts.NotRunned.ToInt()).
+		Updates(map[string]interface{}{"status": consts.Cancelled.ToInt(), "fail_reason": reason}).Error
+}
+
+func (t *TaskDAO) DeleteTaskOutput(ctx context.Context, taskID uint) error {
+	return t.client.DB.WithContext(ctx).Unscoped().Where("task_id = ?", taskID).Delete(&po.TaskOutput{}).Error
 }
 
 func (t *TaskDAO) Count(ctx context.Context, opts ...Option) (int64, error) {
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"github.com/spf13/viper"
 	cf "gotimer_executor/common/conf"
+	calendardao "gotimer_executor/dao/calendar"
+	secretdao "gotimer_executor/dao/secret"
 	"gotimer_executor/dao/task"
 	"gotimer_executor/dao/timer"
 	"gotimer_executor/pkg/bloom"
@@ -17,8 +19,11 @@ import (
 	"gotimer_executor/pkg/xhttp"
 	"gotimer_executor/service/executor"
 	mg "gotimer_executor/service/migrator"
+	"gotimer_executor/service/reconciler"
 	"os"
 	"sync"
+	// 内置时区数据库，镜像中缺少 tzdata 时定时器时区依然可用
+	_ "time/tzdata"
 )
 
 var wg sync.WaitGroup
@@ -27,6 +32,7 @@ var defaultRedisConfProvider *cf.RedisConfigProvider
 var defaultMysqlConf *cf.MysqlConfProvider
 var defaultSchedulerConf *cf.SchedulerAppConfProvider
 var defaultMigratorConf *cf.MigratorAppConfProvider
+var defaultReconcilerConf *cf.ReconcilerAppConfProvider
 
 // 兜底配置
 var gConf GloablConf = GloablConf{
@@ -42,6 +48,16 @@ var gConf GloablConf = GloablConf{
 		// 迁移器提前将定时器数据缓存到内存中的保存时间，单位：min
 		TimerDetailCacheMinutes: 2,
 	},
+	Reconciler: &cf.ReconcilerAppConf{
+		// 扫描超时未执行 task 的时间间隔，单位：s
+		ReconcileGapSeconds: 60,
+		// task 超过执行时间多久仍未执行视为错过，单位：s
+		MisfireThresholdSeconds: 120,
+		// 向前扫描的时间范围，单位：h
+		LookbackHours: 24,
+		// 补偿投递后，避免同一 task 被重复投递的锁时间，单位：s
+		DispatchLockSeconds: 600,
+	},
 	Scheduler: &cf.SchedulerAppConf{
 		// 单节点并行协程数
 		WorkersNum: 100,
@@ -73,11 +89,12 @@ var gConf GloablConf = GloablConf{
 }
 
 type GloablConf struct {
-	Scheduler *cf.SchedulerAppConf `yaml:"scheduler"`
-	Redis     *cf.RedisConfig      `yaml:"redis"`
-	Trigger   *cf.TriggerAppConf   `yaml:"trigger"`
-	Mysql     *cf.MySQLConfig      `yaml:"mysql"`
-	Migrator  *cf.MigratorAppConf  `yaml:"migrator"`
+	Scheduler  *cf.SchedulerAppConf  `yaml:"scheduler"`
+	Redis      *cf.RedisConfig       `yaml:"redis"`
+	Trigger    *cf.TriggerAppConf    `yaml:"trigger"`
+	Mysql      *cf.MySQLConfig       `yaml:"mysql"`
+	Migrator   *cf.MigratorAppConf   `yaml:"migrator"`
+	Reconciler *cf.ReconcilerAppConf `yaml:"reconciler"`
 }
 
 func main() {
@@ -103,6 +120,7 @@ func main() {
 	defaultRedisConfProvider = cf.NewRedisConfigProvider(gConf.Redis)
 	defaultMysqlConf = cf.NewMysqlConfProvider(gConf.Mysql)
 	defaultMigratorConf = cf.NewMigratorAppConfProvider(gConf.Migrator)
+	defaultReconcilerConf = cf.NewReconcilerAppConfProvider(gConf.Reconciler)
 
 	mysqlClient, err := mysql.GetClient(defaultMysqlConf)
 	timerDao := timer.NewTimerDAO(mysqlClient)
@@ -121,7 +139,7 @@ func main() {
 
 	tashCache := task.NewTaskCache(redisCLient, defaultSchedulerConf)
 	cronPr := cron.NewCronParser()
-	migrateWoker := mg.NewWorker(timerDao, taskDao, tashCache, redisCLient, cronPr, defaultMigratorConf)
+	migrateWoker := mg.NewWorker(timerDao, taskDao, calendardao.NewCalendarDAO(mysqlClient), tashCache, redisCLient, cronPr, defaultMigratorConf)
 
 	go func() {
 		fmt.Println("开始迁移")
@@ -130,7 +148,15 @@ func main() {
 		fmt.Println("迁移执行成功")
 	}()
 
-	executorWorker := executor.NewWorker(timerService, taskDao, jsonClient, filter, rep)
+	secretService := executor.NewSecretService(secretdao.NewSecretDAO(mysqlClient))
+	executorWorker := executor.NewWorker(timerService, taskDao, jsonClient, filter, rep, redisCLient, secretService)
+
+	reconcileWorker := reconciler.NewWorker(timerService, taskDao, executorWorker, redisCLient, defaultReconcilerConf)
+	go func() {
+		if err := reconcileWorker.Start(context.Background()); err != nil {
+			log.Errorf("reconciler start failed,%v", err)
+		}
+	}()
 
 	for {
 		msg, err := executorWorker.Consumer.Receive(context.Background())
@@ -37,7 +37,15 @@ func (c *CronParser) NextsBefore(cron string, end time.Time) ([]time.Time, error
 	return c.NextsBetween(cron, time.Now(), end)
 }
 
+// NextsBetween 返回时间段 [start, end) 内触发时机的切片，按服务器本地时区解析 cron
 func (c *CronParser) NextsBetween(cron string, start, end time.Time) ([]time.Time, error) {
+	return c.NextsBetweenIn(cron, time.Local, start, end)
+}
+
+// NextsBetweenIn 按 loc 时区的墙上时间解析 cron，返回时间段 [start, end) 内触发时机的切片
+// 夏令时切换时：被跳过的墙上时间顺延到跳变之后，与已有触发时机重合的不重复触发；
+// 重复出现的墙上时间只在第一次出现时触发
+func (c *CronParser) NextsBetweenIn(cron string, loc *time.Location, start, end time.Time) ([]time.Time, error) {
 	if end.Before(start) {
 		return nil, fmt.Errorf("end can not earlier than start, start: %v, end: %v", start, end)
 	}
@@ -47,15 +55,46 @@ func (c *CronParser) NextsBetween(cron string, start, end time.Time) ([]time.Tim
 		return nil, err
 	}
 
-	var nexts []time.Time
-	for start.Before(end) {
-		next := expr.Next(start)
-		if next.UnixNano() < 0 {
-			return nil, fmt.Errorf("fail to parse time from cron: %s", cron)
+	// 在没有夏令时的 UTC 中推算墙上时间，再映射回 loc 中的真实时刻
+	// 从 start 的前一秒开始推算，保证 start 本身也能命中
+	wall := toWallClock(start.Add(-time.Second), loc)
+	var (
+		nexts []time.Time
+		last  time.Time
+	)
+	for {
+		// 之后不再有触发时机
+		if wall = expr.Next(wall); wall.IsZero() {
+			break
+		}
+
+		next := fromWallClock(wall, loc)
+		if !next.Before(end) {
+			break
+		}
+		if next.Before(start) || (!last.IsZero() && !next.After(last)) {
+			continue
 		}
 		nexts = append(nexts, next)
-		start = next
+		last = next
 	}
-
 	return nexts, nil
 }
+
+// 取 t 在 loc 时区下的墙上时间，以 UTC 表示
+func toWallClock(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+// 将墙上时间映射为 loc 时区下的真实时刻
+// 墙上时间落在夏令时跳变的空档内时，time.Date 会按跳变后的偏移回退到跳变之前，
+// 这里改为按跳变前的偏移换算，得到跳变之后对应的时刻
+func fromWallClock(wall time.Time, loc *time.Location) time.Time {
+	t := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, loc)
+	if t.Hour() == wall.Hour() && t.Minute() == wall.Minute() {
+		return t
+	}
+	_, offset := t.Zone()
+	return wall.Add(-time.Duration(offset) * time.Second).In(loc)
+}
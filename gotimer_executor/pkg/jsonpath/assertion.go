@@ -0,0 +1,264 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// 支持的比较运算符，同一位置优先匹配 >=、<= 等双字符运算符
+var operators = []string{"==", "!=", ">=", "<=", ">", "<"}
+
+// Assertion 针对 json 响应体的断言，形如 `$.code == 0`、`$.data.list[0].ok == true`.
+// 仅写路径不写运算符时，断言路径存在且值不为 false/null/0/""
+type Assertion struct {
+	expr     string
+	path     []interface{} // string 表示对象字段，int 表示数组下标
+	operator string
+	expected interface{}
+}
+
+func Parse(expr string) (*Assertion, error) {
+	expr = strings.TrimSpace(expr)
+	a := Assertion{expr: expr}
+
+	// 先解析路径，运算符从路径结束处开始匹配，避免 ["a==b"] 等路径中的运算符字符被误匹配
+	path, rest, err := parsePath(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid json path assertion: %s, err: %w", expr, err)
+	}
+	a.path = path
+
+	rest = strings.TrimSpace(rest)
+	var literal string
+	if rest != "" {
+		for _, op := range operators {
+			if strings.HasPrefix(rest, op) {
+				a.operator, literal = op, strings.TrimSpace(rest[len(op):])
+				break
+			}
+		}
+		if a.operator == "" {
+			return nil, fmt.Errorf("invalid json path assertion: %s, expect operator after path, got: %s", expr, rest)
+		}
+	}
+
+	if a.operator == "" {
+		return &a, nil
+	}
+
+	if a.expected, err = parseLiteral(literal); err != nil {
+		return nil, fmt.Errorf("invalid json path assertion: %s, err: %w", expr, err)
+	}
+	if _, ok := a.expected.(float64); !ok && a.operator != "==" && a.operator != "!=" {
+		if _, ok := a.expected.(string); !ok {
+			return nil, fmt.Errorf("invalid json path assertion: %s, operator %s only supports number or string", expr, a.operator)
+		}
+	}
+	return &a, nil
+}
+
+// Evaluate 对响应体执行断言，不满足时返回失败原因
+func (a *Assertion) Evaluate(body []byte) error {
+	var root interface{}
+	if err := json.Unmarshal(body, &root); err != nil {
+		return fmt.Errorf("assertion %s failed, response is not json: %w", a.expr, err)
+	}
+
+	actual, ok := lookup(root, a.path)
+	if !ok {
+		return fmt.Errorf("assertion %s failed, path not found", a.expr)
+	}
+
+	if a.operator == "" {
+		if !truthy(actual) {
+			return fmt.Errorf("assertion %s failed, actual: %s", a.expr, format(actual))
+		}
+		return nil
+	}
+
+	if !compare(actual, a.operator, a.expected) {
+		return fmt.Errorf("assertion %s failed, actual: %s", a.expr, format(actual))
+	}
+	return nil
+}
+
+func (a *Assertion) String() string {
+	return a.expr
+}
+
+// parsePath 解析表达式开头的路径，返回路径之后的剩余部分
+func parsePath(expr string) ([]interface{}, string, error) {
+	if !strings.HasPrefix(expr, "$") {
+		return nil, "", fmt.Errorf("path must start with $")
+	}
+
+	var path []interface{}
+	rest := expr[1:]
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[ \t=!<>")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, "", fmt.Errorf("empty field name in path: %s", expr)
+			}
+			path = append(path, rest[:end])
+			rest = rest[end:]
+		case '[':
+			end := closingBracket(rest)
+			if end < 0 {
+				return nil, "", fmt.Errorf("unclosed [ in path: %s", expr)
+			}
+			seg := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			if index, err := strconv.Atoi(seg); err == nil {
+				if index < 0 {
+					return nil, "", fmt.Errorf("negative index in path: %s", expr)
+				}
+				path = append(path, index)
+				continue
+			}
+			name, err := parseLiteral(seg)
+			if _, ok := name.(string); err != nil || !ok {
+				return nil, "", fmt.Errorf("invalid segment [%s] in path: %s", seg, expr)
+			}
+			path = append(path, name)
+		case ' ', '\t', '=', '!', '<', '>':
+			return path, rest, nil
+		default:
+			return nil, "", fmt.Errorf("unexpected %q in path: %s", rest[0], expr)
+		}
+	}
+	return path, rest, nil
+}
+
+// closingBracket 返回 [ 对应的 ] 的位置，跳过引号括起的字段名中的字符，不存在时返回 -1
+func closingBracket(s string) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote == 0 && (s[i] == '\'' || s[i] == '"'):
+			quote = s[i]
+		case quote == 0 && s[i] == ']':
+			return i
+		case quote != 0 && s[i] == '\\':
+			i++
+		case quote != 0 && s[i] == quote:
+			quote = 0
+		}
+	}
+	return -1
+}
+
+// parseLiteral 解析 json 字面量，额外支持单引号字符串
+func parseLiteral(literal string) (interface{}, error) {
+	if len(literal) >= 2 && literal[0] == '\'' && literal[len(literal)-1] == '\'' {
+		return literal[1 : len(literal)-1], nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(literal), &v); err != nil {
+		return nil, fmt.Errorf("invalid literal: %s", literal)
+	}
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return nil, fmt.Errorf("object or array literal is not supported: %s", literal)
+	}
+	return v, nil
+}
+
+func lookup(node interface{}, path []interface{}) (interface{}, bool) {
+	for _, seg := range path {
+		switch key := seg.(type) {
+		case string:
+			obj, ok := node.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if node, ok = obj[key]; !ok {
+				return nil, false
+			}
+		case int:
+			arr, ok := node.([]interface{})
+			if !ok || key >= len(arr) {
+				return nil, false
+			}
+			node = arr[key]
+		}
+	}
+	return node, true
+}
+
+func compare(actual interface{}, operator string, expected interface{}) bool {
+	switch operator {
+	case "==":
+		return reflect.DeepEqual(actual, expected)
+	case "!=":
+		return !reflect.DeepEqual(actual, expected)
+	}
+
+	var cmp int
+	switch e := expected.(type) {
+	case float64:
+		a, ok := actual.(float64)
+		if !ok {
+			return false
+		}
+		cmp = compareFloat(a, e)
+	case string:
+		a, ok := actual.(string)
+		if !ok {
+			return false
+		}
+		cmp = strings.Compare(a, e)
+	default:
+		return false
+	}
+
+	switch operator {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a > b:
+		return 1
+	case a < b:
+		return -1
+	}
+	return 0
+}
+
+func truthy(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case float64:
+		return t != 0
+	case string:
+		return t != ""
+	}
+	return true
+}
+
+func format(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
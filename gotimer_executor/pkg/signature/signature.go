@@ -0,0 +1,116 @@
+// Package signature 实现回调请求的 HMAC-SHA256 签名与校验.
+//
+// executor 发起回调时携带两个请求头：
+//
+//	X-Gotimer-Timestamp: 1712345678
+//	X-Gotimer-Signature: v1=<hex>,v1=<hex>
+//
+// 签名内容为 "{method}\n{url}\n{timestamp}\n{body}"，对 app 下每个生效的密钥各生成一个 v1 签名.
+// 密钥轮换期间两个密钥同时生效，接收方持有其中任意一个即可校验通过.
+// 供接收方引入的校验实现为独立模块 gotimer_signature，修改签名方案时需同步修改
+package signature
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	TimestampHeader = "X-Gotimer-Timestamp"
+	SignatureHeader = "X-Gotimer-Signature"
+
+	// 签名方案版本
+	schemeV1 = "v1"
+	// 默认允许的请求时间偏差，超出视为重放
+	DefaultTolerance = 5 * time.Minute
+)
+
+var (
+	ErrMissingHeader     = errors.New("missing signature headers")
+	ErrInvalidHeader     = errors.New("invalid signature headers")
+	ErrExpired           = errors.New("signature timestamp out of tolerance")
+	ErrNoValidSecret     = errors.New("no secret provided")
+	ErrSignatureMismatch = errors.New("signature mismatch")
+)
+
+// Sign 使用单个密钥计算签名，返回十六进制编码
+func Sign(secret, method, url string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = fmt.Fprintf(mac, "%s\n%s\n%d\n", strings.ToUpper(method), url, timestamp)
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Headers 生成回调需要携带的签名请求头，secrets 为 app 下全部生效的密钥
+func Headers(secrets []string, method, url string, timestamp int64, body []byte) map[string]string {
+	signatures := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		signatures = append(signatures, schemeV1+"="+Sign(secret, method, url, timestamp, body))
+	}
+	return map[string]string{
+		TimestampHeader: strconv.FormatInt(timestamp, 10),
+		SignatureHeader: strings.Join(signatures, ","),
+	}
+}
+
+// Verify 校验签名. secrets 为接收方持有的密钥，轮换期间可同时传入新旧两个密钥；tolerance 为 0 时使用 DefaultTolerance
+func Verify(secrets []string, method, url, timestampHeader, signatureHeader string, body []byte, tolerance time.Duration, now time.Time) error {
+	if len(secrets) == 0 {
+		return ErrNoValidSecret
+	}
+	if timestampHeader == "" || signatureHeader == "" {
+		return ErrMissingHeader
+	}
+
+	timestamp, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return ErrInvalidHeader
+	}
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	if diff := now.Sub(time.Unix(timestamp, 0)); diff > tolerance || diff < -tolerance {
+		return ErrExpired
+	}
+
+	for _, part := range strings.Split(signatureHeader, ",") {
+		scheme, sig, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || scheme != schemeV1 {
+			continue
+		}
+		got, err := hex.DecodeString(sig)
+		if err != nil {
+			continue
+		}
+		for _, secret := range secrets {
+			expected, _ := hex.DecodeString(Sign(secret, method, url, timestamp, body))
+			if hmac.Equal(got, expected) {
+				return nil
+			}
+		}
+	}
+	return ErrSignatureMismatch
+}
+
+// VerifyRequest 校验收到的回调请求，读取后会重置 r.Body 以便后续继续使用.
+// url 为定时器配置的回调地址，经过网关转发时接收方看到的地址可能与之不同，因此需由接收方显式传入
+func VerifyRequest(r *http.Request, secrets []string, url string, tolerance time.Duration) error {
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = io.ReadAll(r.Body); err != nil {
+			return err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	return Verify(secrets, r.Method, url, r.Header.Get(TimestampHeader), r.Header.Get(SignatureHeader), body, tolerance, time.Now())
+}
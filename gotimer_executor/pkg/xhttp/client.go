@@ -49,17 +49,37 @@ func (j *JSONClient) Delete(ctx context.Context, url string, header map[string]s
 }
 
 func (j *JSONClient) Do(ctx context.Context, method string, url string, header map[string]string, req, resp interface{}) error {
-	tCtx, cancel := context.WithTimeout(ctx, j.timeoutDuration)
+	_, err := j.DoWithStatus(ctx, method, url, header, req, resp)
+	return err
+}
+
+// DoWithStatus 同 Do，额外返回 http 状态码，请求未得到响应时状态码为 0
+func (j *JSONClient) DoWithStatus(ctx context.Context, method string, url string, header map[string]string, req, resp interface{}) (int, error) {
+	statusCode, respBody, err := j.DoRaw(ctx, method, url, header, req)
+	if err != nil {
+		return statusCode, err
+	}
+
+	return statusCode, json.Unmarshal(respBody, resp)
+}
+
+// DoRaw 以 json 格式发送请求，返回 http 状态码和未经解析的响应体，请求未得到响应时状态码为 0
+func (j *JSONClient) DoRaw(ctx context.Context, method string, url string, header map[string]string, req interface{}) (int, []byte, error) {
+	// ctx 已设置超时时间时以 ctx 为准，否则使用客户端默认的超时时间
+	tCtx, cancel := ctx, context.CancelFunc(func() {})
+	if _, ok := ctx.Deadline(); !ok {
+		tCtx, cancel = context.WithTimeout(ctx, j.timeoutDuration)
+	}
 	defer cancel()
 
 	reqBody, err := json.Marshal(req)
 	if err != nil {
-		return err
+		return 0, nil, err
 	}
 
 	request, err := http.NewRequestWithContext(tCtx, method, url, bytes.NewReader(reqBody))
 	if err != nil {
-		return err
+		return 0, nil, err
 	}
 
 	for k, v := range header {
@@ -68,15 +88,12 @@ func (j *JSONClient) Do(ctx context.Context, method string, url string, header m
 
 	response, err := http.DefaultClient.Do(request)
 	if err != nil {
-		return err
+		return 0, nil, err
 	}
+	defer response.Body.Close()
 
 	respBody, err := io.ReadAll(io.LimitReader(response.Body, j.readLimitBytes))
-	if err != nil {
-		return err
-	}
-
-	return json.Unmarshal(respBody, resp)
+	return response.StatusCode, respBody, err
 }
 
 func getCompleteURL(originURL string, params map[string]string) string {
@@ -0,0 +1,133 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	redigo "github.com/gomodule/redigo/redis"
+
+	"gotimer_executor/common/consts"
+	"gotimer_executor/common/model/po"
+	"gotimer_executor/common/model/vo"
+	"gotimer_executor/common/utils"
+	"gotimer_executor/pkg/log"
+	"gotimer_executor/pkg/redis"
+)
+
+const (
+	// 并发控制相关 key 的过期时间，保证 executor 异常退出后自动释放，单位：s. 回调执行期间按 runningLockRenewGap 续期
+	runningLockSeconds = 60
+	// Forbid 策略下回调执行期间为互斥锁续期的时间间隔，需小于 runningLockSeconds
+	runningLockRenewGap = 20 * time.Second
+	// Replace 策略下检查本次执行是否已被其他节点替代的时间间隔
+	replaceCheckGap = time.Second
+)
+
+// runningExec Replace 策略下本节点上正在进行的一次执行
+type runningExec struct {
+	key    string
+	cancel context.CancelFunc
+}
+
+// acquire 按定时器的并发策略获取执行权，返回执行回调使用的 ctx 和执行结束后的释放函数. ok 为 false 表示应跳过本次执行
+func (w *Worker) acquire(ctx context.Context, timer *vo.Timer, timerIDUnixKey string) (context.Context, func(), bool) {
+	switch timer.ConcurrencyPolicy {
+	case consts.ConcurrencyForbid:
+		// 跨节点互斥，上一次执行持有锁期间新一次执行被跳过
+		locker := w.lockService.GetDistributionLock(utils.GetTimerRunningLockKey(timer.ID))
+		if err := locker.Lock(ctx, runningLockSeconds); err != nil {
+			return ctx, nil, false
+		}
+		renewCtx, stopRenew := context.WithCancel(ctx)
+		go w.renewRunningLock(renewCtx, locker, timer)
+		return ctx, func() {
+			stopRenew()
+			if err := locker.Unlock(ctx); err != nil {
+				log.WarnContextf(ctx, "release timer running lock failed, timerID: %d, err: %v", timer.ID, err)
+			}
+		}, true
+
+	case consts.ConcurrencyReplace:
+		// 登记为最新一次执行，本节点上的上一次执行直接中止，其他节点上的上一次执行通过轮询 owner 感知后中止
+		execCtx, cancel := context.WithCancel(ctx)
+		exec := &runningExec{key: timerIDUnixKey, cancel: cancel}
+		if prev, loaded := w.running.Swap(timer.ID, exec); loaded {
+			prev.(*runningExec).cancel()
+		}
+		if err := w.lockService.SetEx(ctx, utils.GetTimerRunningOwnerKey(timer.ID), timerIDUnixKey, runningLockSeconds); err != nil {
+			log.WarnContextf(ctx, "set timer running owner failed, timerID: %d, err: %v", timer.ID, err)
+		}
+		go w.watchReplaced(execCtx, cancel, timer, timerIDUnixKey)
+		return execCtx, func() {
+			w.running.CompareAndDelete(timer.ID, exec)
+			cancel()
+		}, true
+	}
+
+	return ctx, func() {}, true
+}
+
+// renewRunningLock 回调执行期间定期为互斥锁续期，避免回调耗时超过锁的过期时间后下一次执行并发进入
+func (w *Worker) renewRunningLock(ctx context.Context, locker redis.DistributeLocker, timer *vo.Timer) {
+	ticker := time.NewTicker(runningLockRenewGap)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := locker.ExpireLock(ctx, runningLockSeconds); err != nil {
+				log.WarnContextf(ctx, "renew timer running lock failed, timerID: %d, err: %v", timer.ID, err)
+				return
+			}
+		}
+	}
+}
+
+// watchReplaced 执行期间轮询 owner，被其他节点上更新的执行替代后中止本次回调
+func (w *Worker) watchReplaced(ctx context.Context, cancel context.CancelFunc, timer *vo.Timer, timerIDUnixKey string) {
+	ticker := time.NewTicker(replaceCheckGap)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if w.replaced(ctx, timer, timerIDUnixKey) {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// replaced Replace 策略下，本次执行是否已被更新的执行替代
+func (w *Worker) replaced(ctx context.Context, timer *vo.Timer, timerIDUnixKey string) bool {
+	if timer.ConcurrencyPolicy != consts.ConcurrencyReplace {
+		return false
+	}
+
+	owner, err := w.lockService.Get(ctx, utils.GetTimerRunningOwnerKey(timer.ID))
+	if err != nil {
+		if !errors.Is(err, redigo.ErrNil) {
+			log.WarnContextf(ctx, "get timer running owner failed, timerID: %d, err: %v", timer.ID, err)
+		}
+		return false
+	}
+	return owner != timerIDUnixKey
+}
+
+// abandon 本次执行按并发策略被跳过或替代，将 task 记为终态并记录原因
+func (w *Worker) abandon(ctx context.Context, task *po.Task, status consts.TaskStatus, reason string) error {
+	log.WarnContextf(ctx, "task abandoned, timerID: %d, runTimer: %v, reason: %s", task.TimerID, task.RunTimer, reason)
+	task.Status = status.ToInt()
+	task.FailReason = reason
+	return w.taskDAO.UpdateTask(ctx, task)
+}
+
+type lockService interface {
+	GetDistributionLock(key string) redis.DistributeLocker
+	SetEx(ctx context.Context, key, value string, expireSeconds int64) error
+	Get(ctx context.Context, key string) (string, error)
+}
@@ -0,0 +1,55 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gotimer_executor/common/model/po"
+	secretdao "gotimer_executor/dao/secret"
+)
+
+// 密钥缓存时间，轮换后最长经过该时间新密钥参与签名
+const secretCacheDuration = time.Minute
+
+type cachedSecrets struct {
+	secrets  []string
+	expireAt time.Time
+}
+
+// SecretService 提供 app 的回调签名密钥，在内存中短暂缓存以避免每次回调都查库
+type SecretService struct {
+	dao   secretDAO
+	cache sync.Map
+}
+
+func NewSecretService(dao *secretdao.SecretDAO) *SecretService {
+	return &SecretService{
+		dao: dao,
+	}
+}
+
+// GetSecrets 获取 app 下生效的密钥，app 未配置密钥时返回空，回调不签名
+func (s *SecretService) GetSecrets(ctx context.Context, app string) ([]string, error) {
+	if cached, ok := s.cache.Load(app); ok && time.Now().Before(cached.(*cachedSecrets).expireAt) {
+		return cached.(*cachedSecrets).secrets, nil
+	}
+
+	pSecrets, err := s.dao.GetActiveSecrets(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	secrets := make([]string, 0, len(pSecrets))
+	for _, secret := range pSecrets {
+		secrets = append(secrets, secret.Secret)
+	}
+	s.cache.Store(app, &cachedSecrets{
+		secrets:  secrets,
+		expireAt: time.Now().Add(secretCacheDuration),
+	})
+	return secrets, nil
+}
+
+type secretDAO interface {
+	GetActiveSecrets(ctx context.Context, app string) ([]*po.AppSecret, error)
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"gorm.io/gorm"
 	"gotimer_executor/common/conf"
 	"gotimer_executor/common/consts"
 	"gotimer_executor/common/model/po"
@@ -125,7 +126,13 @@ func (t *TimerService) Stop() {
 	t.stop()
 }
 
+// 一次性定时器的 task 执行完毕后，将定时器置为已结束
+func (t *TimerService) FinishTimer(ctx context.Context, id uint) error {
+	return t.timerDAO.UpdateTimer(ctx, &po.Timer{Model: gorm.Model{ID: id}, Status: consts.Finished.ToInt()})
+}
+
 type timerDAO interface {
 	GetTimer(context.Context, ...timerdao.Option) (*po.Timer, error)
 	GetTimers(ctx context.Context, opts ...timerdao.Option) ([]*po.Timer, error)
+	UpdateTimer(ctx context.Context, timer *po.Timer) error
 }
@@ -3,34 +3,47 @@ package executor
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/apache/pulsar-client-go/pulsar"
+	"gorm.io/gorm"
 	"gotimer_executor/mq"
 	nethttp "net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"gotimer_executor/common/consts"
+	"gotimer_executor/common/model/po"
 	"gotimer_executor/common/model/vo"
 	"gotimer_executor/common/utils"
 	taskdao "gotimer_executor/dao/task"
 	"gotimer_executor/pkg/bloom"
 	"gotimer_executor/pkg/log"
 	"gotimer_executor/pkg/promethus"
+	"gotimer_executor/pkg/redis"
+	"gotimer_executor/pkg/signature"
 	"gotimer_executor/pkg/xhttp"
 )
 
 type Worker struct {
-	timerService *TimerService
-	taskDAO      *taskdao.TaskDAO
-	httpClient   *xhttp.JSONClient
-	bloomFilter  *bloom.Filter
-	reporter     *promethus.Reporter
-	pc           *mq.PulsarClient
-	Consumer     pulsar.Consumer
+	timerService  *TimerService
+	secretService *SecretService
+	taskDAO       *taskdao.TaskDAO
+	httpClient    *xhttp.JSONClient
+	bloomFilter   *bloom.Filter
+	reporter      *promethus.Reporter
+	pc            *mq.PulsarClient
+	Consumer      pulsar.Consumer
+	// 回调失败需要重试时，将任务延迟投递回 trigger-topic
+	Producer    pulsar.Producer
+	lockService lockService
+	// Replace 并发策略下，本节点上各定时器正在进行的执行
+	running sync.Map
 }
 
-func NewWorker(timerService *TimerService, taskDAO *taskdao.TaskDAO, httpClient *xhttp.JSONClient, bloomFilter *bloom.Filter, reporter *promethus.Reporter) *Worker {
+func NewWorker(timerService *TimerService, taskDAO *taskdao.TaskDAO, httpClient *xhttp.JSONClient, bloomFilter *bloom.Filter, reporter *promethus.Reporter,
+	lockService *redis.Client, secretService *SecretService) *Worker {
 	pc := mq.GetPulsarClient()
 	consumer, err := pc.Client.Subscribe(pulsar.ConsumerOptions{
 		Topic:            "trigger-topic",
@@ -40,14 +53,24 @@ func NewWorker(timerService *TimerService, taskDAO *taskdao.TaskDAO, httpClient
 	if err != nil {
 		log.Errorf("executor consumer init failed,%v", err)
 	}
+
+	producer, err := pc.Client.CreateProducer(pulsar.ProducerOptions{
+		Topic: "trigger-topic",
+	})
+	if err != nil {
+		log.Errorf("executor producer init failed,%v", err)
+	}
 	return &Worker{
-		pc:           pc,
-		Consumer:     consumer,
-		timerService: timerService,
-		taskDAO:      taskDAO,
-		httpClient:   httpClient,
-		bloomFilter:  bloomFilter,
-		reporter:     reporter,
+		pc:            pc,
+		Consumer:      consumer,
+		Producer:      producer,
+		timerService:  timerService,
+		taskDAO:       taskDAO,
+		httpClient:    httpClient,
+		bloomFilter:   bloomFilter,
+		reporter:      reporter,
+		lockService:   lockService,
+		secretService: secretService,
 	}
 }
 
@@ -70,58 +93,149 @@ func (w *Worker) Work(ctx context.Context, timerIDUnixKey string) error {
 		log.WarnContextf(ctx, "bloom filter check failed, start to check db, bloom key: %s, timerIDUnixKey: %s, err: %v, exist: %t", utils.GetTaskBloomFilterKey(utils.GetDayStr(time.UnixMilli(unix))), timerIDUnixKey, err, exist)
 		// 查库判断定时器状态
 		task, err := w.taskDAO.GetTask(ctx, taskdao.WithTimerID(timerID), taskdao.WithRunTimer(time.UnixMilli(unix)))
-		if err == nil && task.Status != consts.NotRunned.ToInt() {
+		if err == nil && task.Status != consts.NotRunned.ToInt() && task.Status != consts.Retrying.ToInt() {
 			// 重复执行的任务
 			log.WarnContextf(ctx, "task is already executed, timerID: %d, exec_time: %v", timerID, task.RunTimer)
 			return nil
 		}
 	}
 	fmt.Println("幂等去重通过")
-	return w.executeAndPostProcess(ctx, timerID, unix)
+
+	// 定时器更新 cron 后旧的执行时机已被清理，去激活或删除后的执行时机已被取消，均不再执行
+	task, err := w.taskDAO.GetTask(ctx, taskdao.WithTimerID(timerID), taskdao.WithRunTimer(time.UnixMilli(unix)))
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && task.Status == consts.Cancelled.ToInt()) {
+		log.WarnContextf(ctx, "task has been removed or cancelled, timerID: %d, exec_time: %v", timerID, time.UnixMilli(unix))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get task failed, timerID: %d, runTimer: %v, err: %w", timerID, time.UnixMilli(unix), err)
+	}
+	return w.executeAndPostProcess(ctx, task, timerID, unix)
 }
 
-func (w *Worker) executeAndPostProcess(ctx context.Context, timerID uint, unix int64) error {
+func (w *Worker) executeAndPostProcess(ctx context.Context, task *po.Task, timerID uint, unix int64) error {
+	source := consts.TriggerSource(task.Source)
 	// 未执行，则查询 timer 完整的定义，执行回调
 	timer, err := w.timerService.GetTimer(ctx, timerID)
 	if err != nil {
 		return fmt.Errorf("get timer failed, id: %d, err: %w", timerID, err)
 	}
 
-	// 定时器已经处于去激活态，则无需处理任务
-	if timer.Status != consts.Enabled {
+	// 定时器已经处于去激活态，则无需处理任务. 手动触发的任务不受定时器状态限制
+	if source != consts.ManualTrigger && timer.Status != consts.Enabled {
 		log.WarnContextf(ctx, "timer has alread been unabled, timerID: %d", timerID)
 		return nil
 	}
 
+	// 抢占 task，避免同一 task 被重复投递时并发执行
+	claimed, err := w.taskDAO.ClaimTask(ctx, task.ID)
+	if err != nil {
+		return fmt.Errorf("claim task failed, timerID: %d, runTimer: %v, err: %w", timerID, time.UnixMilli(unix), err)
+	}
+	if !claimed {
+		log.WarnContextf(ctx, "task is claimed by others, timerID: %d, exec_time: %v", timerID, time.UnixMilli(unix))
+		return nil
+	}
+
+	timerIDUnixKey := utils.UnionTimerIDUnix(timerID, unix)
+	execCtx, release, ok := w.acquire(ctx, timer, timerIDUnixKey)
+	if !ok {
+		return w.abandon(ctx, task, consts.Skipped, "previous run is still running, skipped by concurrency policy")
+	}
+	defer release()
+
 	execTime := time.Now()
-	resp, err := w.execute(ctx, timer)
+	result := w.execute(execCtx, timer)
 	// log.InfoContextf(ctx, "execute timer: %d, resp: %v, err: %v", timerID, resp, err)
-	return w.postProcess(ctx, resp, err, timer.App, timerID, unix, execTime)
+	if w.replaced(ctx, timer, timerIDUnixKey) {
+		return w.abandon(ctx, task, consts.Cancelled, "replaced by a newer run by concurrency policy")
+	}
+	status, err := w.postProcess(ctx, result, timer, unix, execTime)
+	if err != nil {
+		return err
+	}
+
+	// 一次性定时器执行完唯一的 task 后进入终态，重试中的 task 尚未结束，手动触发的 task 不影响定时器状态
+	if timer.Type == consts.OnceTimer && source != consts.ManualTrigger && status != consts.Retrying {
+		return w.timerService.FinishTimer(ctx, timerID)
+	}
+	return nil
 }
 
-func (w *Worker) execute(ctx context.Context, timer *vo.Timer) (map[string]interface{}, error) {
-	var (
-		resp map[string]interface{}
-		err  error
-	)
-	switch strings.ToUpper(timer.NotifyHTTPParam.Method) {
+// execResult 单次回调的执行结果
+type execResult struct {
+	body       []byte
+	statusCode int // 请求未得到响应时为 0
+	cost       time.Duration
+	err        error
+}
+
+// execute 执行回调，并按定时器的成功判定条件校验响应
+func (w *Worker) execute(ctx context.Context, timer *vo.Timer) *execResult {
+	var result execResult
+	start := time.Now()
+	method := strings.ToUpper(timer.NotifyHTTPParam.Method)
+	var req interface{}
+	switch method {
 	case nethttp.MethodGet:
-		err = w.httpClient.Get(ctx, timer.NotifyHTTPParam.URL, timer.NotifyHTTPParam.Header, nil, &resp)
-	case nethttp.MethodPatch:
-		err = w.httpClient.Patch(ctx, timer.NotifyHTTPParam.URL, timer.NotifyHTTPParam.Header, timer.NotifyHTTPParam.Body, &resp)
-	case nethttp.MethodDelete:
-		err = w.httpClient.Delete(ctx, timer.NotifyHTTPParam.URL, timer.NotifyHTTPParam.Header, timer.NotifyHTTPParam.Body, &resp)
-	case nethttp.MethodPost:
-		err = w.httpClient.Post(ctx, timer.NotifyHTTPParam.URL, timer.NotifyHTTPParam.Header, timer.NotifyHTTPParam.Body, &resp)
+	case nethttp.MethodPatch, nethttp.MethodDelete, nethttp.MethodPost:
+		req = timer.NotifyHTTPParam.Body
 	default:
-		err = fmt.Errorf("invalid http method: %s, timer: %s", timer.NotifyHTTPParam.Method, timer.Name)
+		result.err = fmt.Errorf("invalid http method: %s, timer: %s", timer.NotifyHTTPParam.Method, timer.Name)
+		return &result
+	}
+
+	// 先序列化请求体，保证签名内容与实际发送的内容一致
+	body, err := json.Marshal(req)
+	if err != nil {
+		result.err = err
+		return &result
+	}
+	header, err := w.signedHeader(ctx, timer, method, body)
+	if err != nil {
+		result.err = err
+		return &result
+	}
+
+	if timeout := timer.NotifyHTTPParam.TimeoutSeconds; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+		defer cancel()
+	}
+	result.statusCode, result.body, result.err = w.httpClient.DoRaw(ctx, method, timer.NotifyHTTPParam.URL, header, json.RawMessage(body))
+	result.cost = time.Since(start)
+
+	if result.err == nil {
+		result.err = timer.SuccessCriteria.Evaluate(result.statusCode, result.body)
 	}
 
 	fmt.Println("execute done")
-	return resp, err
+	return &result
 }
 
-func (w *Worker) postProcess(ctx context.Context, resp map[string]interface{}, execErr error, app string, timerID uint, unix int64, execTime time.Time) error {
+// signedHeader 在定时器配置的请求头基础上追加时间戳和签名请求头，app 未配置密钥时不签名
+func (w *Worker) signedHeader(ctx context.Context, timer *vo.Timer, method string, body []byte) (map[string]string, error) {
+	secrets, err := w.secretService.GetSecrets(ctx, timer.App)
+	if err != nil {
+		return nil, fmt.Errorf("get secrets of app: %s failed, err: %w", timer.App, err)
+	}
+
+	header := make(map[string]string, len(timer.NotifyHTTPParam.Header)+2)
+	for k, v := range timer.NotifyHTTPParam.Header {
+		header[k] = v
+	}
+	if len(secrets) == 0 {
+		return header, nil
+	}
+	for k, v := range signature.Headers(secrets, method, timer.NotifyHTTPParam.URL, time.Now().Unix(), body) {
+		header[k] = v
+	}
+	return header, nil
+}
+
+// postProcess 记录本次执行结果，回调失败且满足重试策略时将任务延迟投递，等待下一次执行. 返回 task 更新后的状态
+func (w *Worker) postProcess(ctx context.Context, result *execResult, timer *vo.Timer, unix int64, execTime time.Time) (consts.TaskStatus, error) {
+	app, timerID := timer.App, timer.ID
 	go w.reportMonitorData(app, unix, execTime)
 	if err := w.bloomFilter.Set(ctx, utils.GetTaskBloomFilterKey(utils.GetDayStr(time.UnixMilli(unix))), utils.UnionTimerIDUnix(timerID, unix), consts.BloomFilterKeyExpireSeconds); err != nil {
 		log.ErrorContextf(ctx, "set bloom filter failed, key: %s, err: %v", utils.GetTaskBloomFilterKey(utils.GetDayStr(time.UnixMilli(unix))), err)
@@ -129,20 +243,79 @@ func (w *Worker) postProcess(ctx context.Context, resp map[string]interface{}, e
 
 	task, err := w.taskDAO.GetTask(ctx, taskdao.WithTimerID(timerID), taskdao.WithRunTimer(time.UnixMilli(unix)))
 	if err != nil {
-		return fmt.Errorf("get task failed, timerID: %d, runTimer: %d, err: %w", timerID, time.UnixMilli(unix), err)
+		return 0, fmt.Errorf("get task failed, timerID: %d, runTimer: %v, err: %w", timerID, time.UnixMilli(unix), err)
 	}
 
-	respBody, _ := json.Marshal(resp)
-	task.Output = string(respBody)
+	task.Attempt++
+	task.HTTPStatus = result.statusCode
+	task.CostTime = int(result.cost.Milliseconds())
+	// output 与 task_output.body 均为 utf8mb4 列，二进制或非 utf-8 的响应体替换非法字节后再落库
+	body := strings.ToValidUTF8(string(result.body), "\uFFFD")
+	task.Output, task.Truncated = truncate(body, po.OutputExcerptLen)
+	task.FailReason = ""
+
+	status := consts.Successed
+	if result.err != nil {
+		status = consts.Failed
+		task.FailReason, _ = truncate(strings.ToValidUTF8(result.err.Error(), "\uFFFD"), failReasonMaxLen)
+		if timer.RetryPolicy.Retryable(task.Attempt, result.statusCode) {
+			status = consts.Retrying
+		}
+	}
+	task.Status = status.ToInt()
+	if err := w.saveOutput(ctx, task, body); err != nil {
+		log.ErrorContextf(ctx, "save task output failed, taskID: %d, err: %v", task.ID, err)
+	}
+	if err := w.taskDAO.UpdateTask(ctx, task); err != nil {
+		return status, err
+	}
 
-	if execErr != nil {
-		task.Status = consts.Failed.ToInt()
-	} else {
-		task.Status = consts.Successed.ToInt()
+	// 先落库重试状态再投递，保证重试消息被消费时能通过幂等校验
+	if status == consts.Retrying {
+		if err := w.Dispatch(ctx, timerID, unix, timer.RetryPolicy.Backoff(task.Attempt)); err != nil {
+			log.ErrorContextf(ctx, "retry task failed, timerID: %d, runTimer: %v, attempt: %d, err: %v", timerID, time.UnixMilli(unix), task.Attempt, err)
+			status = consts.Failed
+			task.Status = status.ToInt()
+			return status, w.taskDAO.UpdateTask(ctx, task)
+		}
 	}
 
 	fmt.Println("post done")
-	return w.taskDAO.UpdateTask(ctx, task)
+	return status, nil
+}
+
+// saveOutput 响应体被截断时单独保存完整内容，重试后不再截断则清理上一次执行遗留的内容
+func (w *Worker) saveOutput(ctx context.Context, task *po.Task, body string) error {
+	if task.Truncated {
+		return w.taskDAO.SaveTaskOutput(ctx, &po.TaskOutput{TaskID: task.ID, Body: body})
+	}
+	if task.Attempt > 1 {
+		return w.taskDAO.DeleteTaskOutput(ctx, task.ID)
+	}
+	return nil
+}
+
+// Dispatch 将任务延迟 delay 后重新投递到 trigger-topic，由 executor 再次消费执行. 用于失败重试和错过执行时机后的补偿
+func (w *Worker) Dispatch(ctx context.Context, timerID uint, unix int64, delay time.Duration) error {
+	if w.Producer == nil {
+		return errors.New("executor producer is not initialized")
+	}
+	_, err := w.Producer.Send(ctx, &pulsar.ProducerMessage{
+		Payload:      []byte(utils.UnionTimerIDUnix(timerID, unix)),
+		DeliverAfter: delay,
+	})
+	return err
+}
+
+// 与 task.fail_reason 列宽保持一致
+const failReasonMaxLen = 512
+
+// truncate 按字符截取 s 的前 maxLen 个字符，返回截取结果和是否发生了截断
+func truncate(s string, maxLen int) (string, bool) {
+	if r := []rune(s); len(r) > maxLen {
+		return string(r[:maxLen]), true
+	}
+	return s, false
 }
 
 func (w *Worker) reportMonitorData(app string, expectExecTimeUnix int64, acutalExecTime time.Time) {
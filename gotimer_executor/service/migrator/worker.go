@@ -7,7 +7,10 @@ import (
 
 	mconf "gotimer_executor/common/conf"
 	"gotimer_executor/common/consts"
+	"gotimer_executor/common/model/po"
+	"gotimer_executor/common/model/vo"
 	"gotimer_executor/common/utils"
+	calendardao "gotimer_executor/dao/calendar"
 	taskdao "gotimer_executor/dao/task"
 	timerdao "gotimer_executor/dao/timer"
 	"gotimer_executor/pkg/cron"
@@ -19,6 +22,7 @@ import (
 type Worker struct {
 	timerDAO          *timerdao.TimerDAO
 	taskDAO           *taskdao.TaskDAO
+	calendarDAO       *calendardao.CalendarDAO
 	taskCache         *taskdao.TaskCache
 	cronParser        *cron.CronParser
 	lockService       *redis.Client
@@ -26,12 +30,13 @@ type Worker struct {
 	pool              pool.WorkerPool
 }
 
-func NewWorker(timerDAO *timerdao.TimerDAO, taskDAO *taskdao.TaskDAO, taskCache *taskdao.TaskCache, lockService *redis.Client,
+func NewWorker(timerDAO *timerdao.TimerDAO, taskDAO *taskdao.TaskDAO, calendarDAO *calendardao.CalendarDAO, taskCache *taskdao.TaskCache, lockService *redis.Client,
 	cronParser *cron.CronParser, appConfigProvider *mconf.MigratorAppConfProvider) *Worker {
 	return &Worker{
 		pool:              pool.NewGoWorkerPool(appConfigProvider.Get().WorkersNum),
 		timerDAO:          timerDAO,
 		taskDAO:           taskDAO,
+		calendarDAO:       calendarDAO,
 		taskCache:         taskCache,
 		lockService:       lockService,
 		cronParser:        cronParser,
@@ -81,8 +86,18 @@ func (w *Worker) Migrate(ctx context.Context) error {
 	start, end := utils.GetStartHour(now.Add(time.Duration(conf.MigrateStepMinutes)*time.Minute)), utils.GetStartHour(now.Add(2*time.Duration(conf.MigrateStepMinutes)*time.Minute))
 	// 迁移可以慢慢来，不着急
 	for _, timer := range timers {
-		nexts, _ := w.cronParser.NextsBetween(timer.Cron, start, end)
-		if err := w.timerDAO.BatchCreateRecords(ctx, timer.BatchTasksFromTimer(nexts)); err != nil {
+		nexts, err := w.getExecuteTimes(timer, start, end)
+		if err != nil {
+			log.ErrorContextf(ctx, "migrator get execute times for timer: %d failed, err: %v", timer.ID, err)
+			continue
+		}
+		// 日历过滤失败时跳过该定时器，由下一轮迁移重试，避免节假日等被排除的时机被触发
+		tasks, err := w.batchTasks(ctx, timer, nexts)
+		if err != nil {
+			log.ErrorContextf(ctx, "migrator batch tasks for timer: %d failed, err: %v", timer.ID, err)
+			continue
+		}
+		if err := w.timerDAO.BatchCreateRecords(ctx, tasks); err != nil {
 			log.ErrorContextf(ctx, "migrator batch create records for timer: %d failed, err: %v", timer.ID, err)
 		}
 		time.Sleep(5 * time.Second)
@@ -112,9 +127,19 @@ func (w *Worker) MigrateNow(ctx context.Context) error {
 	fmt.Println("end time = ", end)
 	// 迁移可以慢慢来，不着急
 	for _, timer := range timers {
-		nexts, _ := w.cronParser.NextsBetween(timer.Cron, start, end)
+		nexts, err := w.getExecuteTimes(timer, start, end)
+		if err != nil {
+			log.ErrorContextf(ctx, "migrator get execute times for timer: %d failed, err: %v", timer.ID, err)
+			continue
+		}
 		fmt.Println("nexts = ", nexts)
-		if err := w.timerDAO.BatchCreateRecords(ctx, timer.BatchTasksFromTimer(nexts)); err != nil {
+		// 日历过滤失败时跳过该定时器，由下一轮迁移重试，避免节假日等被排除的时机被触发
+		tasks, err := w.batchTasks(ctx, timer, nexts)
+		if err != nil {
+			log.ErrorContextf(ctx, "migrator batch tasks for timer: %d failed, err: %v", timer.ID, err)
+			continue
+		}
+		if err := w.timerDAO.BatchCreateRecords(ctx, tasks); err != nil {
 			log.ErrorContextf(ctx, "migrator batch create records for timer: %d failed, err: %v", timer.ID, err)
 		}
 		time.Sleep(5 * time.Second)
@@ -138,10 +163,46 @@ func (w *Worker) MigrateNow(ctx context.Context) error {
 // 	return w.taskCache.BatchCreateBucket(ctx, cntByMins, end)
 // }
 
+// 一次性定时器只在 runAt 时刻执行，cron 定时器按所属时区由 cron 表达式推算
+func (w *Worker) getExecuteTimes(timer *po.Timer, start, end time.Time) ([]time.Time, error) {
+	if timer.IsOnce() {
+		return timer.RunAtBetween(start, end), nil
+	}
+
+	loc, err := timer.Location()
+	if err != nil {
+		return nil, err
+	}
+	// cron 中的 H 按定时器 id 哈希为固定取值
+	expr, err := w.cronParser.ResolveHash(timer.Cron, uint64(timer.ID))
+	if err != nil {
+		return nil, err
+	}
+	nexts, err := w.cronParser.NextsBetweenIn(expr, loc, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return timer.JitteredTimes(nexts), nil
+}
+
+// batchTasks 由执行时机生成 task，命中日历过滤的 task 置为已跳过并记录原因. 日历读取失败时返回错误，不生成未经过滤的 task
+func (w *Worker) batchTasks(ctx context.Context, timer *po.Timer, nexts []time.Time) ([]*po.Task, error) {
+	tasks := timer.BatchTasksFromTimer(nexts)
+	calendars, err := w.calendarDAO.GetTimerCalendars(ctx, timer)
+	if err != nil {
+		return nil, fmt.Errorf("get calendars failed, err: %w", err)
+	}
+	if err := vo.ApplyCalendars(timer, tasks, calendars); err != nil {
+		return nil, fmt.Errorf("apply calendars failed, err: %w", err)
+	}
+	return tasks, nil
+}
+
 func (w *Worker) migrateToCache(ctx context.Context, start, end time.Time) error {
 	// 迁移完成后，将所有添加的 task 取出，添加到 redis 当中
 	fmt.Println("migrateToCache")
-	tasks, err := w.taskDAO.GetTasks(ctx, taskdao.WithStartTime(start), taskdao.WithEndTime(end))
+	// 被日历过滤的 task 不需要触发
+	tasks, err := w.taskDAO.GetTasks(ctx, taskdao.WithStartTime(start), taskdao.WithEndTime(end), taskdao.WithStatus(int32(consts.NotRunned)))
 	if err != nil {
 		log.ErrorContextf(ctx, "migrator batch get tasks failed, err: %v", err)
 		return err
@@ -0,0 +1,212 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"gotimer_executor/common/conf"
+	"gotimer_executor/common/consts"
+	"gotimer_executor/common/model/po"
+	"gotimer_executor/common/model/vo"
+	"gotimer_executor/common/utils"
+	taskdao "gotimer_executor/dao/task"
+	"gotimer_executor/pkg/log"
+	"gotimer_executor/pkg/redis"
+	"gotimer_executor/service/executor"
+)
+
+// 补偿时每批从库中读取的 task 个数
+const reconcileBatchSize = 500
+
+// Worker 定期扫描超时仍未执行的 task，按定时器的 misfire 策略补偿投递或记为错过.
+// scheduler 只回看一分钟的时间片，trigger/executor 故障期间的 task 依赖这里兜底
+type Worker struct {
+	timerService timerService
+	taskDAO      taskDAO
+	dispatcher   dispatcher
+	lockService  lockService
+	confProvider *conf.ReconcilerAppConfProvider
+}
+
+func NewWorker(timerService *executor.TimerService, taskDAO *taskdao.TaskDAO, dispatcher *executor.Worker, lockService *redis.Client,
+	confProvider *conf.ReconcilerAppConfProvider) *Worker {
+	return &Worker{
+		timerService: timerService,
+		taskDAO:      taskDAO,
+		dispatcher:   dispatcher,
+		lockService:  lockService,
+		confProvider: confProvider,
+	}
+}
+
+func (w *Worker) Start(ctx context.Context) error {
+	conf := w.confProvider.Get()
+	ticker := time.NewTicker(time.Duration(conf.ReconcileGapSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		// 多个 executor 副本中同一时刻只有一个执行补偿
+		locker := w.lockService.GetDistributionLock(utils.GetReconcilerLockKey(time.Now()))
+		if err := locker.Lock(ctx, int64(conf.ReconcileGapSeconds)); err != nil {
+			continue
+		}
+
+		if err := w.Reconcile(ctx); err != nil {
+			log.ErrorContextf(ctx, "reconcile misfired tasks failed, err: %v", err)
+		}
+	}
+	return nil
+}
+
+// Reconcile 处理 [now-lookback, now-threshold) 内仍处于未执行状态的 task.
+// 按 id 分批读取，同一定时器的 task 可能分布在不同批次，全部读取后再按定时器处理
+func (w *Worker) Reconcile(ctx context.Context) error {
+	conf := w.confProvider.Get()
+	now := time.Now()
+	opts := []taskdao.Option{
+		taskdao.WithStartTime(now.Add(-time.Duration(conf.LookbackHours) * time.Hour)),
+		taskdao.WithEndTime(now.Add(-time.Duration(conf.MisfireThresholdSeconds) * time.Second)),
+		taskdao.WithStatus(int32(consts.NotRunned.ToInt())),
+	}
+
+	timerTasks := make(map[uint][]*po.Task)
+	var lastID uint
+	for {
+		tasks, err := w.taskDAO.GetTasks(ctx, append(opts, taskdao.WithIDAfter(lastID), taskdao.WithIDAsc(), taskdao.WithPageLimit(0, reconcileBatchSize))...)
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			timerTasks[task.TimerID] = append(timerTasks[task.TimerID], task)
+		}
+		if len(tasks) < reconcileBatchSize {
+			break
+		}
+		lastID = tasks[len(tasks)-1].ID
+	}
+
+	for timerID, overdue := range timerTasks {
+		if err := w.reconcileTimer(ctx, timerID, overdue); err != nil {
+			log.ErrorContextf(ctx, "reconcile misfired tasks of timer: %d failed, err: %v", timerID, err)
+		}
+	}
+	return nil
+}
+
+func (w *Worker) reconcileTimer(ctx context.Context, timerID uint, tasks []*po.Task) error {
+	timer, err := w.timerService.GetTimer(ctx, timerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// 定时器已被删除，取消其遗留的 task，避免每轮扫描重复处理
+		if err := w.taskDAO.MarkCancelled(ctx, taskIDs(tasks), "timer has been deleted"); err != nil {
+			return err
+		}
+		log.WarnContextf(ctx, "timer: %d has been deleted, cancelled %d overdue tasks", timerID, len(tasks))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// 手动触发的 task 是明确的执行请求，不受 misfire 策略约束
+	var scheduled []*po.Task
+	for _, task := range tasks {
+		if task.Source == consts.ManualTrigger.ToInt() {
+			w.dispatch(ctx, task)
+			continue
+		}
+		scheduled = append(scheduled, task)
+	}
+
+	if len(scheduled) == 0 {
+		return nil
+	}
+	// 去激活的定时器不再补偿，遗留的 task 记为已取消，避免每轮扫描重复处理
+	if timer.Status != consts.Enabled {
+		if err := w.taskDAO.MarkCancelled(ctx, taskIDs(scheduled), "timer is not enabled"); err != nil {
+			return err
+		}
+		log.WarnContextf(ctx, "timer: %d is not enabled, cancelled %d overdue tasks", timerID, len(scheduled))
+		return nil
+	}
+
+	fire, missed := splitByPolicy(timer, scheduled)
+	if len(missed) > 0 {
+		if err := w.taskDAO.MarkMissed(ctx, taskIDs(missed)); err != nil {
+			return err
+		}
+		log.WarnContextf(ctx, "timer: %d missed %d tasks, misfire policy: %d", timerID, len(missed), timer.MisfirePolicy)
+	}
+
+	for _, task := range fire {
+		w.dispatch(ctx, task)
+	}
+	return nil
+}
+
+// splitByPolicy 按 misfire 策略划分需要补偿执行和记为错过的 task
+func splitByPolicy(timer *vo.Timer, tasks []*po.Task) (fire, missed []*po.Task) {
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].RunTimer.Before(tasks[j].RunTimer)
+	})
+
+	switch timer.MisfirePolicy {
+	case consts.MisfireFireAll:
+		return tasks, nil
+	case consts.MisfireSkip:
+		return nil, tasks
+	default:
+		return tasks[len(tasks)-1:], tasks[:len(tasks)-1]
+	}
+}
+
+func taskIDs(tasks []*po.Task) []uint {
+	ids := make([]uint, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+// dispatch 补偿投递 task. 投递后到 executor 执行完成前 task 仍为未执行状态，通过锁避免下一轮重复投递
+func (w *Worker) dispatch(ctx context.Context, task *po.Task) {
+	unix := task.RunTimer.UnixMilli()
+	locker := w.lockService.GetDistributionLock(utils.GetMisfireDispatchLockKey(utils.UnionTimerIDUnix(task.TimerID, unix)))
+	if err := locker.Lock(ctx, int64(w.confProvider.Get().DispatchLockSeconds)); err != nil {
+		return
+	}
+
+	if err := w.dispatcher.Dispatch(ctx, task.TimerID, unix, 0); err != nil {
+		log.ErrorContextf(ctx, "dispatch misfired task failed, timerID: %d, runTimer: %v, err: %v", task.TimerID, task.RunTimer, err)
+		_ = locker.Unlock(ctx)
+		return
+	}
+	log.InfoContextf(ctx, "dispatch misfired task, timerID: %d, runTimer: %v", task.TimerID, task.RunTimer)
+}
+
+type timerService interface {
+	GetTimer(ctx context.Context, id uint) (*vo.Timer, error)
+}
+
+type taskDAO interface {
+	GetTasks(ctx context.Context, opts ...taskdao.Option) ([]*po.Task, error)
+	MarkMissed(ctx context.Context, ids []uint) error
+	MarkCancelled(ctx context.Context, ids []uint, reason string) error
+}
+
+type dispatcher interface {
+	Dispatch(ctx context.Context, timerID uint, unix int64, delay time.Duration) error
+}
+
+type lockService interface {
+	GetDistributionLock(key string) redis.DistributeLocker
+}
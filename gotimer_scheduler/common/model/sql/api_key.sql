@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS `api_key`
+(
+    `id`         bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `app`        varchar(255) NOT NULL DEFAULT '' COMMENT '所属应用名，管理员可为空',
+    `name`       varchar(255) NOT NULL COMMENT '凭证用途说明',
+    `key_hash`   char(64)     NOT NULL COMMENT '凭证的 sha256 哈希',
+    `role`       smallint(255) NOT NULL COMMENT '角色 1app 2管理员',
+    `created_at` datetime     NOT NULL COMMENT '创建时间',
+    `updated_at` datetime     NOT NULL ON UPDATE CURRENT_TIMESTAMP COMMENT '修改时间',
+    `deleted_at` datetime     DEFAULT NULL COMMENT '删除时间',
+    PRIMARY KEY (`id`) USING BTREE COMMENT '主键索引',
+    UNIQUE KEY `uni_key_hash` (`key_hash`) USING BTREE COMMENT '凭证哈希索引',
+    KEY `idx_app` (`app`) COMMENT '应用名索引'
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4;
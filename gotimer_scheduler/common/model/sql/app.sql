@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS `app`
+(
+    `id`                        bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `name`                      varchar(255) NOT NULL COMMENT '应用名',
+    `owner`                     varchar(255) NOT NULL COMMENT '负责人',
+    `contact`                   varchar(255) NOT NULL DEFAULT '' COMMENT '联系方式',
+    `default_headers`           text                  DEFAULT NULL COMMENT '回调默认请求头',
+    `default_timeout_seconds`   int(11)      NOT NULL DEFAULT 0 COMMENT '回调默认超时时间，单位秒',
+    `max_timers`                int(11)      NOT NULL DEFAULT 0 COMMENT '定时器数量上限，0 不限制',
+    `max_enabled_timers`        int(11)      NOT NULL DEFAULT 0 COMMENT '激活态定时器数量上限，0 不限制',
+    `min_cron_interval_seconds` int(11)      NOT NULL DEFAULT 0 COMMENT 'cron 最小触发间隔，单位秒，0 不限制',
+    `created_at`                datetime     NOT NULL COMMENT '创建时间',
+    `updated_at`                datetime     NOT NULL ON UPDATE CURRENT_TIMESTAMP COMMENT '修改时间',
+    `deleted_at`                datetime     DEFAULT NULL COMMENT '删除时间',
+    PRIMARY KEY (`id`) USING BTREE COMMENT '主键索引',
+    UNIQUE KEY `uni_name` (`name`) USING BTREE COMMENT '应用名索引'
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS `audit_log`
+(
+    `id`         bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `app`        varchar(255) NOT NULL COMMENT '定时器所属应用名',
+    `timer_id`   bigint(20) unsigned NOT NULL COMMENT '定时器ID',
+    `actor`      varchar(255) NOT NULL COMMENT '操作人',
+    `action`     varchar(32)  NOT NULL COMMENT '操作类型 create update delete enable disable rollback',
+    `diff`       json         DEFAULT NULL COMMENT '变更前后有差异的字段',
+    `created_at` datetime     NOT NULL COMMENT '操作时间',
+    PRIMARY KEY (`id`) USING BTREE COMMENT '主键索引',
+    KEY `idx_app_created_at` (`app`,`created_at`) COMMENT '应用名 操作时间索引',
+    KEY `idx_timer_id_created_at` (`timer_id`,`created_at`) COMMENT '定时器ID 操作时间索引'
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4;
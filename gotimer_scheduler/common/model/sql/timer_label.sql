@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS `timer_label`
+(
+    `id`          bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `app`         varchar(255) NOT NULL COMMENT '定时器所属应用名',
+    `timer_id`    bigint(20) unsigned NOT NULL COMMENT '定时器ID',
+    `label_key`   varchar(63)  NOT NULL COMMENT '标签键',
+    `label_value` varchar(63)  NOT NULL DEFAULT '' COMMENT '标签值',
+    PRIMARY KEY (`id`) USING BTREE COMMENT '主键索引',
+    UNIQUE KEY `uni_timer_key` (`timer_id`,`label_key`) USING BTREE COMMENT '定时器ID 标签键索引',
+    KEY `idx_app_key_value` (`app`,`label_key`,`label_value`) COMMENT '应用名 标签键值索引'
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4;
\ No newline at end of file
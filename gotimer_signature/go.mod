@@ -0,0 +1,3 @@
+module gotimer_signature
+
+go 1.22
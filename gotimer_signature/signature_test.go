@@ -0,0 +1,58 @@
+package signature
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1712345678, 0)
+	body := []byte(`{"hello":"world"}`)
+	url := "http://callee.example.com/hook?x=1"
+	headers := Headers([]string{"new-secret", "old-secret"}, "post", url, now.Unix(), body)
+
+	tests := []struct {
+		name    string
+		secrets []string
+		method  string
+		body    []byte
+		now     time.Time
+		wantErr error
+	}{
+		{"new secret", []string{"new-secret"}, "POST", body, now, nil},
+		{"old secret during rotation", []string{"old-secret"}, "POST", body, now, nil},
+		{"unknown secret", []string{"other"}, "POST", body, now, ErrSignatureMismatch},
+		{"tampered body", []string{"new-secret"}, "POST", []byte(`{}`), now, ErrSignatureMismatch},
+		{"tampered method", []string{"new-secret"}, "GET", body, now, ErrSignatureMismatch},
+		{"replayed", []string{"new-secret"}, "POST", body, now.Add(6 * time.Minute), ErrExpired},
+		{"no secret", nil, "POST", body, now, ErrNoValidSecret},
+	}
+	for _, tt := range tests {
+		err := Verify(tt.secrets, tt.method, url, headers[TimestampHeader], headers[SignatureHeader], tt.body, 0, tt.now)
+		if err != tt.wantErr {
+			t.Errorf("%s: expect %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestVerifyRequest(t *testing.T) {
+	body := []byte(`{"a":1}`)
+	url := "http://callee.example.com/hook"
+	r, _ := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	for k, v := range Headers([]string{"secret"}, http.MethodPost, url, time.Now().Unix(), body) {
+		r.Header.Set(k, v)
+	}
+
+	if err := VerifyRequest(r, []string{"secret"}, url, 0); err != nil {
+		t.Fatal(err)
+	}
+	// body 可被再次读取
+	if err := VerifyRequest(r, []string{"secret"}, url, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyRequest(r, []string{"secret"}, "http://proxy/hook", 0); err != ErrSignatureMismatch {
+		t.Errorf("expect mismatch for different url, got %v", err)
+	}
+}
@@ -21,12 +21,78 @@ func (t TimerStatus) ToInt() int {
 	return int(t)
 }
 
+type TimerType int
+
+func (t TimerType) ToInt() int {
+	return int(t)
+}
+
 const (
 	NotRunned TaskStatus = 0
 	Running   TaskStatus = 1
 	Successed TaskStatus = 2
 	Failed    TaskStatus = 3
+	// 定时器去激活或删除后，尚未执行的 task 被取消
+	Cancelled TaskStatus = 4
+	// 执行失败，等待按重试策略再次执行
+	Retrying TaskStatus = 5
+	// 错过执行时机，按定时器的 misfire 策略不再补偿执行
+	Missed TaskStatus = 6
+	// 上一次执行尚未结束，按定时器的并发策略跳过本次执行
+	Skipped TaskStatus = 7
 
 	Unabled TimerStatus = 1
 	Enabled TimerStatus = 2
+	// 一次性定时器的 task 执行完毕后进入终态
+	Finished TimerStatus = 3
+
+	// 按 cron 表达式周期执行
+	CronTimer TimerType = 1
+	// 在 runAt 时刻执行一次
+	OnceTimer TimerType = 2
+)
+
+type TriggerSource int
+
+func (t TriggerSource) ToInt() int {
+	return int(t)
+}
+
+const (
+	// 按 cron/runAt 调度产生的执行
+	ScheduledTrigger TriggerSource = 1
+	// 通过接口手动触发的执行
+	ManualTrigger TriggerSource = 2
+)
+
+// MisfirePolicy 因 trigger/executor 故障等原因错过执行时机后的补偿策略
+type MisfirePolicy int
+
+func (m MisfirePolicy) ToInt() int {
+	return int(m)
+}
+
+const (
+	// 只补偿执行最近一次错过的 task，其余记为错过（默认）
+	MisfireFireOnce MisfirePolicy = 1
+	// 补偿执行全部错过的 task
+	MisfireFireAll MisfirePolicy = 2
+	// 不补偿执行，全部记为错过
+	MisfireSkip MisfirePolicy = 3
+)
+
+// ConcurrencyPolicy 同一定时器上一次执行尚未结束时，新一次执行的处理策略
+type ConcurrencyPolicy int
+
+func (c ConcurrencyPolicy) ToInt() int {
+	return int(c)
+}
+
+const (
+	// 允许并发执行（默认）
+	ConcurrencyAllow ConcurrencyPolicy = 1
+	// 跳过新一次执行
+	ConcurrencyForbid ConcurrencyPolicy = 2
+	// 中止上一次执行，以新一次执行替代
+	ConcurrencyReplace ConcurrencyPolicy = 3
 )
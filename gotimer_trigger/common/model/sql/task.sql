@@ -3,10 +3,16 @@ CREATE TABLE IF NOT EXISTS `task`
     `id`         bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
     `app`        varchar(255) NOT NULL COMMENT '应用名',
     `timer_id`   bigint(20) NOT NULL COMMENT '定时器ID',
-    `output`     varchar(256) DEFAULT NULL COMMENT '执行结果',
-    `run_timer`  datetime     NOT NULL COMMENT '执行时间',
-    `cost_time`  int(8) DEFAULT NULL COMMENT '执行耗时',
-    `status`     int(4) NOT NULL COMMENT '当前状态',
+    `output`     varchar(256) DEFAULT NULL COMMENT '执行结果，响应体摘要',
+    `run_timer`  datetime(3)  NOT NULL COMMENT '执行时间，调度触发为整秒，手动触发精确到毫秒',
+    `cost_time`  int(8) DEFAULT NULL COMMENT '执行耗时，单位：ms',
+    `status`     int(4) NOT NULL COMMENT '当前状态 0未执行 1执行中 2成功 3失败 4已取消 5重试中 6已错过 7已跳过',
+    `attempt`    int(8) NOT NULL DEFAULT 0 COMMENT '已执行次数，包含重试',
+    `fail_reason` varchar(512) DEFAULT NULL COMMENT '最近一次执行的错误信息',
+    `http_status` int(4) NOT NULL DEFAULT 0 COMMENT '最近一次回调的 http 状态码',
+    `output_truncated` tinyint(1) NOT NULL DEFAULT 0 COMMENT '执行结果是否被截断',
+    `source`     tinyint(4) NOT NULL DEFAULT 1 COMMENT '触发来源 1调度触发 2手动触发',
+    `timer_version` int(11) NOT NULL DEFAULT 0 COMMENT '生成该 task 的定时器版本 0为版本记录上线前生成',
     `created_at` datetime     NOT NULL COMMENT '创建时间',
     `updated_at` datetime     NOT NULL ON UPDATE CURRENT_TIMESTAMP COMMENT '修改时间',
     `deleted_at` datetime     DEFAULT NULL COMMENT '删除时间',
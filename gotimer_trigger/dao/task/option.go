@@ -44,6 +44,12 @@ func WithStatus(status int32) Option {
 	}
 }
 
+func WithSource(source int32) Option {
+	return func(d *gorm.DB) *gorm.DB {
+		return d.Where("source = ?", source)
+	}
+}
+
 func WithStatuses(statuses []int32) Option {
 	return func(d *gorm.DB) *gorm.DB {
 		return d.Where("status IN ?", statuses)
@@ -31,8 +31,9 @@ func (t *TaskService) GetTasksByTime(ctx context.Context, key string, bucket int
 		return vo.NewTasks(tasks), nil
 	}
 
-	// 倘若缓存 miss 再走 db
-	tasks, err := t.dao.GetTasks(ctx, dao.WithStartTime(start), dao.WithEndTime(end), dao.WithStatus(int32(consts.NotRunned.ToInt())))
+	// 倘若缓存 miss 再走 db，手动触发的 task 已直接投递，不再重复触发
+	tasks, err := t.dao.GetTasks(ctx, dao.WithStartTime(start), dao.WithEndTime(end), dao.WithStatus(int32(consts.NotRunned.ToInt())),
+		dao.WithSource(int32(consts.ScheduledTrigger.ToInt())))
 	if err != nil {
 		return nil, err
 	}
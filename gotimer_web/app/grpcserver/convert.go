@@ -0,0 +1,188 @@
+package grpcserver
+
+import (
+	"encoding/json"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gotimer_web/common/consts"
+	"gotimer_web/common/model/vo"
+	"gotimer_web/pb"
+)
+
+func toVOTimer(timer *pb.Timer) *vo.Timer {
+	vTimer := vo.Timer{
+		ID:                uint(timer.GetId()),
+		App:               timer.GetApp(),
+		Name:              timer.GetName(),
+		Type:              consts.TimerType(timer.GetType()),
+		Cron:              timer.GetCron(),
+		Timezone:          timer.GetTimezone(),
+		MisfirePolicy:     consts.MisfirePolicy(timer.GetMisfirePolicy()),
+		ConcurrencyPolicy: consts.ConcurrencyPolicy(timer.GetConcurrencyPolicy()),
+		IncludeCalendars:  timer.GetIncludeCalendars(),
+		ExcludeCalendars:  timer.GetExcludeCalendars(),
+		JitterSeconds:     int(timer.GetJitterSeconds()),
+		Labels:            timer.GetLabels(),
+	}
+	if timer.RunAt != nil {
+		runAt := timer.RunAt.AsTime()
+		vTimer.RunAt = &runAt
+	}
+	if param := timer.GetNotifyHttpParam(); param != nil {
+		vTimer.NotifyHTTPParam = &vo.NotifyHTTPParam{
+			Method:         param.GetMethod(),
+			URL:            param.GetUrl(),
+			Header:         param.GetHeader(),
+			Body:           param.GetBody(),
+			TimeoutSeconds: int(param.GetTimeoutSeconds()),
+		}
+	}
+	if policy := timer.GetRetryPolicy(); policy != nil {
+		vTimer.RetryPolicy = &vo.RetryPolicy{
+			MaxAttempts:           int(policy.GetMaxAttempts()),
+			InitialBackoffSeconds: int(policy.GetInitialBackoffSeconds()),
+			Multiplier:            policy.GetMultiplier(),
+		}
+		for _, code := range policy.GetRetryableStatusCodes() {
+			vTimer.RetryPolicy.RetryableStatusCodes = append(vTimer.RetryPolicy.RetryableStatusCodes, int(code))
+		}
+	}
+	if criteria := timer.GetSuccessCriteria(); criteria != nil {
+		vTimer.SuccessCriteria = &vo.SuccessCriteria{Assertion: criteria.GetAssertion()}
+		for _, r := range criteria.GetStatusRanges() {
+			vTimer.SuccessCriteria.StatusRanges = append(vTimer.SuccessCriteria.StatusRanges, vo.StatusRange{Min: int(r.GetMin()), Max: int(r.GetMax())})
+		}
+	}
+	return &vTimer
+}
+
+func toPBTimer(timer *vo.Timer) *pb.Timer {
+	pTimer := pb.Timer{
+		Id:                uint64(timer.ID),
+		App:               timer.App,
+		Name:              timer.Name,
+		Status:            int32(timer.Status),
+		Type:              int32(timer.Type),
+		Cron:              timer.Cron,
+		Timezone:          timer.Timezone,
+		MisfirePolicy:     int32(timer.MisfirePolicy),
+		ConcurrencyPolicy: int32(timer.ConcurrencyPolicy),
+		IncludeCalendars:  timer.IncludeCalendars,
+		ExcludeCalendars:  timer.ExcludeCalendars,
+		JitterSeconds:     int32(timer.JitterSeconds),
+		Version:           int32(timer.Version),
+		Labels:            timer.Labels,
+	}
+	if timer.RunAt != nil {
+		pTimer.RunAt = timestamppb.New(*timer.RunAt)
+	}
+	if param := timer.NotifyHTTPParam; param != nil {
+		pTimer.NotifyHttpParam = &pb.NotifyHTTPParam{
+			Method:         param.Method,
+			Url:            param.URL,
+			Header:         param.Header,
+			Body:           param.Body,
+			TimeoutSeconds: int32(param.TimeoutSeconds),
+		}
+	}
+	if policy := timer.RetryPolicy; policy != nil {
+		pTimer.RetryPolicy = &pb.RetryPolicy{
+			MaxAttempts:           int32(policy.MaxAttempts),
+			InitialBackoffSeconds: int32(policy.InitialBackoffSeconds),
+			Multiplier:            policy.Multiplier,
+		}
+		for _, code := range policy.RetryableStatusCodes {
+			pTimer.RetryPolicy.RetryableStatusCodes = append(pTimer.RetryPolicy.RetryableStatusCodes, int32(code))
+		}
+	}
+	if criteria := timer.SuccessCriteria; criteria != nil {
+		pTimer.SuccessCriteria = &pb.SuccessCriteria{Assertion: criteria.Assertion}
+		for _, r := range criteria.StatusRanges {
+			pTimer.SuccessCriteria.StatusRanges = append(pTimer.SuccessCriteria.StatusRanges, &pb.StatusRange{Min: int32(r.Min), Max: int32(r.Max)})
+		}
+	}
+	return &pTimer
+}
+
+func toPBTimers(timers []*vo.Timer) []*pb.Timer {
+	pTimers := make([]*pb.Timer, 0, len(timers))
+	for _, timer := range timers {
+		pTimers = append(pTimers, toPBTimer(timer))
+	}
+	return pTimers
+}
+
+func toPBTask(task *vo.Task) *pb.Task {
+	return &pb.Task{
+		Id:           uint64(task.ID),
+		App:          task.App,
+		TimerId:      uint64(task.TimerID),
+		Output:       task.Output,
+		RunTimer:     timestamppb.New(task.RunTimer),
+		CostTime:     int32(task.CostTime),
+		Status:       int32(task.Status),
+		Attempt:      int32(task.Attempt),
+		FailReason:   task.FailReason,
+		HttpStatus:   int32(task.HTTPStatus),
+		Truncated:    task.Truncated,
+		Source:       int32(task.Source),
+		TimerVersion: int32(task.TimerVersion),
+		FullOutput:   task.FullOutput,
+	}
+}
+
+func toPBTimerVersion(version *vo.TimerVersion) *pb.TimerVersion {
+	return &pb.TimerVersion{
+		Version:   int32(version.Version),
+		Actor:     version.Actor,
+		Snapshot:  string(version.Snapshot),
+		CreatedAt: timestamppb.New(version.CreatedAt),
+	}
+}
+
+func toPBTimerVersionDiff(diff *vo.TimerVersionDiff) *pb.TimerVersionDiff {
+	pDiff := pb.TimerVersionDiff{
+		From: int32(diff.From),
+		To:   int32(diff.To),
+		Diff: make(map[string]*pb.FieldDiff, len(diff.Diff)),
+	}
+	for field, d := range diff.Diff {
+		pDiff.Diff[field] = &pb.FieldDiff{
+			Before: jsonValue(d.Before),
+			After:  jsonValue(d.After),
+		}
+	}
+	return &pDiff
+}
+
+// jsonValue 字段取值以 json 表示，字段不存在时为空串
+func jsonValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	body, _ := json.Marshal(v)
+	return string(body)
+}
+
+func toPBBulkTimersReport(report *vo.BulkTimersReport) *pb.BulkTimersReport {
+	pReport := pb.BulkTimersReport{
+		Action:    string(report.Action),
+		DryRun:    report.DryRun,
+		Matched:   int32(report.Matched),
+		Planned:   int32(report.Planned),
+		Succeeded: int32(report.Succeeded),
+		Skipped:   int32(report.Skipped),
+		Failed:    int32(report.Failed),
+		Results:   make([]*pb.BulkTimerResult, 0, len(report.Results)),
+	}
+	for _, result := range report.Results {
+		pReport.Results = append(pReport.Results, &pb.BulkTimerResult{
+			Id:     uint64(result.ID),
+			App:    result.App,
+			Name:   result.Name,
+			Result: string(result.Result),
+			Reason: result.Reason,
+		})
+	}
+	return &pReport
+}
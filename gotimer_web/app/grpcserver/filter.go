@@ -0,0 +1,74 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"gotimer_web/common/consts"
+	"gotimer_web/common/model/vo"
+)
+
+type authenticator interface {
+	Enabled() bool
+	Authenticate(ctx context.Context, token string) (*vo.Principal, error)
+}
+
+// AuthInterceptor 与 http 接口一致，校验 authorization: Bearer <key> 或 x-api-key 元数据，并将调用方身份写入 ctx
+func AuthInterceptor(auth authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !auth.Enabled() {
+			return handler(vo.WithPrincipal(ctx, &vo.Principal{Role: consts.AdminRole, KeyName: "anonymous"}), req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		token := firstValue(md, "x-api-key")
+		if bearer := firstValue(md, "authorization"); token == "" && strings.HasPrefix(bearer, "Bearer ") {
+			token = strings.TrimSpace(strings.TrimPrefix(bearer, "Bearer "))
+		}
+
+		principal, err := auth.Authenticate(ctx, token)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return handler(vo.WithPrincipal(ctx, principal), req)
+	}
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// checkApp 校验调用方是否有权操作 app
+func checkApp(ctx context.Context, app string) error {
+	if getPrincipal(ctx).CanAccess(app) {
+		return nil
+	}
+	return status.Errorf(codes.PermissionDenied, "no permission to access app: %s", app)
+}
+
+func getPrincipal(ctx context.Context) *vo.Principal {
+	if principal := vo.PrincipalFromContext(ctx); principal != nil {
+		return principal
+	}
+	return &vo.Principal{}
+}
+
+// toStatus 将 service 层的错误转换为 gRPC 状态，记录不存在时为 NotFound，其余为 Unknown
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return status.Error(codes.Unknown, err.Error())
+}
@@ -0,0 +1,57 @@
+package grpcserver
+
+import (
+	"fmt"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+	"gotimer_web/common/conf"
+	"gotimer_web/pb"
+	"gotimer_web/pkg/log"
+	service "gotimer_web/service/webserver"
+)
+
+// Server 与 http 服务并行运行的 gRPC 服务，复用同一套 service 层
+type Server struct {
+	sync.Once
+	server *grpc.Server
+
+	confProvider *conf.WebServerAppConfProvider
+}
+
+func NewServer(timer *service.TimerService, task *service.TaskService, auth *service.AuthService, confProvider *conf.WebServerAppConfProvider) *Server {
+	s := Server{
+		server:       grpc.NewServer(grpc.UnaryInterceptor(AuthInterceptor(auth))),
+		confProvider: confProvider,
+	}
+	pb.RegisterTimerServiceServer(s.server, NewTimerServer(timer))
+	pb.RegisterTaskServiceServer(s.server, NewTaskServer(task))
+	return &s
+}
+
+func (s *Server) Start() {
+	s.Do(s.start)
+}
+
+// 端口配置为 0 时不启动 gRPC 服务
+func (s *Server) start() {
+	port := s.confProvider.Get().GRPCPort
+	if port <= 0 {
+		return
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		panic(err)
+	}
+	go func() {
+		if err := s.server.Serve(lis); err != nil {
+			log.Errorf("grpc server stopped, err: %v", err)
+		}
+	}()
+}
+
+func (s *Server) Stop() {
+	s.server.GracefulStop()
+}
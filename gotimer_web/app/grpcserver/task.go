@@ -0,0 +1,53 @@
+package grpcserver
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotimer_web/common/model/vo"
+	"gotimer_web/pb"
+	service "gotimer_web/service/webserver"
+)
+
+type taskService interface {
+	GetTasks(ctx context.Context, req *vo.GetTasksReq) ([]*vo.Task, int64, error)
+}
+
+type TaskServer struct {
+	pb.UnimplementedTaskServiceServer
+	service taskService
+}
+
+func NewTaskServer(service *service.TaskService) *TaskServer {
+	return &TaskServer{
+		service: service,
+	}
+}
+
+func (t *TaskServer) ListTasks(ctx context.Context, req *pb.ListTasksRequest) (*pb.ListTasksResponse, error) {
+	if req.GetTimerId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "[list tasks] timer_id is required")
+	}
+
+	getReq := vo.GetTasksReq{
+		App:            req.GetApp(),
+		TimerID:        uint(req.GetTimerId()),
+		WithFullOutput: req.GetWithFullOutput(),
+		PageLimiter:    vo.PageLimiter{Index: int(req.GetPageIndex()), Size: int(req.GetPageSize())},
+	}
+	// 非管理员只能查询所属应用的执行记录
+	if principal := getPrincipal(ctx); !principal.IsAdmin() {
+		getReq.App = principal.App
+	}
+
+	tasks, total, err := t.service.GetTasks(ctx, &getReq)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := pb.ListTasksResponse{Total: total, Tasks: make([]*pb.Task, 0, len(tasks))}
+	for _, task := range tasks {
+		resp.Tasks = append(resp.Tasks, toPBTask(task))
+	}
+	return &resp, nil
+}
@@ -0,0 +1,281 @@
+package grpcserver
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gotimer_web/common/consts"
+	"gotimer_web/common/model/vo"
+	"gotimer_web/pb"
+	service "gotimer_web/service/webserver"
+)
+
+type timerService interface {
+	CreateTimer(ctx context.Context, timer *vo.Timer) (uint, error)
+	DeleteTimer(ctx context.Context, app string, id uint) error
+	UpdateTimer(ctx context.Context, timer *vo.Timer) error
+	GetTimer(ctx context.Context, id uint) (*vo.Timer, error)
+	EnableTimer(ctx context.Context, app string, id uint) error
+	UnableTimer(ctx context.Context, app string, id uint) error
+	RunTimer(ctx context.Context, app string, id uint) (uint, error)
+	GetAppTimers(ctx context.Context, req *vo.GetAppTimersReq) ([]*vo.Timer, int64, error)
+	GetTimersByName(ctx context.Context, req *vo.GetTimersByNameReq) ([]*vo.Timer, int64, error)
+	PreviewCron(ctx context.Context, req *vo.CronPreviewReq) (*vo.CronPreview, error)
+	GetTimerVersions(ctx context.Context, req *vo.GetTimerVersionsReq) ([]*vo.TimerVersion, int64, error)
+	DiffTimerVersions(ctx context.Context, req *vo.DiffTimerVersionsReq) (*vo.TimerVersionDiff, error)
+	RollbackTimer(ctx context.Context, req *vo.RollbackTimerReq) error
+	BulkTimers(ctx context.Context, action consts.BulkAction, req *vo.BulkTimersReq) (*vo.BulkTimersReport, error)
+}
+
+type TimerServer struct {
+	pb.UnimplementedTimerServiceServer
+	service timerService
+}
+
+func NewTimerServer(service *service.TimerService) *TimerServer {
+	return &TimerServer{
+		service: service,
+	}
+}
+
+func (t *TimerServer) CreateTimer(ctx context.Context, req *pb.Timer) (*pb.CreateTimerResponse, error) {
+	if req.GetApp() == "" || req.GetName() == "" || req.GetNotifyHttpParam() == nil {
+		return nil, status.Error(codes.InvalidArgument, "[create timer] app, name and notify_http_param are required")
+	}
+	if err := checkApp(ctx, req.GetApp()); err != nil {
+		return nil, err
+	}
+
+	timer := toVOTimer(req)
+	// 与 http 接口一致，新建时忽略调用方传入的 id
+	timer.ID = 0
+	id, err := t.service.CreateTimer(ctx, timer)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.CreateTimerResponse{Id: uint64(id)}, nil
+}
+
+func (t *TimerServer) GetTimer(ctx context.Context, req *pb.TimerRequest) (*pb.Timer, error) {
+	if err := checkTimerReq(ctx, req.GetApp(), req.GetId()); err != nil {
+		return nil, err
+	}
+
+	timer, err := t.service.GetTimer(ctx, uint(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if timer.App != req.GetApp() {
+		return nil, status.Errorf(codes.NotFound, "timer not belongs to app: %s, timer id: %d", req.GetApp(), req.GetId())
+	}
+	return toPBTimer(timer), nil
+}
+
+func (t *TimerServer) UpdateTimer(ctx context.Context, req *pb.UpdateTimerRequest) (*emptypb.Empty, error) {
+	pTimer := req.GetTimer()
+	if pTimer == nil || pTimer.GetName() == "" || pTimer.GetNotifyHttpParam() == nil {
+		return nil, status.Error(codes.InvalidArgument, "[update timer] timer with name and notify_http_param is required")
+	}
+	if err := checkTimerReq(ctx, pTimer.GetApp(), pTimer.GetId()); err != nil {
+		return nil, err
+	}
+
+	timer := toVOTimer(pTimer)
+	// proto3 的 map 无法区分未设置与空，由 update_labels 决定是否覆盖标签
+	if !req.GetUpdateLabels() {
+		timer.Labels = nil
+	} else if timer.Labels == nil {
+		timer.Labels = map[string]string{}
+	}
+	if err := t.service.UpdateTimer(ctx, timer); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (t *TimerServer) DeleteTimer(ctx context.Context, req *pb.TimerRequest) (*emptypb.Empty, error) {
+	if err := checkTimerReq(ctx, req.GetApp(), req.GetId()); err != nil {
+		return nil, err
+	}
+	if err := t.service.DeleteTimer(ctx, req.GetApp(), uint(req.GetId())); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (t *TimerServer) ListTimers(ctx context.Context, req *pb.ListTimersRequest) (*pb.ListTimersResponse, error) {
+	if req.GetApp() == "" {
+		return nil, status.Error(codes.InvalidArgument, "[list timers] app is required")
+	}
+	if err := checkApp(ctx, req.GetApp()); err != nil {
+		return nil, err
+	}
+
+	page := vo.PageLimiter{Index: int(req.GetPageIndex()), Size: int(req.GetPageSize())}
+	var (
+		timers []*vo.Timer
+		total  int64
+		err    error
+	)
+	if req.GetFuzzyName() == "" {
+		timers, total, err = t.service.GetAppTimers(ctx, &vo.GetAppTimersReq{App: req.GetApp(), Selector: req.GetSelector(), PageLimiter: page})
+	} else {
+		timers, total, err = t.service.GetTimersByName(ctx, &vo.GetTimersByNameReq{App: req.GetApp(), FuzzyName: req.GetFuzzyName(), Selector: req.GetSelector(), PageLimiter: page})
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.ListTimersResponse{Timers: toPBTimers(timers), Total: total}, nil
+}
+
+func (t *TimerServer) EnableTimer(ctx context.Context, req *pb.TimerRequest) (*emptypb.Empty, error) {
+	if err := checkTimerReq(ctx, req.GetApp(), req.GetId()); err != nil {
+		return nil, err
+	}
+	if err := t.service.EnableTimer(ctx, req.GetApp(), uint(req.GetId())); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (t *TimerServer) DisableTimer(ctx context.Context, req *pb.TimerRequest) (*emptypb.Empty, error) {
+	if err := checkTimerReq(ctx, req.GetApp(), req.GetId()); err != nil {
+		return nil, err
+	}
+	if err := t.service.UnableTimer(ctx, req.GetApp(), uint(req.GetId())); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (t *TimerServer) RunTimer(ctx context.Context, req *pb.TimerRequest) (*pb.RunTimerResponse, error) {
+	if err := checkTimerReq(ctx, req.GetApp(), req.GetId()); err != nil {
+		return nil, err
+	}
+	taskID, err := t.service.RunTimer(ctx, req.GetApp(), uint(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.RunTimerResponse{TaskId: uint64(taskID)}, nil
+}
+
+func (t *TimerServer) PreviewCron(ctx context.Context, req *pb.PreviewCronRequest) (*pb.CronPreview, error) {
+	if req.GetCron() == "" {
+		return nil, status.Error(codes.InvalidArgument, "[preview cron] cron is required")
+	}
+
+	preview, err := t.service.PreviewCron(ctx, &vo.CronPreviewReq{
+		Cron:    req.GetCron(),
+		N:       int(req.GetN()),
+		TZ:      req.GetTz(),
+		TimerID: uint(req.GetTimerId()),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	nexts := make([]*timestamppb.Timestamp, 0, len(preview.Nexts))
+	for _, next := range preview.Nexts {
+		nexts = append(nexts, timestamppb.New(next))
+	}
+	return &pb.CronPreview{Nexts: nexts, Description: preview.Description, Warnings: preview.Warnings}, nil
+}
+
+func (t *TimerServer) ListTimerVersions(ctx context.Context, req *pb.ListTimerVersionsRequest) (*pb.ListTimerVersionsResponse, error) {
+	if err := checkTimerReq(ctx, req.GetApp(), req.GetId()); err != nil {
+		return nil, err
+	}
+
+	versions, total, err := t.service.GetTimerVersions(ctx, &vo.GetTimerVersionsReq{
+		App:         req.GetApp(),
+		ID:          uint(req.GetId()),
+		PageLimiter: vo.PageLimiter{Index: int(req.GetPageIndex()), Size: int(req.GetPageSize())},
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := pb.ListTimerVersionsResponse{Total: total, Versions: make([]*pb.TimerVersion, 0, len(versions))}
+	for _, version := range versions {
+		resp.Versions = append(resp.Versions, toPBTimerVersion(version))
+	}
+	return &resp, nil
+}
+
+func (t *TimerServer) DiffTimerVersions(ctx context.Context, req *pb.DiffTimerVersionsRequest) (*pb.TimerVersionDiff, error) {
+	if err := checkTimerReq(ctx, req.GetApp(), req.GetId()); err != nil {
+		return nil, err
+	}
+	if req.GetFrom() == 0 || req.GetTo() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "[diff timer versions] from and to are required")
+	}
+
+	diff, err := t.service.DiffTimerVersions(ctx, &vo.DiffTimerVersionsReq{
+		App:  req.GetApp(),
+		ID:   uint(req.GetId()),
+		From: int(req.GetFrom()),
+		To:   int(req.GetTo()),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBTimerVersionDiff(diff), nil
+}
+
+func (t *TimerServer) RollbackTimer(ctx context.Context, req *pb.RollbackTimerRequest) (*emptypb.Empty, error) {
+	if err := checkTimerReq(ctx, req.GetApp(), req.GetId()); err != nil {
+		return nil, err
+	}
+	if req.GetVersion() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "[rollback timer] version is required")
+	}
+
+	if err := t.service.RollbackTimer(ctx, &vo.RollbackTimerReq{
+		App:     req.GetApp(),
+		ID:      uint(req.GetId()),
+		Version: int(req.GetVersion()),
+	}); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (t *TimerServer) BulkTimers(ctx context.Context, req *pb.BulkTimersRequest) (*pb.BulkTimersReport, error) {
+	action := consts.BulkAction(req.GetAction())
+	switch action {
+	case consts.BulkEnable, consts.BulkDisable, consts.BulkDelete:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "[bulk timers] invalid action: %s", req.GetAction())
+	}
+
+	bulkReq := vo.BulkTimersReq{
+		App:          req.GetApp(),
+		Selector:     req.GetSelector(),
+		CallbackHost: req.GetCallbackHost(),
+		DryRun:       req.GetDryRun(),
+	}
+	for _, id := range req.GetIds() {
+		bulkReq.IDs = append(bulkReq.IDs, uint(id))
+	}
+	// 非管理员只能批量操作所属应用的定时器
+	if principal := getPrincipal(ctx); !principal.IsAdmin() {
+		bulkReq.App = principal.App
+	}
+
+	report, err := t.service.BulkTimers(ctx, action, &bulkReq)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBBulkTimersReport(report), nil
+}
+
+// checkTimerReq 校验 app 和 id 必填，且调用方有权操作 app
+func checkTimerReq(ctx context.Context, app string, id uint64) error {
+	if app == "" || id == 0 {
+		return status.Error(codes.InvalidArgument, "app and id are required")
+	}
+	return checkApp(ctx, app)
+}
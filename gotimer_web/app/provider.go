@@ -3,12 +3,20 @@ package app
 import (
 	"go.uber.org/dig"
 
+	"gotimer_web/app/grpcserver"
 	"gotimer_web/app/migrator"
 	"gotimer_web/app/scheduler"
 	"gotimer_web/app/webserver"
 	"gotimer_web/common/conf"
+	appdao "gotimer_web/dao/app"
+	auditdao "gotimer_web/dao/audit"
+	authdao "gotimer_web/dao/auth"
+	calendardao "gotimer_web/dao/calendar"
+	secretdao "gotimer_web/dao/secret"
 	taskdao "gotimer_web/dao/task"
 	timerdao "gotimer_web/dao/timer"
+	versiondao "gotimer_web/dao/version"
+	"gotimer_web/mq"
 	"gotimer_web/pkg/bloom"
 	"gotimer_web/pkg/cron"
 	"gotimer_web/pkg/hash"
@@ -55,12 +63,19 @@ func providePKG(c *dig.Container) {
 	c.Provide(cron.NewCronParser)
 	c.Provide(xhttp.NewJSONClient)
 	c.Provide(promethus.GetReporter)
+	c.Provide(mq.NewTriggerProducer)
 }
 
 func provideDAO(c *dig.Container) {
 	c.Provide(timerdao.NewTimerDAO)
 	c.Provide(taskdao.NewTaskDAO)
 	c.Provide(taskdao.NewTaskCache)
+	c.Provide(secretdao.NewSecretDAO)
+	c.Provide(authdao.NewAPIKeyDAO)
+	c.Provide(appdao.NewAppDAO)
+	c.Provide(calendardao.NewCalendarDAO)
+	c.Provide(versiondao.NewVersionDAO)
+	c.Provide(auditdao.NewAuditDAO)
 }
 
 func provideService(c *dig.Container) {
@@ -68,6 +83,11 @@ func provideService(c *dig.Container) {
 	c.Provide(migratorservice.NewWorker)
 	c.Provide(webservice.NewTaskService)
 	c.Provide(webservice.NewTimerService)
+	c.Provide(webservice.NewSecretService)
+	c.Provide(webservice.NewAuthService)
+	c.Provide(webservice.NewAppService)
+	c.Provide(webservice.NewCalendarService)
+	c.Provide(webservice.NewAuditService)
 	c.Provide(executorservice.NewTimerService)
 	c.Provide(executorservice.NewWorker)
 	c.Provide(triggerservice.NewWorker)
@@ -79,7 +99,13 @@ func provideApp(c *dig.Container) {
 	c.Provide(migrator.NewMigratorApp)
 	c.Provide(webserver.NewTaskApp)
 	c.Provide(webserver.NewTimerApp)
+	c.Provide(webserver.NewSecretApp)
+	c.Provide(webserver.NewAuthApp)
+	c.Provide(webserver.NewAppApp)
+	c.Provide(webserver.NewCalendarApp)
+	c.Provide(webserver.NewAuditApp)
 	c.Provide(webserver.NewServer)
+	c.Provide(grpcserver.NewServer)
 	c.Provide(scheduler.NewWorkerApp)
 }
 
@@ -103,6 +129,16 @@ func GetWebServer() *webserver.Server {
 	return server
 }
 
+func GetGRPCServer() *grpcserver.Server {
+	var server *grpcserver.Server
+	if err := container.Invoke(func(_s *grpcserver.Server) {
+		server = _s
+	}); err != nil {
+		panic(err)
+	}
+	return server
+}
+
 func GetMigratorApp() *migrator.MigratorApp {
 	var migratorApp *migrator.MigratorApp
 	if err := container.Invoke(func(_m *migrator.MigratorApp) {
@@ -16,33 +16,60 @@ type Server struct {
 	sync.Once
 	engine *gin.Engine
 
-	timerApp *TimerAPP
-	taskApp  *TaskApp
-
-	timerRouter *gin.RouterGroup
-	taskRouter  *gin.RouterGroup
-	mockRouter  *gin.RouterGroup
+	timerApp    *TimerAPP
+	taskApp     *TaskApp
+	secretApp   *SecretApp
+	authApp     *AuthApp
+	appApp      *AppApp
+	calendarApp *CalendarApp
+	auditApp    *AuditApp
+
+	timerRouter    *gin.RouterGroup
+	taskRouter     *gin.RouterGroup
+	secretRouter   *gin.RouterGroup
+	authRouter     *gin.RouterGroup
+	appRouter      *gin.RouterGroup
+	calendarRouter *gin.RouterGroup
+	auditRouter    *gin.RouterGroup
+	mockRouter     *gin.RouterGroup
 
 	confProvider *conf.WebServerAppConfProvider
 }
 
-func NewServer(timer *TimerAPP, task *TaskApp, confProvider *conf.WebServerAppConfProvider) *Server {
+func NewServer(timer *TimerAPP, task *TaskApp, secret *SecretApp, auth *AuthApp, app *AppApp, calendar *CalendarApp, audit *AuditApp, confProvider *conf.WebServerAppConfProvider) *Server {
 	s := Server{
 		engine:       gin.Default(),
 		timerApp:     timer,
 		taskApp:      task,
+		secretApp:    secret,
+		authApp:      auth,
+		appApp:       app,
+		calendarApp:  calendar,
+		auditApp:     audit,
 		confProvider: confProvider,
 	}
 
-	s.engine.Use(CrosHandler())
-
-	s.timerRouter = s.engine.Group("api/timer/v1")
-	s.taskRouter = s.engine.Group("api/task/v1")
+	s.engine.Use(CrosHandler(confProvider.Get().AllowedOrigins))
+
+	// swagger、监控及 mock 接口不需要认证
+	authHandler := AuthHandler(auth.service)
+	s.timerRouter = s.engine.Group("api/timer/v1", authHandler)
+	s.taskRouter = s.engine.Group("api/task/v1", authHandler)
+	s.secretRouter = s.engine.Group("api/secret/v1", authHandler)
+	s.authRouter = s.engine.Group("api/auth/v1", authHandler, AdminHandler())
+	s.appRouter = s.engine.Group("api/app/v1", authHandler)
+	s.calendarRouter = s.engine.Group("api/calendar/v1", authHandler)
+	s.auditRouter = s.engine.Group("api/audit/v1", authHandler)
 	s.mockRouter = s.engine.Group("api/mock/v1")
 	s.RegisterBaseRouter()
 	s.RegisterMockRouter()
 	s.RegisterTimerRouter()
 	s.RegisterTaskRouter()
+	s.RegisterSecretRouter()
+	s.RegisterAuthRouter()
+	s.RegisterAppRouter()
+	s.RegisterCalendarRouter()
+	s.RegisterAuditRouter()
 	s.RegisterMonitorRouter()
 	return &s
 }
@@ -78,12 +105,59 @@ func (s *Server) RegisterTimerRouter() {
 
 	s.timerRouter.POST("/enable", s.timerApp.EnableTimer)
 	s.timerRouter.POST("/unable", s.timerApp.UnableTimer)
+	s.timerRouter.POST("/run", s.timerApp.RunTimer)
+
+	s.timerRouter.GET("/cron/preview", s.timerApp.PreviewCron)
+
+	s.timerRouter.GET("/versions", s.timerApp.GetTimerVersions)
+	s.timerRouter.GET("/versions/diff", s.timerApp.DiffTimerVersions)
+	s.timerRouter.POST("/rollback", s.timerApp.RollbackTimer)
+
+	s.timerRouter.POST("/bulk/enable", s.timerApp.BulkEnableTimers)
+	s.timerRouter.POST("/bulk/unable", s.timerApp.BulkUnableTimers)
+	s.timerRouter.POST("/bulk/delete", s.timerApp.BulkDeleteTimers)
+
+	s.timerRouter.GET("/export", s.timerApp.ExportTimers)
+	s.timerRouter.POST("/sync", s.timerApp.SyncTimers)
 }
 
 func (s *Server) RegisterTaskRouter() {
 	s.taskRouter.GET("/records", s.taskApp.GetTasks)
 }
 
+func (s *Server) RegisterSecretRouter() {
+	s.secretRouter.GET("/list", s.secretApp.GetSecrets)
+	s.secretRouter.POST("/rotate", s.secretApp.RotateSecret)
+	s.secretRouter.DELETE("/revoke", s.secretApp.RevokeSecret)
+}
+
+func (s *Server) RegisterAuthRouter() {
+	s.authRouter.GET("/keys", s.authApp.GetAPIKeys)
+	s.authRouter.POST("/key", s.authApp.CreateAPIKey)
+	s.authRouter.DELETE("/key", s.authApp.DeleteAPIKey)
+}
+
+// 应用的注册、修改配额和删除仅管理员可操作，应用自身只能查看
+func (s *Server) RegisterAppRouter() {
+	s.appRouter.GET("/def", s.appApp.GetApp)
+	s.appRouter.POST("/def", AdminHandler(), s.appApp.CreateApp)
+	s.appRouter.PATCH("/def", AdminHandler(), s.appApp.UpdateApp)
+	s.appRouter.DELETE("/def", AdminHandler(), s.appApp.DeleteApp)
+	s.appRouter.GET("/defs", AdminHandler(), s.appApp.GetApps)
+}
+
+func (s *Server) RegisterCalendarRouter() {
+	s.calendarRouter.GET("/def", s.calendarApp.GetCalendar)
+	s.calendarRouter.POST("/def", s.calendarApp.CreateCalendar)
+	s.calendarRouter.PATCH("/def", s.calendarApp.UpdateCalendar)
+	s.calendarRouter.DELETE("/def", s.calendarApp.DeleteCalendar)
+	s.calendarRouter.GET("/defs", s.calendarApp.GetCalendars)
+}
+
+func (s *Server) RegisterAuditRouter() {
+	s.auditRouter.GET("/records", s.auditApp.GetAuditLogs)
+}
+
 func (s *Server) RegisterMockRouter() {
 	s.mockRouter.Any("/mock", func(ctx *gin.Context) {
 		ctx.JSON(http.StatusOK, struct {
@@ -0,0 +1,105 @@
+package webserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gotimer_web/common/model/vo"
+	service "gotimer_web/service/webserver"
+)
+
+type appService interface {
+	CreateApp(ctx context.Context, app *vo.App) error
+	UpdateApp(ctx context.Context, app *vo.App) error
+	DeleteApp(ctx context.Context, name string) error
+	GetApp(ctx context.Context, name string) (*vo.App, error)
+	GetApps(ctx context.Context, req *vo.GetAppsReq) ([]*vo.App, int64, error)
+}
+
+// AppApp 接入方应用的注册与配额管理
+type AppApp struct {
+	service appService
+}
+
+func NewAppApp(service *service.AppService) *AppApp {
+	return &AppApp{
+		service: service,
+	}
+}
+
+func (a *AppApp) CreateApp(c *gin.Context) {
+	var req vo.App
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[create app] bind req failed, err: %v", err)))
+		return
+	}
+
+	if err := a.service.CreateApp(c.Request.Context(), &req); err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewCodeMsgWithErr(nil))
+}
+
+func (a *AppApp) UpdateApp(c *gin.Context) {
+	var req vo.App
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[update app] bind req failed, err: %v", err)))
+		return
+	}
+
+	if err := a.service.UpdateApp(c.Request.Context(), &req); err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewCodeMsgWithErr(nil))
+}
+
+func (a *AppApp) DeleteApp(c *gin.Context) {
+	var req vo.AppReq
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[delete app] bind req failed, err: %v", err)))
+		return
+	}
+
+	if err := a.service.DeleteApp(c.Request.Context(), req.Name); err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewCodeMsgWithErr(nil))
+}
+
+func (a *AppApp) GetApp(c *gin.Context) {
+	var req vo.AppReq
+	if err := c.Bind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[get app] bind req failed, err: %v", err)))
+		return
+	}
+	if !checkApp(c, req.Name) {
+		return
+	}
+
+	app, err := a.service.GetApp(c.Request.Context(), req.Name)
+	if err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewGetAppResp(app, vo.NewCodeMsgWithErr(nil)))
+}
+
+func (a *AppApp) GetApps(c *gin.Context) {
+	var req vo.GetAppsReq
+	if err := c.Bind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[get apps] bind req failed, err: %v", err)))
+		return
+	}
+
+	apps, total, err := a.service.GetApps(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewGetAppsResp(apps, total, vo.NewCodeMsgWithErr(nil)))
+}
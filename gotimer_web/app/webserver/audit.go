@@ -0,0 +1,44 @@
+package webserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gotimer_web/common/model/vo"
+	service "gotimer_web/service/webserver"
+)
+
+type auditService interface {
+	GetAuditLogs(ctx context.Context, req *vo.GetAuditLogsReq) ([]*vo.AuditLog, int64, error)
+}
+
+type AuditApp struct {
+	service auditService
+}
+
+func NewAuditApp(service *service.AuditService) *AuditApp {
+	return &AuditApp{
+		service: service,
+	}
+}
+
+func (a *AuditApp) GetAuditLogs(c *gin.Context) {
+	var req vo.GetAuditLogsReq
+	if err := c.Bind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[get audit logs] bind req failed, err: %v", err)))
+		return
+	}
+	// 非管理员只能查询所属应用的审计日志
+	if principal := getPrincipal(c); !principal.IsAdmin() {
+		req.App = principal.App
+	}
+
+	logs, total, err := a.service.GetAuditLogs(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewGetAuditLogsResp(logs, total, vo.NewCodeMsgWithErr(nil)))
+}
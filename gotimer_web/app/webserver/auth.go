@@ -0,0 +1,72 @@
+package webserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gotimer_web/common/model/vo"
+	service "gotimer_web/service/webserver"
+)
+
+type authService interface {
+	authenticator
+	CreateAPIKey(ctx context.Context, req *vo.CreateAPIKeyReq) (*vo.APIKey, error)
+	GetAPIKeys(ctx context.Context, app string) ([]*vo.APIKey, error)
+	DeleteAPIKey(ctx context.Context, id uint) error
+}
+
+type AuthApp struct {
+	service authService
+}
+
+func NewAuthApp(service *service.AuthService) *AuthApp {
+	return &AuthApp{
+		service: service,
+	}
+}
+
+func (a *AuthApp) CreateAPIKey(c *gin.Context) {
+	var req vo.CreateAPIKeyReq
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[create api key] bind req failed, err: %v", err)))
+		return
+	}
+
+	key, err := a.service.CreateAPIKey(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewCreateAPIKeyResp(key, vo.NewCodeMsgWithErr(nil)))
+}
+
+func (a *AuthApp) GetAPIKeys(c *gin.Context) {
+	var req vo.GetAPIKeysReq
+	if err := c.Bind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[get api keys] bind req failed, err: %v", err)))
+		return
+	}
+
+	keys, err := a.service.GetAPIKeys(c.Request.Context(), req.App)
+	if err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewGetAPIKeysResp(keys, vo.NewCodeMsgWithErr(nil)))
+}
+
+func (a *AuthApp) DeleteAPIKey(c *gin.Context) {
+	var req vo.APIKeyReq
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[delete api key] bind req failed, err: %v", err)))
+		return
+	}
+
+	if err := a.service.DeleteAPIKey(c.Request.Context(), req.ID); err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewCodeMsgWithErr(nil))
+}
@@ -0,0 +1,116 @@
+package webserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gotimer_web/common/model/vo"
+	service "gotimer_web/service/webserver"
+)
+
+type calendarService interface {
+	CreateCalendar(ctx context.Context, calendar *vo.Calendar) error
+	UpdateCalendar(ctx context.Context, calendar *vo.Calendar) error
+	DeleteCalendar(ctx context.Context, app, name string) error
+	GetCalendar(ctx context.Context, app, name string) (*vo.Calendar, error)
+	GetCalendars(ctx context.Context, app string) ([]*vo.Calendar, error)
+}
+
+type CalendarApp struct {
+	service calendarService
+}
+
+func NewCalendarApp(service *service.CalendarService) *CalendarApp {
+	return &CalendarApp{
+		service: service,
+	}
+}
+
+func (a *CalendarApp) CreateCalendar(c *gin.Context) {
+	var req vo.Calendar
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[create calendar] bind req failed, err: %v", err)))
+		return
+	}
+	if !checkApp(c, req.App) {
+		return
+	}
+
+	if err := a.service.CreateCalendar(c.Request.Context(), &req); err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewCodeMsgWithErr(nil))
+}
+
+func (a *CalendarApp) UpdateCalendar(c *gin.Context) {
+	var req vo.Calendar
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[update calendar] bind req failed, err: %v", err)))
+		return
+	}
+	if !checkApp(c, req.App) {
+		return
+	}
+
+	if err := a.service.UpdateCalendar(c.Request.Context(), &req); err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewCodeMsgWithErr(nil))
+}
+
+func (a *CalendarApp) DeleteCalendar(c *gin.Context) {
+	var req vo.CalendarReq
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[delete calendar] bind req failed, err: %v", err)))
+		return
+	}
+	if !checkApp(c, req.App) {
+		return
+	}
+
+	if err := a.service.DeleteCalendar(c.Request.Context(), req.App, req.Name); err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewCodeMsgWithErr(nil))
+}
+
+func (a *CalendarApp) GetCalendar(c *gin.Context) {
+	var req vo.CalendarReq
+	if err := c.Bind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[get calendar] bind req failed, err: %v", err)))
+		return
+	}
+	if !checkApp(c, req.App) {
+		return
+	}
+
+	calendar, err := a.service.GetCalendar(c.Request.Context(), req.App, req.Name)
+	if err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewGetCalendarResp(calendar, vo.NewCodeMsgWithErr(nil)))
+}
+
+func (a *CalendarApp) GetCalendars(c *gin.Context) {
+	var req vo.GetCalendarsReq
+	if err := c.Bind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[get calendars] bind req failed, err: %v", err)))
+		return
+	}
+	if !checkApp(c, req.App) {
+		return
+	}
+
+	calendars, err := a.service.GetCalendars(c.Request.Context(), req.App)
+	if err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewGetCalendarsResp(calendars, vo.NewCodeMsgWithErr(nil)))
+}
@@ -1,20 +1,43 @@
 package webserver
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strings"
 
+	"gotimer_web/common/consts"
 	"gotimer_web/common/model/vo"
 
 	"github.com/gin-gonic/gin"
 )
 
-func CrosHandler() gin.HandlerFunc {
+// gin 上下文中保存调用方身份的 key
+const principalKey = "principal"
+
+type authenticator interface {
+	Enabled() bool
+	Authenticate(ctx context.Context, token string) (*vo.Principal, error)
+}
+
+// CrosHandler 只允许 allowedOrigins 中的来源跨域访问，为空时不允许任何来源.
+// 接口通过 X-Api-Key 等凭证认证，允许任意来源会使浏览器持有的凭证可被任意网页使用
+func CrosHandler(allowedOrigins []string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[origin] = true
+	}
 	return func(context *gin.Context) {
 		method := context.Request.Method
-		context.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		context.Header("Access-Control-Allow-Origin", "*") // 设置允许访问所有域
+		origin := context.GetHeader("Origin")
+		context.Header("Vary", "Origin")
+		if origin == "" || !allowed[origin] {
+			context.Next()
+			return
+		}
+		context.Header("Access-Control-Allow-Origin", origin)
 		context.Header("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE,UPDATE")
-		context.Header("Access-Control-Allow-Headers", "Authorization, Content-Length, X-CSRF-Token, Token,session,X_Requested_With,Accept, Origin, Host, Connection, Accept-Encoding, Accept-Language,DNT, X-CustomHeader, Keep-Alive, User-Agent, X-Requested-With, If-Modified-Since, Cache-Control, Content-Type, Pragma,token,openid,opentoken")
+		context.Header("Access-Control-Allow-Headers", "Authorization, Content-Length, X-CSRF-Token, Token,session,X_Requested_With,Accept, Origin, Host, Connection, Accept-Encoding, Accept-Language,DNT, X-CustomHeader, Keep-Alive, User-Agent, X-Requested-With, If-Modified-Since, Cache-Control, Content-Type, Pragma,token,openid,opentoken,X-Api-Key")
 		context.Header("Access-Control-Expose-Headers", "Content-Length, Access-Control-Allow-Origin, Access-Control-Allow-Headers,Cache-Control,Content-Language,Content-Type,Expires,Last-Modified,Pragma,FooBar")
 		context.Header("Access-Control-Max-Age", "172800")
 		context.Header("Access-Control-Allow-Credentials", "false")
@@ -27,3 +50,67 @@ func CrosHandler() gin.HandlerFunc {
 		context.Next()
 	}
 }
+
+// AuthHandler 校验 Authorization: Bearer <key> 或 X-Api-Key 请求头，并将调用方身份写入上下文
+func AuthHandler(auth authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+
+		if !auth.Enabled() {
+			setPrincipal(c, &vo.Principal{Role: consts.AdminRole, KeyName: "anonymous"})
+			c.Next()
+			return
+		}
+
+		token := c.GetHeader("X-Api-Key")
+		if bearer := c.GetHeader("Authorization"); token == "" && strings.HasPrefix(bearer, "Bearer ") {
+			token = strings.TrimSpace(strings.TrimPrefix(bearer, "Bearer "))
+		}
+
+		principal, err := auth.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, vo.NewCodeMsg(-1, err.Error()))
+			return
+		}
+		setPrincipal(c, principal)
+		c.Next()
+	}
+}
+
+// setPrincipal 调用方身份同时写入 gin 上下文和请求的 ctx，后者供 service 层使用
+func setPrincipal(c *gin.Context, principal *vo.Principal) {
+	c.Set(principalKey, principal)
+	c.Request = c.Request.WithContext(vo.WithPrincipal(c.Request.Context(), principal))
+}
+
+// AdminHandler 仅允许管理员访问
+func AdminHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodOptions && !getPrincipal(c).IsAdmin() {
+			c.AbortWithStatusJSON(http.StatusForbidden, vo.NewCodeMsg(-1, "admin role required"))
+			return
+		}
+		c.Next()
+	}
+}
+
+func getPrincipal(c *gin.Context) *vo.Principal {
+	if v, ok := c.Get(principalKey); ok {
+		if principal, ok := v.(*vo.Principal); ok {
+			return principal
+		}
+	}
+	return &vo.Principal{}
+}
+
+// checkApp 校验调用方是否有权操作 app，无权时直接写回 403
+func checkApp(c *gin.Context, app string) bool {
+	if getPrincipal(c).CanAccess(app) {
+		return true
+	}
+	c.JSON(http.StatusForbidden, vo.NewCodeMsg(-1, fmt.Sprintf("no permission to access app: %s", app)))
+	return false
+}
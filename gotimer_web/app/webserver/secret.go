@@ -0,0 +1,80 @@
+package webserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gotimer_web/common/model/vo"
+	service "gotimer_web/service/webserver"
+)
+
+type secretService interface {
+	RotateSecret(ctx context.Context, app string) (*vo.AppSecret, error)
+	GetSecrets(ctx context.Context, app string) ([]*vo.AppSecret, error)
+	RevokeSecret(ctx context.Context, app string, id uint) error
+}
+
+type SecretApp struct {
+	service secretService
+}
+
+func NewSecretApp(service *service.SecretService) *SecretApp {
+	return &SecretApp{
+		service: service,
+	}
+}
+
+func (s *SecretApp) RotateSecret(c *gin.Context) {
+	var req vo.RotateSecretReq
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[rotate secret] bind req failed, err: %v", err)))
+		return
+	}
+	if !checkApp(c, req.App) {
+		return
+	}
+
+	secret, err := s.service.RotateSecret(c.Request.Context(), req.App)
+	if err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewRotateSecretResp(secret, vo.NewCodeMsgWithErr(nil)))
+}
+
+func (s *SecretApp) GetSecrets(c *gin.Context) {
+	var req vo.GetSecretsReq
+	if err := c.Bind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[get secrets] bind req failed, err: %v", err)))
+		return
+	}
+	if !checkApp(c, req.App) {
+		return
+	}
+
+	secrets, err := s.service.GetSecrets(c.Request.Context(), req.App)
+	if err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewGetSecretsResp(secrets, vo.NewCodeMsgWithErr(nil)))
+}
+
+func (s *SecretApp) RevokeSecret(c *gin.Context) {
+	var req vo.SecretReq
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[revoke secret] bind req failed, err: %v", err)))
+		return
+	}
+	if !checkApp(c, req.App) {
+		return
+	}
+
+	if err := s.service.RevokeSecret(c.Request.Context(), req.App, req.ID); err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewCodeMsgWithErr(nil))
+}
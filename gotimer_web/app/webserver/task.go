@@ -30,6 +30,10 @@ func (t *TaskApp) GetTasks(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[get tasks] bind req failed,err: %v", err)))
 		return
 	}
+	// 非管理员只能查询所属应用的执行记录
+	if principal := getPrincipal(c); !principal.IsAdmin() {
+		req.App = principal.App
+	}
 
 	tasks, total, err := t.service.GetTasks(c.Request.Context(), &req)
 	c.JSON(http.StatusOK, vo.NewGetTasksResp(tasks, total, vo.NewCodeMsgWithErr(err)))
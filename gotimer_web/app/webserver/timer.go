@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"gotimer_web/common/consts"
 	"gotimer_web/common/model/vo"
 	service "gotimer_web/service/webserver"
 	"net/http"
@@ -16,8 +17,16 @@ type timerService interface {
 	GetTimer(ctx context.Context, id uint) (*vo.Timer, error)
 	EnableTimer(ctx context.Context, app string, id uint) error
 	UnableTimer(ctx context.Context, app string, id uint) error
+	RunTimer(ctx context.Context, app string, id uint) (uint, error)
 	GetAppTimers(ctx context.Context, req *vo.GetAppTimersReq) ([]*vo.Timer, int64, error)
 	GetTimersByName(ctx context.Context, req *vo.GetTimersByNameReq) ([]*vo.Timer, int64, error)
+	PreviewCron(ctx context.Context, req *vo.CronPreviewReq) (*vo.CronPreview, error)
+	GetTimerVersions(ctx context.Context, req *vo.GetTimerVersionsReq) ([]*vo.TimerVersion, int64, error)
+	DiffTimerVersions(ctx context.Context, req *vo.DiffTimerVersionsReq) (*vo.TimerVersionDiff, error)
+	RollbackTimer(ctx context.Context, req *vo.RollbackTimerReq) error
+	BulkTimers(ctx context.Context, action consts.BulkAction, req *vo.BulkTimersReq) (*vo.BulkTimersReport, error)
+	ExportTimers(ctx context.Context, req *vo.ExportTimersReq) (*vo.TimerManifest, error)
+	SyncTimers(ctx context.Context, req *vo.SyncTimersReq) (*vo.SyncTimersReport, error)
 }
 
 type TimerAPP struct {
@@ -32,6 +41,9 @@ func (t *TimerAPP) CreateTimer(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[create timer] bind req failed,err: %v", err)))
 		return
 	}
+	if !checkApp(c, req.App) {
+		return
+	}
 
 	id, err := t.service.CreateTimer(c.Request.Context(), &req)
 	if err != nil {
@@ -47,6 +59,9 @@ func (t *TimerAPP) GetAppTimers(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[get app timers] bind req failed, err: %v", err)))
 		return
 	}
+	if !checkApp(c, req.App) {
+		return
+	}
 
 	timers, total, err := t.service.GetAppTimers(c.Request.Context(), &req)
 	if err != nil {
@@ -62,6 +77,9 @@ func (t *TimerAPP) GetTimersByName(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[get timers by name] bind req failed, err: %v", err)))
 		return
 	}
+	if !checkApp(c, req.App) {
+		return
+	}
 
 	timers, total, err := t.service.GetTimersByName(c.Request.Context(), &req)
 	if err != nil {
@@ -77,6 +95,9 @@ func (t *TimerAPP) DeleteTimer(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[delete timer] bind req failed, err: %v", err)))
 		return
 	}
+	if !checkApp(c, req.App) {
+		return
+	}
 
 	if err := t.service.DeleteTimer(c.Request.Context(), req.App, req.ID); err != nil {
 		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
@@ -86,7 +107,24 @@ func (t *TimerAPP) DeleteTimer(c *gin.Context) {
 }
 
 func (t *TimerAPP) UpdateTimer(c *gin.Context) {
-	c.JSON(http.StatusOK, nil)
+	var req vo.Timer
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[update timer] bind req failed, err: %v", err)))
+		return
+	}
+	if !checkApp(c, req.App) {
+		return
+	}
+	if req.ID == 0 {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, "[update timer] empty timer id"))
+		return
+	}
+
+	if err := t.service.UpdateTimer(c.Request.Context(), &req); err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewCodeMsgWithErr(nil))
 }
 
 func (t *TimerAPP) GetTimer(c *gin.Context) {
@@ -95,12 +133,19 @@ func (t *TimerAPP) GetTimer(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[get timer] bind req failed, err: %v", err)))
 		return
 	}
+	if !checkApp(c, req.App) {
+		return
+	}
 
 	timer, err := t.service.GetTimer(c.Request.Context(), req.ID)
 	if err != nil {
 		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
 		return
 	}
+	if timer.App != req.App {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, fmt.Sprintf("timer not belongs to app: %s, timer id: %d", req.App, req.ID)))
+		return
+	}
 	c.JSON(http.StatusOK, vo.NewGetTimerResp(timer, vo.NewCodeMsgWithErr(nil)))
 }
 
@@ -110,6 +155,9 @@ func (t *TimerAPP) EnableTimer(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[enable timer] bind req failed,err: %v", err)))
 		return
 	}
+	if !checkApp(c, req.App) {
+		return
+	}
 
 	if err := t.service.EnableTimer(c.Request.Context(), req.App, req.ID); err != nil {
 		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
@@ -118,15 +166,188 @@ func (t *TimerAPP) EnableTimer(c *gin.Context) {
 	c.JSON(http.StatusOK, vo.NewCodeMsgWithErr(nil))
 }
 
+func (t *TimerAPP) RunTimer(c *gin.Context) {
+	var req vo.TimerReq
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[run timer] bind req failed,err: %v", err)))
+		return
+	}
+	if !checkApp(c, req.App) {
+		return
+	}
+
+	taskID, err := t.service.RunTimer(c.Request.Context(), req.App, req.ID)
+	if err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewRunTimerResp(taskID, vo.NewCodeMsgWithErr(nil)))
+}
+
 func (t *TimerAPP) UnableTimer(c *gin.Context) {
 	var req vo.TimerReq
 	if err := c.BindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[enable timer] bind req failed, err:%v", err)))
 		return
 	}
+	if !checkApp(c, req.App) {
+		return
+	}
 	if err := t.service.UnableTimer(c.Request.Context(), req.App, req.ID); err != nil {
 		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
 		return
 	}
 	c.JSON(http.StatusOK, vo.NewCodeMsgWithErr(nil))
 }
+
+func (t *TimerAPP) PreviewCron(c *gin.Context) {
+	var req vo.CronPreviewReq
+	if err := c.Bind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[preview cron] bind req failed, err: %v", err)))
+		return
+	}
+
+	preview, err := t.service.PreviewCron(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewCronPreviewResp(preview, vo.NewCodeMsgWithErr(nil)))
+}
+
+func (t *TimerAPP) GetTimerVersions(c *gin.Context) {
+	var req vo.GetTimerVersionsReq
+	if err := c.Bind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[get timer versions] bind req failed, err: %v", err)))
+		return
+	}
+	if !checkApp(c, req.App) {
+		return
+	}
+
+	versions, total, err := t.service.GetTimerVersions(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewGetTimerVersionsResp(versions, total, vo.NewCodeMsgWithErr(nil)))
+}
+
+func (t *TimerAPP) DiffTimerVersions(c *gin.Context) {
+	var req vo.DiffTimerVersionsReq
+	if err := c.Bind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[diff timer versions] bind req failed, err: %v", err)))
+		return
+	}
+	if !checkApp(c, req.App) {
+		return
+	}
+
+	diff, err := t.service.DiffTimerVersions(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewDiffTimerVersionsResp(diff, vo.NewCodeMsgWithErr(nil)))
+}
+
+func (t *TimerAPP) RollbackTimer(c *gin.Context) {
+	var req vo.RollbackTimerReq
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[rollback timer] bind req failed, err: %v", err)))
+		return
+	}
+	if !checkApp(c, req.App) {
+		return
+	}
+
+	if err := t.service.RollbackTimer(c.Request.Context(), &req); err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewCodeMsgWithErr(nil))
+}
+
+func (t *TimerAPP) BulkEnableTimers(c *gin.Context) {
+	t.bulkTimers(c, consts.BulkEnable)
+}
+
+func (t *TimerAPP) BulkUnableTimers(c *gin.Context) {
+	t.bulkTimers(c, consts.BulkDisable)
+}
+
+func (t *TimerAPP) BulkDeleteTimers(c *gin.Context) {
+	t.bulkTimers(c, consts.BulkDelete)
+}
+
+func (t *TimerAPP) bulkTimers(c *gin.Context, action consts.BulkAction) {
+	var req vo.BulkTimersReq
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[bulk %s timers] bind req failed, err: %v", action, err)))
+		return
+	}
+	// 非管理员只能批量操作所属应用的定时器
+	if principal := getPrincipal(c); !principal.IsAdmin() {
+		req.App = principal.App
+	}
+
+	report, err := t.service.BulkTimers(c.Request.Context(), action, &req)
+	if err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewBulkTimersResp(report, vo.NewCodeMsgWithErr(nil)))
+}
+
+// ExportTimers 导出应用下定时器的声明式定义，指定 format 时以文件形式返回
+func (t *TimerAPP) ExportTimers(c *gin.Context) {
+	var req vo.ExportTimersReq
+	if err := c.Bind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[export timers] bind req failed, err: %v", err)))
+		return
+	}
+	if !checkApp(c, req.App) {
+		return
+	}
+
+	manifest, err := t.service.ExportTimers(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	if req.Format == "" {
+		c.JSON(http.StatusOK, vo.NewExportTimersResp(manifest, vo.NewCodeMsgWithErr(nil)))
+		return
+	}
+
+	body, err := manifest.Marshal(req.Format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	contentType := "application/json; charset=utf-8"
+	if req.Format == consts.ManifestYAML {
+		contentType = "application/yaml; charset=utf-8"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s-timers.%s", req.App, req.Format))
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// SyncTimers 按声明式定义同步应用下的定时器，dryRun 时只返回变更计划
+func (t *TimerAPP) SyncTimers(c *gin.Context) {
+	var req vo.SyncTimersReq
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, vo.NewCodeMsg(-1, fmt.Sprintf("[sync timers] bind req failed, err: %v", err)))
+		return
+	}
+	if !checkApp(c, req.App) {
+		return
+	}
+
+	report, err := t.service.SyncTimers(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusOK, vo.NewCodeMsg(-1, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, vo.NewSyncTimersResp(report, vo.NewCodeMsgWithErr(nil)))
+}
@@ -0,0 +1,363 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gotimer_web/common/consts"
+	"gotimer_web/common/model/vo"
+)
+
+// Client 定时器服务 /api/timer/v1 与 /api/task/v1 接口的调用方式，HTTPClient 和 Fake 均实现该接口
+type Client interface {
+	CreateTimer(ctx context.Context, timer *vo.Timer) (uint, error)
+	GetTimer(ctx context.Context, app string, id uint) (*vo.Timer, error)
+	UpdateTimer(ctx context.Context, timer *vo.Timer) error
+	DeleteTimer(ctx context.Context, app string, id uint) error
+	GetAppTimers(ctx context.Context, req *vo.GetAppTimersReq) ([]*vo.Timer, int64, error)
+	GetTimersByName(ctx context.Context, req *vo.GetTimersByNameReq) ([]*vo.Timer, int64, error)
+	EnableTimer(ctx context.Context, app string, id uint) error
+	UnableTimer(ctx context.Context, app string, id uint) error
+	RunTimer(ctx context.Context, app string, id uint) (uint, error)
+	PreviewCron(ctx context.Context, req *vo.CronPreviewReq) (*vo.CronPreview, error)
+	GetTimerVersions(ctx context.Context, req *vo.GetTimerVersionsReq) ([]*vo.TimerVersion, int64, error)
+	DiffTimerVersions(ctx context.Context, req *vo.DiffTimerVersionsReq) (*vo.TimerVersionDiff, error)
+	RollbackTimer(ctx context.Context, req *vo.RollbackTimerReq) error
+	BulkTimers(ctx context.Context, action consts.BulkAction, req *vo.BulkTimersReq) (*vo.BulkTimersReport, error)
+	ExportTimers(ctx context.Context, req *vo.ExportTimersReq) (*vo.TimerManifest, error)
+	SyncTimers(ctx context.Context, req *vo.SyncTimersReq) (*vo.SyncTimersReport, error)
+	GetTasks(ctx context.Context, req *vo.GetTasksReq) ([]*vo.Task, int64, error)
+}
+
+var _ Client = (*HTTPClient)(nil)
+
+// HTTPClient 通过 http 调用定时器服务
+type HTTPClient struct {
+	baseURL        string
+	apiKey         string
+	httpClient     *http.Client
+	maxAttempts    int
+	backoff        time.Duration
+	maxBackoff     time.Duration
+	readLimitBytes int64
+}
+
+// NewHTTPClient baseURL 为服务地址，如 http://127.0.0.1:8092
+func NewHTTPClient(baseURL string, opts ...Option) *HTTPClient {
+	c := HTTPClient{baseURL: strings.TrimRight(baseURL, "/")}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	repair(&c)
+	return &c
+}
+
+func (c *HTTPClient) CreateTimer(ctx context.Context, timer *vo.Timer) (uint, error) {
+	var resp vo.CreateTimerResp
+	if err := c.do(ctx, http.MethodPost, "/api/timer/v1/def", nil, timer, &resp); err != nil {
+		return 0, err
+	}
+	return resp.ID, nil
+}
+
+func (c *HTTPClient) GetTimer(ctx context.Context, app string, id uint) (*vo.Timer, error) {
+	var resp vo.GetTimerResp
+	if err := c.do(ctx, http.MethodGet, "/api/timer/v1/def", timerQuery(app, id), nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (c *HTTPClient) UpdateTimer(ctx context.Context, timer *vo.Timer) error {
+	return c.do(ctx, http.MethodPatch, "/api/timer/v1/def", nil, timer, &vo.CodeMsg{})
+}
+
+func (c *HTTPClient) DeleteTimer(ctx context.Context, app string, id uint) error {
+	return c.do(ctx, http.MethodDelete, "/api/timer/v1/def", nil, &vo.TimerReq{App: app, ID: id}, &vo.CodeMsg{})
+}
+
+func (c *HTTPClient) GetAppTimers(ctx context.Context, req *vo.GetAppTimersReq) ([]*vo.Timer, int64, error) {
+	query := pageQuery(req.PageLimiter)
+	query.Set("app", req.App)
+	setIfNotEmpty(query, "selector", req.Selector)
+
+	var resp vo.GetTimersResp
+	if err := c.do(ctx, http.MethodGet, "/api/timer/v1/defs", query, nil, &resp); err != nil {
+		return nil, -1, err
+	}
+	return resp.Data, resp.Total, nil
+}
+
+func (c *HTTPClient) GetTimersByName(ctx context.Context, req *vo.GetTimersByNameReq) ([]*vo.Timer, int64, error) {
+	query := pageQuery(req.PageLimiter)
+	query.Set("app", req.App)
+	query.Set("fuzzyName", req.FuzzyName)
+	setIfNotEmpty(query, "selector", req.Selector)
+
+	var resp vo.GetTimersResp
+	if err := c.do(ctx, http.MethodGet, "/api/timer/v1/defsByName", query, nil, &resp); err != nil {
+		return nil, -1, err
+	}
+	return resp.Data, resp.Total, nil
+}
+
+func (c *HTTPClient) EnableTimer(ctx context.Context, app string, id uint) error {
+	return c.do(ctx, http.MethodPost, "/api/timer/v1/enable", nil, &vo.TimerReq{App: app, ID: id}, &vo.CodeMsg{})
+}
+
+func (c *HTTPClient) UnableTimer(ctx context.Context, app string, id uint) error {
+	return c.do(ctx, http.MethodPost, "/api/timer/v1/unable", nil, &vo.TimerReq{App: app, ID: id}, &vo.CodeMsg{})
+}
+
+func (c *HTTPClient) RunTimer(ctx context.Context, app string, id uint) (uint, error) {
+	var resp vo.RunTimerResp
+	if err := c.do(ctx, http.MethodPost, "/api/timer/v1/run", nil, &vo.TimerReq{App: app, ID: id}, &resp); err != nil {
+		return 0, err
+	}
+	return resp.TaskID, nil
+}
+
+func (c *HTTPClient) PreviewCron(ctx context.Context, req *vo.CronPreviewReq) (*vo.CronPreview, error) {
+	query := neturl.Values{}
+	query.Set("cron", req.Cron)
+	setIfPositive(query, "n", int64(req.N))
+	setIfNotEmpty(query, "tz", req.TZ)
+	setIfPositive(query, "timerID", int64(req.TimerID))
+
+	var resp vo.CronPreviewResp
+	if err := c.do(ctx, http.MethodGet, "/api/timer/v1/cron/preview", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (c *HTTPClient) GetTimerVersions(ctx context.Context, req *vo.GetTimerVersionsReq) ([]*vo.TimerVersion, int64, error) {
+	query := pageQuery(req.PageLimiter)
+	query.Set("app", req.App)
+	query.Set("id", strconv.FormatUint(uint64(req.ID), 10))
+
+	var resp vo.GetTimerVersionsResp
+	if err := c.do(ctx, http.MethodGet, "/api/timer/v1/versions", query, nil, &resp); err != nil {
+		return nil, -1, err
+	}
+	return resp.Data, resp.Total, nil
+}
+
+func (c *HTTPClient) DiffTimerVersions(ctx context.Context, req *vo.DiffTimerVersionsReq) (*vo.TimerVersionDiff, error) {
+	query := timerQuery(req.App, req.ID)
+	query.Set("from", strconv.Itoa(req.From))
+	query.Set("to", strconv.Itoa(req.To))
+
+	var resp vo.DiffTimerVersionsResp
+	if err := c.do(ctx, http.MethodGet, "/api/timer/v1/versions/diff", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (c *HTTPClient) RollbackTimer(ctx context.Context, req *vo.RollbackTimerReq) error {
+	return c.do(ctx, http.MethodPost, "/api/timer/v1/rollback", nil, req, &vo.CodeMsg{})
+}
+
+func (c *HTTPClient) BulkTimers(ctx context.Context, action consts.BulkAction, req *vo.BulkTimersReq) (*vo.BulkTimersReport, error) {
+	var path string
+	switch action {
+	case consts.BulkEnable:
+		path = "/api/timer/v1/bulk/enable"
+	case consts.BulkDisable:
+		path = "/api/timer/v1/bulk/unable"
+	case consts.BulkDelete:
+		path = "/api/timer/v1/bulk/delete"
+	default:
+		return nil, fmt.Errorf("invalid bulk action: %s", action)
+	}
+
+	var resp vo.BulkTimersResp
+	if err := c.do(ctx, http.MethodPost, path, nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// ExportTimers 以 data 字段返回声明式定义，需要 yaml 或 json 文件时使用 TimerManifest.Marshal
+func (c *HTTPClient) ExportTimers(ctx context.Context, req *vo.ExportTimersReq) (*vo.TimerManifest, error) {
+	query := neturl.Values{}
+	query.Set("app", req.App)
+	setIfNotEmpty(query, "selector", req.Selector)
+
+	var resp vo.ExportTimersResp
+	if err := c.do(ctx, http.MethodGet, "/api/timer/v1/export", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (c *HTTPClient) SyncTimers(ctx context.Context, req *vo.SyncTimersReq) (*vo.SyncTimersReport, error) {
+	var resp vo.SyncTimersResp
+	if err := c.do(ctx, http.MethodPost, "/api/timer/v1/sync", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (c *HTTPClient) GetTasks(ctx context.Context, req *vo.GetTasksReq) ([]*vo.Task, int64, error) {
+	query := pageQuery(req.PageLimiter)
+	setIfNotEmpty(query, "app", req.App)
+	query.Set("timerID", strconv.FormatUint(uint64(req.TimerID), 10))
+	if req.WithFullOutput {
+		query.Set("withFullOutput", "true")
+	}
+
+	var resp vo.GetTaskResp
+	if err := c.do(ctx, http.MethodGet, "/api/task/v1/records", query, nil, &resp); err != nil {
+		return nil, -1, err
+	}
+	return resp.Data, resp.Total, nil
+}
+
+// do 发起请求并解析响应，resp 需内嵌 vo.CodeMsg. 网络错误及 5xx、429 响应按退避间隔重试
+func (c *HTTPClient) do(ctx context.Context, method, path string, query neturl.Values, req interface{}, resp vo.Errorer) error {
+	url := c.baseURL + path
+	if len(query) > 0 {
+		url = url + "?" + query.Encode()
+	}
+
+	var reqBody []byte
+	if req != nil {
+		var err error
+		if reqBody, err = json.Marshal(req); err != nil {
+			return err
+		}
+	}
+
+	backoff := c.backoff
+	for attempt := 1; ; attempt++ {
+		err := c.doOnce(ctx, method, url, reqBody, resp)
+		if err == nil || attempt >= c.maxAttempts || !retryable(method, err) {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > c.maxBackoff {
+			backoff = c.maxBackoff
+		}
+	}
+}
+
+func (c *HTTPClient) doOnce(ctx context.Context, method, url string, reqBody []byte, resp vo.Errorer) error {
+	var body io.Reader
+	if reqBody != nil {
+		body = bytes.NewReader(reqBody)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	if reqBody != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		request.Header.Set("X-Api-Key", c.apiKey)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(response.Body, c.readLimitBytes))
+	if err != nil {
+		return err
+	}
+
+	// 路由不存在、网关报错等情况下响应体不是 CodeMsg
+	var status respStatus
+	if err := json.Unmarshal(respBody, &status); err != nil {
+		return &APIError{HTTPStatus: response.StatusCode, Code: -1, Msg: strings.TrimSpace(string(respBody))}
+	}
+	if response.StatusCode >= http.StatusBadRequest || status.failed() {
+		return &APIError{HTTPStatus: response.StatusCode, Code: status.Code, Msg: status.msg()}
+	}
+	return json.Unmarshal(respBody, resp)
+}
+
+// respStatus 响应中的 CodeMsg. 服务端以 NewCodeMsgWithErr 返回错误时 code 为 0、Err 不为 null，
+// Err 为 error 接口，序列化后丢失内容，无法直接解析到 vo.CodeMsg
+type respStatus struct {
+	Code int32           `json:"code"`
+	Msg  string          `json:"Msg"`
+	Err  json.RawMessage `json:"Err"`
+}
+
+func (s *respStatus) failed() bool {
+	return s.Code != 0 || (len(s.Err) > 0 && string(s.Err) != "null")
+}
+
+func (s *respStatus) msg() string {
+	if s.Msg == "" && s.Code == 0 {
+		return "request failed without message"
+	}
+	return s.Msg
+}
+
+// retryable GET 请求在网络错误及 5xx、429 时重试；其余请求可能已被服务端处理，只在连接未建立时重试
+func retryable(method string, err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	if method != http.MethodGet {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.HTTPStatus >= http.StatusInternalServerError || apiErr.HTTPStatus == http.StatusTooManyRequests
+	}
+	// 调用方取消或超时不再重试
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+func timerQuery(app string, id uint) neturl.Values {
+	query := neturl.Values{}
+	query.Set("app", app)
+	query.Set("id", strconv.FormatUint(uint64(id), 10))
+	return query
+}
+
+func pageQuery(page vo.PageLimiter) neturl.Values {
+	query := neturl.Values{}
+	setIfPositive(query, "pageIndex", int64(page.Index))
+	setIfPositive(query, "pageSize", int64(page.Size))
+	return query
+}
+
+func setIfNotEmpty(query neturl.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
+
+func setIfPositive(query neturl.Values, key string, value int64) {
+	if value > 0 {
+		query.Set(key, strconv.FormatInt(value, 10))
+	}
+}
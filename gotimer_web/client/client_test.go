@@ -0,0 +1,229 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotimer_web/common/consts"
+	"gotimer_web/common/model/vo"
+)
+
+func newTimer(name string) *vo.Timer {
+	return &vo.Timer{
+		App:             "demo",
+		Name:            name,
+		Cron:            "0 */5 * * * * *",
+		NotifyHTTPParam: &vo.NotifyHTTPParam{Method: http.MethodPost, URL: "http://callback.example.com/notify"},
+	}
+}
+
+func TestHTTPClient(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code":-1,"Msg":"invalid api key"}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/api/timer/v1/def":
+			if r.URL.Query().Get("id") == "2" {
+				w.Write([]byte(`{"code":-1,"Msg":"record not found"}`))
+				return
+			}
+			w.Write([]byte(`{"code":0,"Msg":"","data":{"id":1,"app":"demo","name":"a","status":1}}`))
+		case "/api/timer/v1/defs":
+			// 首次请求返回 503，验证 GET 请求的重试
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"code":0,"Msg":"","total":1,"data":[{"id":1,"app":"demo","name":"a"}]}`))
+		case "/api/task/v1/records":
+			// NewCodeMsgWithErr 返回的错误，code 为 0，Err 序列化后没有内容
+			w.Write([]byte(`{"code":0,"Msg":"","Err":{},"total":0,"data":null}`))
+		case "/api/timer/v1/enable":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"code":-1,"Msg":"no permission to access app: demo"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`404 page not found`))
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	c := NewHTTPClient(server.URL, WithAPIKey("key"), WithRetry(3, time.Millisecond))
+
+	timer, err := c.GetTimer(ctx, "demo", 1)
+	if err != nil || timer.ID != 1 || timer.Status != consts.Unabled {
+		t.Fatalf("get timer, timer: %+v, err: %v", timer, err)
+	}
+	if _, err := c.GetTimer(ctx, "demo", 2); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("get missing timer, expect not found, got: %v", err)
+	}
+
+	timers, total, err := c.GetAppTimers(ctx, &vo.GetAppTimersReq{App: "demo"})
+	if err != nil || total != 1 || len(timers) != 1 || atomic.LoadInt32(&calls) != 2 {
+		t.Fatalf("get app timers, total: %d, calls: %d, err: %v", total, calls, err)
+	}
+
+	var apiErr *APIError
+	if _, _, err := c.GetTasks(ctx, &vo.GetTasksReq{App: "demo", TimerID: 1}); !errors.As(err, &apiErr) {
+		t.Fatalf("get tasks, expect api error, got: %v", err)
+	}
+
+	if err := c.EnableTimer(ctx, "demo", 1); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("enable timer, expect forbidden, got: %v", err)
+	}
+	if _, err := c.RunTimer(ctx, "demo", 1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("unknown route, expect not found, got: %v", err)
+	}
+
+	if _, err := NewHTTPClient(server.URL).GetTimer(ctx, "demo", 1); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("get timer without key, expect unauthorized, got: %v", err)
+	}
+}
+
+func TestFakeVersions(t *testing.T) {
+	ctx := context.Background()
+	f := NewFake()
+
+	id, err := f.CreateTimer(ctx, newTimer("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	update := newTimer("a")
+	update.ID, update.Cron = id, "0 0 * * * * *"
+	if err := f.UpdateTimer(ctx, update); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := f.DiffTimerVersions(ctx, &vo.DiffTimerVersionsReq{App: "demo", ID: id, From: 1, To: 2})
+	if err != nil || len(diff.Diff) != 1 || diff.Diff["cron"] == nil {
+		t.Fatalf("diff versions, diff: %+v, err: %v", diff, err)
+	}
+
+	if err := f.RollbackTimer(ctx, &vo.RollbackTimerReq{App: "demo", ID: id, Version: 1}); err != nil {
+		t.Fatal(err)
+	}
+	timer, err := f.GetTimer(ctx, "demo", id)
+	if err != nil || timer.Cron != "0 */5 * * * * *" || timer.Version != 3 {
+		t.Fatalf("rollback, timer: %+v, err: %v", timer, err)
+	}
+
+	if err := f.EnableTimer(ctx, "demo", id); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.EnableTimer(ctx, "demo", id); err == nil {
+		t.Fatal("enable enabled timer, expect err")
+	}
+	if _, err := f.GetTimer(ctx, "other", id); err == nil {
+		t.Fatal("get timer of other app, expect err")
+	}
+	if _, err := f.GetTimer(ctx, "demo", id+1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("get missing timer, expect not found, got: %v", err)
+	}
+}
+
+func TestIterators(t *testing.T) {
+	ctx := context.Background()
+	f := NewFake()
+
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		timer := newTimer(name)
+		if name != "c" {
+			timer.Labels = map[string]string{"team": "billing"}
+		}
+		if _, err := f.CreateTimer(ctx, timer); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var names []string
+	it := NewTimerIterator(f, vo.GetAppTimersReq{App: "demo", Selector: "team=billing", PageLimiter: vo.PageLimiter{Size: 2}})
+	for it.Next(ctx) {
+		names = append(names, it.Timer().Name)
+	}
+	if it.Err() != nil || len(names) != 4 || names[0] != "e" || names[3] != "a" {
+		t.Fatalf("iterate timers, names: %v, err: %v", names, it.Err())
+	}
+
+	for i := 0; i < 3; i++ {
+		f.AddTask(&vo.Task{App: "demo", TimerID: 1, Status: consts.Successed.ToInt(), RunTimer: time.Now().Add(time.Duration(i) * time.Minute)})
+	}
+	f.AddTask(&vo.Task{App: "demo", TimerID: 2, Status: consts.Successed.ToInt()})
+	if _, err := f.RunTimer(ctx, "demo", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	cnt := 0
+	taskIt := NewTaskIterator(f, vo.GetTasksReq{App: "demo", TimerID: 1, PageLimiter: vo.PageLimiter{Size: 2}})
+	for taskIt.Next(ctx) {
+		cnt++
+	}
+	if taskIt.Err() != nil || cnt != 3 || taskIt.Total() != 3 {
+		t.Fatalf("iterate tasks, cnt: %d, total: %d, err: %v", cnt, taskIt.Total(), taskIt.Err())
+	}
+}
+
+func TestFakeSync(t *testing.T) {
+	ctx := context.Background()
+	f := NewFake()
+
+	for _, name := range []string{"a", "b", "c"} {
+		timer := newTimer(name)
+		timer.Labels = map[string]string{"managed": "true"}
+		if _, err := f.CreateTimer(ctx, timer); err != nil {
+			t.Fatal(err)
+		}
+	}
+	manifest, err := f.ExportTimers(ctx, &vo.ExportTimersReq{App: "demo"})
+	if err != nil || len(manifest.Timers) != 3 || manifest.Timers[0].ID != 0 {
+		t.Fatalf("export timers, manifest: %+v, err: %v", manifest, err)
+	}
+
+	// 导出的定义原样同步不产生变更
+	report, err := f.SyncTimers(ctx, &vo.SyncTimersReq{TimerManifest: *manifest, Prune: true})
+	if err != nil || report.Unchanged != 3 || report.Created+report.Updated+report.Deleted+report.Failed != 0 {
+		t.Fatalf("sync exported manifest, report: %+v, err: %v", report, err)
+	}
+
+	b := newTimer("b")
+	b.App, b.Cron, b.Status = "", "0 0 * * * * *", consts.Enabled
+	req := vo.SyncTimersReq{
+		TimerManifest: vo.TimerManifest{App: "demo", Timers: []*vo.Timer{manifest.Timers[0], b, newTimer("d")}},
+		Prune:         true,
+		Selector:      "managed=true",
+		DryRun:        true,
+	}
+	if report, err = f.SyncTimers(ctx, &req); err != nil {
+		t.Fatal(err)
+	}
+	if report.Unchanged != 1 || report.Updated != 1 || report.Created != 1 || report.Deleted != 1 {
+		t.Fatalf("plan sync, report: %+v", report)
+	}
+	if diff := report.Results[1].Diff; len(diff) != 3 || diff["cron"] == nil || diff["status"] == nil || diff["labels"] == nil {
+		t.Fatalf("plan update, diff: %+v", diff)
+	}
+
+	req.DryRun = false
+	if report, err = f.SyncTimers(ctx, &req); err != nil || report.Failed != 0 {
+		t.Fatalf("apply sync, report: %+v, err: %v", report, err)
+	}
+	timer, err := f.GetTimer(ctx, "demo", 2)
+	if err != nil || timer.Cron != "0 0 * * * * *" || timer.Status != consts.Enabled || timer.Labels != nil || timer.Version != 2 {
+		t.Fatalf("synced timer, timer: %+v, err: %v", timer, err)
+	}
+	if _, err := f.GetTimer(ctx, "demo", 3); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("pruned timer, expect not found, got: %v", err)
+	}
+	if timers, total, _ := f.GetAppTimers(ctx, &vo.GetAppTimersReq{App: "demo"}); total != 3 || timers[0].Name != "d" {
+		t.Fatalf("timers after sync, timers: %v, total: %d", timers, total)
+	}
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrUnauthorized 凭证缺失或无效
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden 凭证无权操作目标应用，或接口仅限管理员调用
+	ErrForbidden = errors.New("forbidden")
+	// ErrNotFound 定时器等资源不存在
+	ErrNotFound = errors.New("not found")
+	// ErrBadRequest 请求参数不合法
+	ErrBadRequest = errors.New("bad request")
+)
+
+// 服务端查询不到记录时透传的 gorm 错误信息
+const recordNotFoundMsg = "record not found"
+
+// APIError 服务端返回的错误，可通过 errors.Is 与 ErrUnauthorized 等错误比较
+type APIError struct {
+	HTTPStatus int    // http 状态码，业务错误时通常为 200
+	Code       int32  // 响应中的 code，非 0 表示失败
+	Msg        string // 响应中的 Msg
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("gotimer api error, http status: %d, code: %d, msg: %s", e.HTTPStatus, e.Code, e.Msg)
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.HTTPStatus == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.HTTPStatus == http.StatusForbidden
+	case ErrNotFound:
+		return e.HTTPStatus == http.StatusNotFound || strings.Contains(e.Msg, recordNotFoundMsg)
+	case ErrBadRequest:
+		return e.HTTPStatus == http.StatusBadRequest
+	}
+	return false
+}
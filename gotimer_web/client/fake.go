@@ -0,0 +1,733 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gotimer_web/common/consts"
+	"gotimer_web/common/model/po"
+	"gotimer_web/common/model/vo"
+	"gotimer_web/pkg/cron"
+	"gotimer_web/pkg/selector"
+)
+
+const (
+	defaultFakePreviewN = 10
+	maxFakePreviewN     = 100
+	// 与服务端一致，按接下来 1000 次触发推算 cron 的最小间隔
+	fakeCronIntervalSamples = 1000
+)
+
+var _ Client = (*Fake)(nil)
+
+// Fake 内存实现的 Client，供单元测试使用.
+// 参数校验、状态流转、版本记录和错误信息与服务端保持一致，不做鉴权，不真正执行回调：
+// RunTimer 生成的执行记录停留在未执行状态，与服务端一样不会出现在 GetTasks 的结果中，可通过 AddTask 写入任意执行记录
+type Fake struct {
+	mu          sync.Mutex
+	cronParser  *cron.CronParser
+	nextTimerID uint
+	nextTaskID  uint
+	timers      map[uint]*po.Timer
+	labels      map[uint]map[string]string
+	versions    map[uint][]*po.TimerVersion
+	tasks       map[uint]*vo.Task
+}
+
+func NewFake() *Fake {
+	return &Fake{
+		cronParser: cron.NewCronParser(),
+		timers:     make(map[uint]*po.Timer),
+		labels:     make(map[uint]map[string]string),
+		versions:   make(map[uint][]*po.TimerVersion),
+		tasks:      make(map[uint]*vo.Task),
+	}
+}
+
+// AddTask 写入一条执行记录并返回其 id，task.ID 为空时自动分配
+func (f *Fake) AddTask(task *vo.Task) uint {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	t := *task
+	if t.ID == 0 {
+		f.nextTaskID++
+		t.ID = f.nextTaskID
+	} else if t.ID > f.nextTaskID {
+		f.nextTaskID = t.ID
+	}
+	f.tasks[t.ID] = &t
+	return t.ID
+}
+
+func (f *Fake) CreateTimer(ctx context.Context, timer *vo.Timer) (uint, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.createTimer(ctx, timer)
+}
+
+func (f *Fake) createTimer(ctx context.Context, timer *vo.Timer) (uint, error) {
+	pTimer, err := f.toPO(timer)
+	if err != nil {
+		return 0, err
+	}
+
+	// 与服务端一致，新建的定时器总是未激活
+	pTimer.Status = consts.Unabled.ToInt()
+	f.nextTimerID++
+	pTimer.ID = f.nextTimerID
+	pTimer.CreatedAt, pTimer.UpdatedAt = time.Now(), time.Now()
+	pTimer.Version = 1
+	f.timers[pTimer.ID] = pTimer
+	f.labels[pTimer.ID] = copyLabels(timer.Labels)
+	return pTimer.ID, f.createVersion(ctx, pTimer)
+}
+
+func (f *Fake) GetTimer(_ context.Context, app string, id uint) (*vo.Timer, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	pTimer, err := f.getTimer(app, id)
+	if err != nil {
+		return nil, err
+	}
+	return f.toVO(pTimer)
+}
+
+// UpdateTimer 与服务端一样以请求为完整的定义覆盖，状态不可修改，labels 为 nil 时保持不变
+func (f *Fake) UpdateTimer(ctx context.Context, timer *vo.Timer) error {
+	if timer.ID == 0 {
+		return newFakeError(http.StatusBadRequest, "[update timer] empty timer id")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.updateTimer(ctx, timer)
+}
+
+func (f *Fake) DeleteTimer(_ context.Context, app string, id uint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.getTimer(app, id); err != nil {
+		return err
+	}
+	f.deleteTimer(id)
+	return nil
+}
+
+func (f *Fake) GetAppTimers(_ context.Context, req *vo.GetAppTimersReq) ([]*vo.Timer, int64, error) {
+	return f.listTimers(req.App, "", req.Selector, req.PageLimiter)
+}
+
+func (f *Fake) GetTimersByName(_ context.Context, req *vo.GetTimersByNameReq) ([]*vo.Timer, int64, error) {
+	return f.listTimers(req.App, req.FuzzyName, req.Selector, req.PageLimiter)
+}
+
+func (f *Fake) EnableTimer(_ context.Context, app string, id uint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	pTimer, err := f.getTimer(app, id)
+	if err != nil {
+		return err
+	}
+	if pTimer.Status != consts.Unabled.ToInt() {
+		return newFakeError(http.StatusOK, fmt.Sprintf("not unabled status, enable failed, timer id: %d", id))
+	}
+	if pTimer.IsOnce() && !pTimer.RunAt.After(time.Now()) {
+		return newFakeError(http.StatusOK, fmt.Sprintf("runAt has passed, enable failed, timer id: %d", id))
+	}
+	pTimer.Status = consts.Enabled.ToInt()
+	return nil
+}
+
+func (f *Fake) UnableTimer(_ context.Context, app string, id uint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	pTimer, err := f.getTimer(app, id)
+	if err != nil {
+		return err
+	}
+	if pTimer.Status != consts.Enabled.ToInt() {
+		return newFakeError(http.StatusOK, fmt.Sprintf("not enabled status, unable failed, timer id: %d", id))
+	}
+	pTimer.Status = consts.Unabled.ToInt()
+	return nil
+}
+
+func (f *Fake) RunTimer(_ context.Context, app string, id uint) (uint, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	pTimer, err := f.getTimer(app, id)
+	if err != nil {
+		return 0, err
+	}
+
+	f.nextTaskID++
+	f.tasks[f.nextTaskID] = &vo.Task{
+		ID:           f.nextTaskID,
+		App:          pTimer.App,
+		TimerID:      pTimer.ID,
+		RunTimer:     time.Now().Truncate(time.Millisecond),
+		Status:       consts.NotRunned.ToInt(),
+		Source:       consts.ManualTrigger.ToInt(),
+		TimerVersion: pTimer.Version,
+	}
+	return f.nextTaskID, nil
+}
+
+// PreviewCron 只试算触发时机和描述，不给出告警
+func (f *Fake) PreviewCron(_ context.Context, req *vo.CronPreviewReq) (*vo.CronPreview, error) {
+	n := req.N
+	if n <= 0 {
+		n = defaultFakePreviewN
+	}
+	if n > maxFakePreviewN {
+		n = maxFakePreviewN
+	}
+
+	loc := time.Local
+	if req.TZ != "" {
+		var err error
+		if loc, err = time.LoadLocation(req.TZ); err != nil {
+			return nil, newFakeError(http.StatusOK, fmt.Sprintf("非法的时区: %s", req.TZ))
+		}
+	}
+	if !f.cronParser.IsValidCronExpr(req.Cron) {
+		return nil, newFakeError(http.StatusOK, fmt.Sprintf("invalid cron expr: %s", req.Cron))
+	}
+
+	expr, err := f.cronParser.ResolveHash(req.Cron, uint64(req.TimerID))
+	if err != nil {
+		return nil, newFakeError(http.StatusOK, err.Error())
+	}
+	nexts, err := f.cronParser.NextNIn(expr, loc, time.Now(), n)
+	if err != nil {
+		return nil, newFakeError(http.StatusOK, err.Error())
+	}
+	description, err := f.cronParser.Describe(req.Cron)
+	if err != nil {
+		return nil, newFakeError(http.StatusOK, err.Error())
+	}
+	for i := range nexts {
+		nexts[i] = nexts[i].In(loc)
+	}
+	return &vo.CronPreview{Nexts: nexts, Description: description, Warnings: []string{}}, nil
+}
+
+func (f *Fake) GetTimerVersions(_ context.Context, req *vo.GetTimerVersionsReq) ([]*vo.TimerVersion, int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.getTimer(req.App, req.ID); err != nil {
+		return nil, -1, err
+	}
+
+	versions := make([]*po.TimerVersion, 0, len(f.versions[req.ID]))
+	for i := len(f.versions[req.ID]) - 1; i >= 0; i-- {
+		versions = append(versions, f.versions[req.ID][i])
+	}
+	versions, total := page(versions, req.PageLimiter)
+	return vo.NewTimerVersions(versions), total, nil
+}
+
+func (f *Fake) DiffTimerVersions(_ context.Context, req *vo.DiffTimerVersionsReq) (*vo.TimerVersionDiff, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.getTimer(req.App, req.ID); err != nil {
+		return nil, err
+	}
+	for _, version := range []int{req.From, req.To} {
+		if f.getVersion(req.ID, version) == nil {
+			return nil, newFakeError(http.StatusOK, fmt.Sprintf("version: %d of timer: %d not found", version, req.ID))
+		}
+	}
+	return vo.NewTimerVersionDiff(f.getVersion(req.ID, req.From), f.getVersion(req.ID, req.To))
+}
+
+func (f *Fake) RollbackTimer(ctx context.Context, req *vo.RollbackTimerReq) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	cur, err := f.getTimer(req.App, req.ID)
+	if err != nil {
+		return err
+	}
+	version := f.getVersion(req.ID, req.Version)
+	if version == nil {
+		return newFakeError(http.StatusOK, fmt.Sprintf("get version: %d of timer: %d failed, err: %s", req.Version, req.ID, recordNotFoundMsg))
+	}
+
+	var timer vo.Timer
+	if err := json.Unmarshal([]byte(version.Snapshot), &timer); err != nil {
+		return err
+	}
+	timer.ID, timer.App, timer.Name = cur.ID, cur.App, cur.Name
+	return f.updateTimer(ctx, &timer)
+}
+
+func (f *Fake) BulkTimers(_ context.Context, action consts.BulkAction, req *vo.BulkTimersReq) (*vo.BulkTimersReport, error) {
+	if err := req.Check(); err != nil {
+		return nil, newFakeError(http.StatusOK, err.Error())
+	}
+	labelSelector, err := selector.Parse(req.Selector)
+	if err != nil {
+		return nil, newFakeError(http.StatusOK, err.Error())
+	}
+	ids := make(map[uint]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		ids[id] = true
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	report := vo.NewBulkTimersReport(action, req.DryRun)
+	for _, pTimer := range f.sortedTimers(true) {
+		if req.App != "" && pTimer.App != req.App {
+			continue
+		}
+		if len(ids) > 0 && !ids[pTimer.ID] {
+			continue
+		}
+		if !labelSelector.Matches(f.labels[pTimer.ID]) || !vo.MatchCallbackHost(pTimer, req.CallbackHost) {
+			continue
+		}
+		report.Add(f.bulkTimer(action, req.DryRun, pTimer))
+	}
+	return report, nil
+}
+
+func (f *Fake) ExportTimers(_ context.Context, req *vo.ExportTimersReq) (*vo.TimerManifest, error) {
+	labelSelector, err := selector.Parse(req.Selector)
+	if err != nil {
+		return nil, newFakeError(http.StatusOK, err.Error())
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	manifest := vo.TimerManifest{App: req.App, Timers: make([]*vo.Timer, 0)}
+	for _, pTimer := range f.sortedTimers(true) {
+		if pTimer.App != req.App || !labelSelector.Matches(f.labels[pTimer.ID]) {
+			continue
+		}
+		vTimer, err := f.toVO(pTimer)
+		if err != nil {
+			return nil, err
+		}
+		vTimer.ID, vTimer.App, vTimer.Version = 0, "", 0
+		manifest.Timers = append(manifest.Timers, vTimer)
+	}
+	sort.Slice(manifest.Timers, func(i, j int) bool {
+		return manifest.Timers[i].Name < manifest.Timers[j].Name
+	})
+	return &manifest, nil
+}
+
+// SyncTimers 与服务端的变更计划一致，应用级别的默认配置不生效
+func (f *Fake) SyncTimers(ctx context.Context, req *vo.SyncTimersReq) (*vo.SyncTimersReport, error) {
+	if err := req.Check(); err != nil {
+		return nil, newFakeError(http.StatusOK, err.Error())
+	}
+	labelSelector, err := selector.Parse(req.Selector)
+	if err != nil {
+		return nil, newFakeError(http.StatusOK, err.Error())
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	existing := make(map[string]*po.Timer)
+	for _, pTimer := range f.sortedTimers(true) {
+		if pTimer.App == req.App {
+			existing[pTimer.Name] = pTimer
+		}
+	}
+	declared := make([]*vo.Timer, len(req.Timers))
+	copy(declared, req.Timers)
+	sort.Slice(declared, func(i, j int) bool {
+		return declared[i].Name < declared[j].Name
+	})
+
+	report := vo.NewSyncTimersReport(req)
+	for _, timer := range declared {
+		cur := existing[timer.Name]
+		delete(existing, timer.Name)
+		report.Add(f.syncTimer(ctx, req, timer, cur))
+	}
+	if !req.Prune {
+		return report, nil
+	}
+
+	stale := make([]*po.Timer, 0, len(existing))
+	for _, pTimer := range existing {
+		if labelSelector.Matches(f.labels[pTimer.ID]) {
+			stale = append(stale, pTimer)
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].Name < stale[j].Name
+	})
+	for _, pTimer := range stale {
+		result := vo.SyncTimerResult{Name: pTimer.Name, ID: pTimer.ID, Action: consts.SyncDelete, Result: consts.BulkPlanned}
+		if !req.DryRun {
+			f.deleteTimer(pTimer.ID)
+			result.Result = consts.BulkSucceeded
+		}
+		report.Add(&result)
+	}
+	return report, nil
+}
+
+func (f *Fake) GetTasks(_ context.Context, req *vo.GetTasksReq) ([]*vo.Task, int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tasks := make([]*vo.Task, 0)
+	for _, task := range f.tasks {
+		if task.TimerID != req.TimerID || (req.App != "" && task.App != req.App) || task.Status == consts.NotRunned.ToInt() {
+			continue
+		}
+		t := *task
+		if !req.WithFullOutput {
+			t.FullOutput = ""
+		}
+		tasks = append(tasks, &t)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if !tasks[i].RunTimer.Equal(tasks[j].RunTimer) {
+			return tasks[i].RunTimer.After(tasks[j].RunTimer)
+		}
+		return tasks[i].ID > tasks[j].ID
+	})
+
+	tasks, total := page(tasks, req.PageLimiter)
+	return tasks, total, nil
+}
+
+func (f *Fake) listTimers(app, fuzzyName, expr string, limiter vo.PageLimiter) ([]*vo.Timer, int64, error) {
+	labelSelector, err := selector.Parse(expr)
+	if err != nil {
+		return nil, -1, newFakeError(http.StatusOK, err.Error())
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	timers := make([]*po.Timer, 0)
+	for _, pTimer := range f.sortedTimers(false) {
+		if pTimer.App != app || !strings.Contains(pTimer.Name, fuzzyName) || !labelSelector.Matches(f.labels[pTimer.ID]) {
+			continue
+		}
+		timers = append(timers, pTimer)
+	}
+
+	timers, total := page(timers, limiter)
+	vTimers := make([]*vo.Timer, 0, len(timers))
+	for _, pTimer := range timers {
+		vTimer, err := f.toVO(pTimer)
+		if err != nil {
+			return nil, -1, err
+		}
+		vTimers = append(vTimers, vTimer)
+	}
+	return vTimers, total, nil
+}
+
+func (f *Fake) updateTimer(ctx context.Context, timer *vo.Timer) error {
+	pTimer, err := f.toPO(timer)
+	if err != nil {
+		return err
+	}
+	old, ok := f.timers[timer.ID]
+	if !ok {
+		return newFakeError(http.StatusOK, recordNotFoundMsg)
+	}
+	if old.App != pTimer.App {
+		return newFakeError(http.StatusOK, fmt.Sprintf("timer not belongs to app: %s, timer id: %d", pTimer.App, old.ID))
+	}
+
+	// 与服务端一致，以请求为完整的定义覆盖
+	cur := *pTimer
+	cur.Model = old.Model
+	// 状态只允许通过激活/去激活接口修改，已结束的一次性定时器修改执行时机后回到未激活态
+	cur.Status, cur.Version = old.Status, old.Version
+	if old.Status == consts.Finished.ToInt() && vo.ScheduleChanged(old, &cur) {
+		cur.Status = consts.Unabled.ToInt()
+	}
+	cur.UpdatedAt = time.Now()
+
+	before, err := vo.NewTimerSnapshot(old)
+	if err != nil {
+		return err
+	}
+	after, err := vo.NewTimerSnapshot(&cur)
+	if err != nil {
+		return err
+	}
+	if before != after {
+		cur.Version++
+		if err := f.createVersion(ctx, &cur); err != nil {
+			return err
+		}
+	}
+
+	f.timers[cur.ID] = &cur
+	if timer.Labels != nil {
+		f.labels[cur.ID] = copyLabels(timer.Labels)
+	}
+	return nil
+}
+
+func (f *Fake) bulkTimer(action consts.BulkAction, dryRun bool, pTimer *po.Timer) *vo.BulkTimerResult {
+	result := vo.BulkTimerResult{
+		ID:   pTimer.ID,
+		App:  pTimer.App,
+		Name: pTimer.Name,
+	}
+	if reason := vo.BulkSkipReason(action, pTimer); reason != "" {
+		result.Result, result.Reason = consts.BulkSkipped, reason
+		return &result
+	}
+	if dryRun {
+		result.Result = consts.BulkPlanned
+		return &result
+	}
+
+	switch action {
+	case consts.BulkEnable:
+		pTimer.Status = consts.Enabled.ToInt()
+	case consts.BulkDisable:
+		pTimer.Status = consts.Unabled.ToInt()
+	case consts.BulkDelete:
+		f.deleteTimer(pTimer.ID)
+	default:
+		result.Result, result.Reason = consts.BulkFailed, fmt.Sprintf("invalid bulk action: %s", action)
+		return &result
+	}
+	result.Result = consts.BulkSucceeded
+	return &result
+}
+
+func (f *Fake) syncTimer(ctx context.Context, req *vo.SyncTimersReq, timer *vo.Timer, cur *po.Timer) *vo.SyncTimerResult {
+	result := vo.SyncTimerResult{Name: timer.Name, Action: consts.SyncCreate, Result: consts.BulkPlanned}
+	if cur != nil {
+		result.ID, result.Action = cur.ID, consts.SyncUpdate
+	}
+	setResult := func(err error) *vo.SyncTimerResult {
+		result.Result = consts.BulkSucceeded
+		if err != nil {
+			result.Result, result.Reason = consts.BulkFailed, err.Error()
+		}
+		return &result
+	}
+
+	syncReq := fakeSyncTimerReq(req.App, timer)
+	pTimer, err := f.toPO(syncReq)
+	if err != nil {
+		return setResult(err)
+	}
+	desired, err := vo.NewTimerSpec(pTimer, syncReq.Labels)
+	if err != nil {
+		return setResult(err)
+	}
+	desired.Status = timer.Status
+	var curTimer *vo.Timer
+	if cur != nil {
+		if curTimer, err = f.toVO(cur); err != nil {
+			return setResult(err)
+		}
+	}
+	plan, err := vo.NewSyncTimerPlan(curTimer, desired)
+	if err != nil {
+		return setResult(err)
+	}
+
+	if cur == nil {
+		if req.DryRun {
+			return &result
+		}
+		if result.ID, err = f.createTimer(ctx, syncReq); err == nil && plan.Status == consts.Enabled {
+			err = f.setSyncStatus(consts.BulkEnable, f.timers[result.ID])
+		}
+		return setResult(err)
+	}
+
+	result.Diff = plan.Diff
+	if len(plan.Diff) == 0 {
+		result.Action, result.Result = consts.SyncUnchanged, consts.BulkSkipped
+		return &result
+	}
+	if req.DryRun {
+		return &result
+	}
+
+	if plan.Update {
+		syncReq.ID = cur.ID
+		if err := f.updateTimer(ctx, syncReq); err != nil {
+			return setResult(err)
+		}
+	}
+	switch plan.Status {
+	case consts.Enabled:
+		err = f.setSyncStatus(consts.BulkEnable, f.timers[cur.ID])
+	case consts.Unabled:
+		err = f.setSyncStatus(consts.BulkDisable, f.timers[cur.ID])
+	}
+	return setResult(err)
+}
+
+// setSyncStatus 复用批量操作的状态流转，无法变更时返回原因
+func (f *Fake) setSyncStatus(action consts.BulkAction, pTimer *po.Timer) error {
+	result := f.bulkTimer(action, false, pTimer)
+	if result.Result != consts.BulkSucceeded {
+		return newFakeError(http.StatusOK, result.Reason)
+	}
+	return nil
+}
+
+func (f *Fake) deleteTimer(id uint) {
+	delete(f.timers, id)
+	delete(f.labels, id)
+	delete(f.versions, id)
+}
+
+func (f *Fake) getTimer(app string, id uint) (*po.Timer, error) {
+	pTimer, ok := f.timers[id]
+	if !ok {
+		return nil, newFakeError(http.StatusOK, recordNotFoundMsg)
+	}
+	if pTimer.App != app {
+		return nil, newFakeError(http.StatusOK, fmt.Sprintf("timer not belongs to app: %s, timer id: %d", app, id))
+	}
+	return pTimer, nil
+}
+
+func (f *Fake) getVersion(timerID uint, version int) *po.TimerVersion {
+	for _, timerVersion := range f.versions[timerID] {
+		if timerVersion.Version == version {
+			return timerVersion
+		}
+	}
+	return nil
+}
+
+func (f *Fake) createVersion(ctx context.Context, pTimer *po.Timer) error {
+	snapshot, err := vo.NewTimerSnapshot(pTimer)
+	if err != nil {
+		return err
+	}
+	f.versions[pTimer.ID] = append(f.versions[pTimer.ID], &po.TimerVersion{
+		App:       pTimer.App,
+		TimerID:   pTimer.ID,
+		Version:   pTimer.Version,
+		Snapshot:  snapshot,
+		Actor:     vo.PrincipalFromContext(ctx).Actor(),
+		CreatedAt: time.Now(),
+	})
+	return nil
+}
+
+// sortedTimers 按 id 排序的全部定时器，与服务端列表接口的顺序一致
+func (f *Fake) sortedTimers(asc bool) []*po.Timer {
+	timers := make([]*po.Timer, 0, len(f.timers))
+	for _, pTimer := range f.timers {
+		timers = append(timers, pTimer)
+	}
+	sort.Slice(timers, func(i, j int) bool {
+		if asc {
+			return timers[i].ID < timers[j].ID
+		}
+		return timers[i].ID > timers[j].ID
+	})
+	return timers
+}
+
+// toPO 与服务端相同的参数校验，失败时返回业务错误
+func (f *Fake) toPO(timer *vo.Timer) (*po.Timer, error) {
+	t := *timer
+	pTimer, err := t.ToPO()
+	if err != nil {
+		return nil, newFakeError(http.StatusOK, err.Error())
+	}
+	if _, err := pTimer.Location(); err != nil {
+		return nil, newFakeError(http.StatusOK, fmt.Sprintf("非法的时区: %s", pTimer.Timezone))
+	}
+	if !pTimer.IsOnce() && !f.cronParser.IsValidCronExpr(pTimer.Cron) {
+		return nil, newFakeError(http.StatusOK, fmt.Sprintf("非法的 cron 表达式: %s", pTimer.Cron))
+	}
+	if pTimer.JitterSeconds > 0 && !pTimer.IsOnce() {
+		expr, err := f.cronParser.ResolveHash(pTimer.Cron, uint64(pTimer.ID))
+		if err != nil {
+			return nil, newFakeError(http.StatusOK, err.Error())
+		}
+		interval, err := f.cronParser.MinInterval(expr, fakeCronIntervalSamples)
+		if err != nil {
+			return nil, newFakeError(http.StatusOK, err.Error())
+		}
+		if interval > 0 && time.Duration(pTimer.JitterSeconds)*time.Second >= interval {
+			return nil, newFakeError(http.StatusOK, fmt.Sprintf("jitterSeconds: %d should be less than the min cron interval: %v", pTimer.JitterSeconds, interval))
+		}
+	}
+	return pTimer, nil
+}
+
+func (f *Fake) toVO(pTimer *po.Timer) (*vo.Timer, error) {
+	vTimer, err := vo.NewTimer(pTimer)
+	if err != nil {
+		return nil, err
+	}
+	vTimer.Labels = copyLabels(f.labels[pTimer.ID])
+	return vTimer, nil
+}
+
+func newFakeError(httpStatus int, msg string) *APIError {
+	return &APIError{HTTPStatus: httpStatus, Code: -1, Msg: msg}
+}
+
+func page[T any](items []T, limiter vo.PageLimiter) ([]T, int64) {
+	total := int64(len(items))
+	offset, limit := limiter.Get()
+	if offset >= len(items) {
+		return []T{}, total
+	}
+	if end := offset + limit; end < len(items) {
+		return items[offset:end], total
+	}
+	return items[offset:], total
+}
+
+// fakeSyncTimerReq 声明中没有标签表示清空标签，激活状态由同步单独调整
+func fakeSyncTimerReq(app string, timer *vo.Timer) *vo.Timer {
+	req := *timer
+	req.ID, req.App, req.Status, req.Version = 0, app, 0, 0
+	req.Labels = make(map[string]string, len(timer.Labels))
+	for k, v := range timer.Labels {
+		req.Labels[k] = v
+	}
+	return &req
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	cp := make(map[string]string, len(labels))
+	for k, v := range labels {
+		cp[k] = v
+	}
+	return cp
+}
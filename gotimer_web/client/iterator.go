@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+
+	"gotimer_web/common/model/vo"
+)
+
+const defaultIteratorPageSize = 100
+
+// TimerIterator 按页遍历应用下的定时器，用法与 bufio.Scanner 相同：
+//
+//	it := client.NewTimerIterator(c, vo.GetAppTimersReq{App: "demo"})
+//	for it.Next(ctx) {
+//		timer := it.Timer()
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+//
+// 遍历过程中有定时器创建或删除时，页边界上的定时器可能被重复返回或遗漏
+type TimerIterator struct {
+	client Client
+	req    vo.GetAppTimersReq
+	page   []*vo.Timer
+	cur    *vo.Timer
+	seen   int64
+	total  int64
+	done   bool
+	err    error
+}
+
+// NewTimerIterator 从 req 指定的页开始遍历，未指定页大小时每页 100 条
+func NewTimerIterator(client Client, req vo.GetAppTimersReq) *TimerIterator {
+	if req.Size <= 0 {
+		req.Size = defaultIteratorPageSize
+	}
+	if req.Index <= 0 {
+		req.Index = 1
+	}
+	return &TimerIterator{client: client, req: req, seen: int64((req.Index - 1) * req.Size)}
+}
+
+// Next 移动到下一个定时器，遍历结束或出错时返回 false
+func (it *TimerIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if len(it.page) == 0 && !it.done {
+		req := it.req
+		timers, total, err := it.client.GetAppTimers(ctx, &req)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page, it.total = timers, total
+		it.req.Index++
+		it.seen += int64(len(timers))
+		it.done = len(timers) < it.req.Size || it.seen >= total
+	}
+	if len(it.page) == 0 {
+		return false
+	}
+	it.cur, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+func (it *TimerIterator) Timer() *vo.Timer { return it.cur }
+
+// Total 最近一次查询时命中的定时器总数
+func (it *TimerIterator) Total() int64 { return it.total }
+
+func (it *TimerIterator) Err() error { return it.err }
+
+// TaskIterator 按页遍历定时器的执行记录，用法同 TimerIterator
+type TaskIterator struct {
+	client Client
+	req    vo.GetTasksReq
+	page   []*vo.Task
+	cur    *vo.Task
+	seen   int64
+	total  int64
+	done   bool
+	err    error
+}
+
+// NewTaskIterator 从 req 指定的页开始遍历，未指定页大小时每页 100 条
+func NewTaskIterator(client Client, req vo.GetTasksReq) *TaskIterator {
+	if req.Size <= 0 {
+		req.Size = defaultIteratorPageSize
+	}
+	if req.Index <= 0 {
+		req.Index = 1
+	}
+	return &TaskIterator{client: client, req: req, seen: int64((req.Index - 1) * req.Size)}
+}
+
+// Next 移动到下一条执行记录，遍历结束或出错时返回 false
+func (it *TaskIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if len(it.page) == 0 && !it.done {
+		req := it.req
+		tasks, total, err := it.client.GetTasks(ctx, &req)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page, it.total = tasks, total
+		it.req.Index++
+		it.seen += int64(len(tasks))
+		it.done = len(tasks) < it.req.Size || it.seen >= total
+	}
+	if len(it.page) == 0 {
+		return false
+	}
+	it.cur, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+func (it *TaskIterator) Task() *vo.Task { return it.cur }
+
+// Total 最近一次查询时命中的执行记录总数
+func (it *TaskIterator) Total() int64 { return it.total }
+
+func (it *TaskIterator) Err() error { return it.err }
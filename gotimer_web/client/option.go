@@ -0,0 +1,81 @@
+package client
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	defaultTimeoutDuration = 10 * time.Second
+	defaultMaxAttempts     = 3
+	defaultBackoff         = 200 * time.Millisecond
+	defaultMaxBackoff      = 2 * time.Second
+	// 单次读取限制 4M
+	defaultReadLimitBytes = 4 * 1024 * 1024
+)
+
+type Option func(*HTTPClient)
+
+// WithAPIKey 以 X-Api-Key 请求头携带凭证，服务端未开启鉴权时可不设置
+func WithAPIKey(apiKey string) Option {
+	return func(c *HTTPClient) {
+		c.apiKey = apiKey
+	}
+}
+
+// WithHTTPClient 自定义底层的 http.Client，如设置代理、TLS 或超时
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *HTTPClient) {
+		c.httpClient = httpClient
+	}
+}
+
+func WithTimeout(duration time.Duration) Option {
+	if duration <= 0 {
+		duration = defaultTimeoutDuration
+	}
+	return func(c *HTTPClient) {
+		c.httpClient = &http.Client{Timeout: duration}
+	}
+}
+
+// WithRetry maxAttempts 为最大请求次数，包含首次请求，为 1 时不重试；backoff 为首次重试前的等待时间，之后每次翻倍
+func WithRetry(maxAttempts int, backoff time.Duration) Option {
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	if backoff <= 0 {
+		backoff = defaultBackoff
+	}
+	return func(c *HTTPClient) {
+		c.maxAttempts = maxAttempts
+		c.backoff = backoff
+	}
+}
+
+func WithReadLimitBytes(limit int64) Option {
+	if limit <= 0 {
+		limit = defaultReadLimitBytes
+	}
+	return func(c *HTTPClient) {
+		c.readLimitBytes = limit
+	}
+}
+
+func repair(c *HTTPClient) {
+	if c.httpClient == nil {
+		WithTimeout(defaultTimeoutDuration)(c)
+	}
+	if c.maxAttempts <= 0 {
+		WithRetry(defaultMaxAttempts, defaultBackoff)(c)
+	}
+	if c.maxBackoff <= 0 {
+		c.maxBackoff = defaultMaxBackoff
+	}
+	if c.maxBackoff < c.backoff {
+		c.maxBackoff = c.backoff
+	}
+	if c.readLimitBytes <= 0 {
+		WithReadLimitBytes(defaultReadLimitBytes)(c)
+	}
+}
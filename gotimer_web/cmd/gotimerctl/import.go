@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"gotimer_web/common/consts"
+	"gotimer_web/common/model/vo"
+	"gotimer_web/pkg/crontab"
+)
+
+const (
+	importCrontab = "crontab"
+	importCronJob = "cronjob"
+)
+
+// CronJob 的并发策略与定时器的并发策略一一对应
+var cronJobConcurrencyPolicies = map[string]consts.ConcurrencyPolicy{
+	"Allow":   consts.ConcurrencyAllow,
+	"Forbid":  consts.ConcurrencyForbid,
+	"Replace": consts.ConcurrencyReplace,
+}
+
+// kvFlag 可重复指定的 key=value 参数
+type kvFlag map[string]string
+
+func (f kvFlag) String() string {
+	return labels(f)
+}
+
+func (f kvFlag) Set(value string) error {
+	k, v, ok := strings.Cut(value, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("expect key=value, got: %s", value)
+	}
+	f[k] = v
+	return nil
+}
+
+// importTemplates 回调参数和名称的模板，模板的参数为 crontab.Entry
+type importTemplates struct {
+	name *template.Template
+	url  *template.Template
+	body *template.Template
+}
+
+func newImportTemplates(name, url, body string) (*importTemplates, error) {
+	funcs := template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}
+	var t importTemplates
+	for _, item := range []struct {
+		dst  **template.Template
+		name string
+		text string
+	}{{&t.name, "name", name}, {&t.url, "url", url}, {&t.body, "body", body}} {
+		tmpl, err := template.New(item.name).Funcs(funcs).Option("missingkey=error").Parse(item.text)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s template failed, err: %w", item.name, err)
+		}
+		*item.dst = tmpl
+	}
+	return &t, nil
+}
+
+func execTemplate(tmpl *template.Template, entry *crontab.Entry) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, entry); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// runImport 将 crontab 文件和 CronJob 清单转换为声明式定义，无法转换的条目及原因输出到标准错误.
+// 输出的定义可直接交给 sync 命令预览和执行
+func runImport(_ context.Context, g *globalFlags, args []string) error {
+	fs := newFlagSet("import")
+	format := fs.String("format", "", "输入格式，crontab 或 cronjob，默认 .yaml、.yml 文件为 cronjob，其余为 crontab")
+	system := fs.Bool("system", false, "crontab 为 /etc/crontab、/etc/cron.d 的格式，命令之前多一列用户名")
+	name := fs.String("name", "{{.Name}}", "定时器名称的模板，参数为 crontab.Entry")
+	url := fs.String("url", "", "回调 url 的模板，如 http://runner.example.com/run?job={{.Name}}")
+	method := fs.String("method", http.MethodPost, "回调的 http 方法")
+	body := fs.String("body", `{"name":{{json .Name}},"command":{{json .Command}}}`, "回调请求体的模板")
+	enable := fs.Bool("enable", false, "同步时激活转换出的定时器，已暂停的 CronJob 始终为未激活")
+	output := fs.String("o", "-", "输出文件，- 表示标准输出")
+	headers, extraLabels := kvFlag{}, kvFlag{}
+	fs.Var(headers, "header", "回调的请求头，key=value，可重复指定")
+	fs.Var(extraLabels, "label", "为定时器添加的标签，key=value，可重复指定")
+	_ = fs.Parse(args)
+	if fs.NArg() == 0 || *url == "" {
+		fs.Usage()
+		return fmt.Errorf("expect -url and at least one file")
+	}
+	if err := g.requireApp(); err != nil {
+		return err
+	}
+	templates, err := newImportTemplates(*name, *url, *body)
+	if err != nil {
+		return err
+	}
+
+	var (
+		entries []*crontab.Entry
+		skipped []*crontab.Skipped
+	)
+	for _, file := range fs.Args() {
+		content, err := readFile(file)
+		if err != nil {
+			return err
+		}
+		fileFormat := *format
+		if fileFormat == "" {
+			fileFormat = importCrontab
+			if ext := filepath.Ext(file); ext == ".yaml" || ext == ".yml" {
+				fileFormat = importCronJob
+			}
+		}
+
+		var fileEntries []*crontab.Entry
+		var fileSkipped []*crontab.Skipped
+		switch fileFormat {
+		case importCrontab:
+			fileEntries, fileSkipped, err = crontab.ParseCrontab(file, bytes.NewReader(content), *system)
+		case importCronJob:
+			fileEntries, fileSkipped, err = crontab.ParseCronJobs(file, content)
+		default:
+			return fmt.Errorf("invalid format: %s, expect crontab or cronjob", fileFormat)
+		}
+		if err != nil {
+			return err
+		}
+		entries, skipped = append(entries, fileEntries...), append(skipped, fileSkipped...)
+	}
+
+	manifest := vo.TimerManifest{App: g.app, Timers: make([]*vo.Timer, 0, len(entries))}
+	names := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		timer, err := importTimer(templates, entry, *method, headers, extraLabels, *enable)
+		if err == nil && names[timer.Name] {
+			err = fmt.Errorf("duplicated timer name: %s, set -name to distinguish", timer.Name)
+		}
+		if err != nil {
+			skipped = append(skipped, &crontab.Skipped{Source: entry.Source, Line: entry.Line, Name: entry.Name, Reason: err.Error()})
+			continue
+		}
+		names[timer.Name] = true
+		manifest.Timers = append(manifest.Timers, timer)
+	}
+
+	out, err := manifest.Marshal(consts.ManifestYAML)
+	if err != nil {
+		return err
+	}
+	if *output == "-" {
+		_, err = os.Stdout.Write(out)
+	} else {
+		err = os.WriteFile(*output, out, 0644)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, s := range skipped {
+		fmt.Fprintf(os.Stderr, "skipped %s\n", s)
+	}
+	fmt.Fprintf(os.Stderr, "%d timers converted, %d entries skipped\n", len(manifest.Timers), len(skipped))
+	return nil
+}
+
+// importTimer 按模板生成单个定时器，并按创建接口的规则校验
+func importTimer(templates *importTemplates, entry *crontab.Entry, method string, headers, extraLabels map[string]string, enable bool) (*vo.Timer, error) {
+	name, err := execTemplate(templates.name, entry)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, fmt.Errorf("empty timer name")
+	}
+	url, err := execTemplate(templates.url, entry)
+	if err != nil {
+		return nil, err
+	}
+	body, err := execTemplate(templates.body, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	timer := vo.Timer{
+		Name:     name,
+		Type:     consts.CronTimer,
+		Cron:     entry.Cron,
+		Timezone: entry.Timezone,
+		NotifyHTTPParam: &vo.NotifyHTTPParam{
+			Method: method,
+			URL:    url,
+			Body:   body,
+		},
+		ConcurrencyPolicy: cronJobConcurrencyPolicies[entry.ConcurrencyPolicy],
+	}
+	if len(headers) > 0 {
+		timer.NotifyHTTPParam.Header = make(map[string]string, len(headers))
+		for k, v := range headers {
+			timer.NotifyHTTPParam.Header[k] = v
+		}
+	}
+	for _, src := range []map[string]string{entry.Labels, extraLabels} {
+		for k, v := range src {
+			if timer.Labels == nil {
+				timer.Labels = make(map[string]string)
+			}
+			timer.Labels[k] = v
+		}
+	}
+	switch {
+	case entry.Suspend:
+		timer.Status = consts.Unabled
+	case enable:
+		timer.Status = consts.Enabled
+	}
+
+	// ToPO 会补全默认值，在副本上校验，保持输出的定义简洁
+	check := timer
+	if _, err := check.ToPO(); err != nil {
+		return nil, err
+	}
+	return &timer, nil
+}
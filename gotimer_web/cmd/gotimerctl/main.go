@@ -0,0 +1,128 @@
+// gotimerctl 定时器服务的运维命令行工具.
+// 定时器相关命令通过 web 服务的 http 接口完成，redis 相关命令直接读取 redis，用于排查调度问题
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"gotimer_web/client"
+)
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, g *globalFlags, args []string) error
+}
+
+var commands map[string]*command
+
+// 子命令的 usage 引用了 commands，在 init 中注册避免初始化循环
+func init() {
+	commands = map[string]*command{
+		"list":    {usage: "list [-selector expr] [-name fuzzy]        列出应用下的定时器", run: runList},
+		"get":     {usage: "get <id>                                   查看定时器定义", run: runGet},
+		"create":  {usage: "create -f timer.yaml                       按 yaml 文件创建定时器", run: runCreate},
+		"enable":  {usage: "enable <id>...                             激活定时器", run: runEnable},
+		"disable": {usage: "disable <id>...                            去激活定时器", run: runDisable},
+		"delete":  {usage: "delete <id>...                             删除定时器", run: runDelete},
+		"run-now": {usage: "run-now <id>                               立即手动触发一次", run: runNow},
+		"tail":    {usage: "tail [-n 10] [-f] [-interval 5s] <id>      查看定时器最近的执行记录，-f 时持续输出", run: runTail},
+		"export":  {usage: "export [-format f] [-selector s] [-o file] 导出应用下定时器的声明式定义", run: runExport},
+		"sync":    {usage: "sync -f file [-prune -selector s] [-apply] 按声明式定义同步定时器，默认只输出变更计划", run: runSync},
+		"import":  {usage: "import -url tmpl [flags] file...           将 crontab 和 CronJob 转换为声明式定义", run: runImport},
+		"cron":    {usage: "cron [-n 10] [-tz zone] <expr>             试算 cron 表达式接下来的触发时机", run: runCron},
+		"bucket":  {usage: "bucket [-minute m] [-bucket b]             查看 {minute}_{bucket} 时间片中待触发的成员", run: runBucket},
+		"locks":   {usage: "locks [-pattern p]                         查看调度器和迁移器分布式锁的持有者", run: runLocks},
+	}
+}
+
+// globalFlags 全局参数，未指定时从环境变量读取
+type globalFlags struct {
+	server        string
+	apiKey        string
+	app           string
+	redisAddr     string
+	redisPassword string
+	buckets       int
+}
+
+func (g *globalFlags) client() client.Client {
+	return client.NewHTTPClient(g.server, client.WithAPIKey(g.apiKey))
+}
+
+func (g *globalFlags) requireApp() error {
+	if g.app == "" {
+		return fmt.Errorf("empty app, set -app or GOTIMER_APP")
+	}
+	return nil
+}
+
+func main() {
+	var g globalFlags
+	fs := flag.NewFlagSet("gotimerctl", flag.ExitOnError)
+	fs.StringVar(&g.server, "server", envOr("GOTIMER_SERVER", "http://127.0.0.1:8092"), "web 服务地址，环境变量 GOTIMER_SERVER")
+	fs.StringVar(&g.apiKey, "api-key", os.Getenv("GOTIMER_API_KEY"), "调用凭证，环境变量 GOTIMER_API_KEY")
+	fs.StringVar(&g.app, "app", os.Getenv("GOTIMER_APP"), "应用名，环境变量 GOTIMER_APP")
+	fs.StringVar(&g.redisAddr, "redis-addr", envOr("GOTIMER_REDIS_ADDR", "127.0.0.1:6379"), "redis 地址，环境变量 GOTIMER_REDIS_ADDR")
+	fs.StringVar(&g.redisPassword, "redis-password", os.Getenv("GOTIMER_REDIS_PASSWORD"), "redis 密码，环境变量 GOTIMER_REDIS_PASSWORD")
+	fs.IntVar(&g.buckets, "buckets", 10, "调度器的分桶数量，与 scheduler.bucketsNum 一致")
+	fs.Usage = func() { usage(fs) }
+	_ = fs.Parse(os.Args[1:])
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n\n", fs.Arg(0))
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	if err := cmd.run(ctx, &g, fs.Args()[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", fs.Arg(0), err)
+		cancel()
+		os.Exit(1)
+	}
+}
+
+func usage(fs *flag.FlagSet) {
+	fmt.Fprintln(os.Stderr, "usage: gotimerctl [global flags] <command> [flags] [args]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %s\n", commands[name].usage)
+	}
+	fmt.Fprintln(os.Stderr, "\nglobal flags:")
+	fs.PrintDefaults()
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// newFlagSet 子命令的参数，解析失败时直接退出
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: gotimerctl %s\n", strings.TrimSpace(commands[name].usage))
+		fs.PrintDefaults()
+	}
+	return fs
+}
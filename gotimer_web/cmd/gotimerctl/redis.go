@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"gotimer_web/common/consts"
+	"gotimer_web/common/utils"
+)
+
+// redisConn 直接建立单个连接，不依赖服务的 conf.yml
+func (g *globalFlags) redisConn(ctx context.Context) (redis.Conn, error) {
+	return redis.DialContext(ctx, "tcp", g.redisAddr, redis.DialPassword(g.redisPassword))
+}
+
+// runBucket 查看调度器某一分钟各个桶中待触发的成员，成员为 {timerID}_{unixMilli}，score 为执行时间
+func runBucket(ctx context.Context, g *globalFlags, args []string) error {
+	fs := newFlagSet("bucket")
+	minute := fs.String("minute", time.Now().Format(consts.MinuteFormat), "时间片所在的分钟，格式 \"2006-01-02 15:04\"，默认当前分钟")
+	bucket := fs.Int("bucket", -1, "桶 id，默认查看全部桶")
+	_ = fs.Parse(args)
+
+	t, err := time.ParseInLocation(consts.MinuteFormat, *minute, time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid minute: %s, expect format: %s", *minute, consts.MinuteFormat)
+	}
+	buckets := make([]int, 0, g.buckets)
+	if *bucket >= 0 {
+		buckets = append(buckets, *bucket)
+	} else {
+		for i := 0; i < g.buckets; i++ {
+			buckets = append(buckets, i)
+		}
+	}
+
+	conn, err := g.redisConn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tTIMER_ID\tRUN_AT\tMEMBER")
+	empty := 0
+	for _, bucketID := range buckets {
+		key := utils.GetSliceMsgKey(t, bucketID)
+		members, err := redis.Strings(conn.Do("ZRANGE", key, 0, -1, "WITHSCORES"))
+		if err != nil {
+			return fmt.Errorf("zrange %s failed, err: %w", key, err)
+		}
+		if len(members) == 0 {
+			empty++
+			continue
+		}
+		// WITHSCORES 的结果为 member、score 交替排列
+		for i := 0; i+1 < len(members); i += 2 {
+			runAt := members[i+1]
+			timerID, unix, err := utils.SplitTimerIDUnix(members[i])
+			if err == nil {
+				runAt = time.UnixMilli(unix).Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", key, timerID, runAt, members[i])
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("%d of %d buckets are empty\n", empty, len(buckets))
+	return nil
+}
+
+// runLocks 查看调度器时间片锁和迁移器锁的持有者，锁的值为持有者的 {pid}_{goroutineID}
+func runLocks(ctx context.Context, g *globalFlags, args []string) error {
+	fs := newFlagSet("locks")
+	pattern := fs.String("pattern", "", "自定义锁 key 的匹配模式，不含锁前缀，如 time_bucket_lock_2024-01-01 10:*，默认查看全部时间片锁和迁移器锁")
+	_ = fs.Parse(args)
+
+	patterns := []string{utils.GetDistributionLockKey("time_bucket_lock_*"), utils.GetDistributionLockKey("migrator_lock_*")}
+	if *pattern != "" {
+		patterns = []string{utils.GetDistributionLockKey(*pattern)}
+	}
+
+	conn, err := g.redisConn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var keys []string
+	for _, p := range patterns {
+		matched, err := scanKeys(conn, p)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, matched...)
+	}
+	sort.Strings(keys)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tHOLDER\tTTL")
+	for _, key := range keys {
+		holder, err := redis.String(conn.Do("GET", key))
+		if err == redis.ErrNil {
+			// 扫描到之后锁已过期
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("get %s failed, err: %w", key, err)
+		}
+		ttl, err := redis.Int64(conn.Do("TTL", key))
+		if err != nil {
+			return fmt.Errorf("ttl %s failed, err: %w", key, err)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", strings.TrimPrefix(key, utils.GetDistributionLockKey("")), describeHolder(holder), describeTTL(ttl))
+	}
+	return w.Flush()
+}
+
+// scanKeys 以 SCAN 遍历匹配的 key，避免 KEYS 阻塞 redis
+func scanKeys(conn redis.Conn, pattern string) ([]string, error) {
+	var keys []string
+	cursor := "0"
+	for {
+		reply, err := redis.Values(conn.Do("SCAN", cursor, "MATCH", pattern, "COUNT", 1000))
+		if err != nil {
+			return nil, fmt.Errorf("scan %s failed, err: %w", pattern, err)
+		}
+		if cursor, err = redis.String(reply[0], nil); err != nil {
+			return nil, err
+		}
+		batch, err := redis.Strings(reply[1], nil)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, batch...)
+		if cursor == "0" {
+			return keys, nil
+		}
+	}
+}
+
+func describeHolder(holder string) string {
+	pid, goroutineID, ok := strings.Cut(holder, "_")
+	if !ok {
+		return holder
+	}
+	return fmt.Sprintf("pid=%s goroutine=%s", pid, goroutineID)
+}
+
+func describeTTL(ttl int64) string {
+	if ttl < 0 {
+		return "no expire"
+	}
+	return (time.Duration(ttl) * time.Second).String()
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"gotimer_web/common/consts"
+	"gotimer_web/common/model/vo"
+)
+
+func runExport(ctx context.Context, g *globalFlags, args []string) error {
+	fs := newFlagSet("export")
+	format := fs.String("format", consts.ManifestYAML, "输出格式，yaml 或 json")
+	labelSelector := fs.String("selector", "", "标签选择器，只导出匹配的定时器")
+	output := fs.String("o", "-", "输出文件，- 表示标准输出")
+	_ = fs.Parse(args)
+	if err := g.requireApp(); err != nil {
+		return err
+	}
+
+	manifest, err := g.client().ExportTimers(ctx, &vo.ExportTimersReq{App: g.app, Selector: *labelSelector})
+	if err != nil {
+		return err
+	}
+	body, err := manifest.Marshal(*format)
+	if err != nil {
+		return err
+	}
+	if *output == "-" {
+		_, err = os.Stdout.Write(body)
+		return err
+	}
+	if err := os.WriteFile(*output, body, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("%d timers exported to %s\n", len(manifest.Timers), *output)
+	return nil
+}
+
+// runSync 默认以 dry-run 方式输出变更计划，确认无误后加 -apply 执行
+func runSync(ctx context.Context, g *globalFlags, args []string) error {
+	fs := newFlagSet("sync")
+	file := fs.String("f", "", "声明式定义的 yaml 或 json 文件，- 表示标准输入")
+	prune := fs.Bool("prune", false, "删除声明中不存在的定时器")
+	labelSelector := fs.String("selector", "", "标签选择器，-prune 时只删除匹配的定时器")
+	apply := fs.Bool("apply", false, "执行变更，默认只输出变更计划")
+	_ = fs.Parse(args)
+	if *file == "" {
+		fs.Usage()
+		return fmt.Errorf("empty file")
+	}
+
+	body, err := readFile(*file)
+	if err != nil {
+		return err
+	}
+	manifest, err := vo.UnmarshalTimerManifest(body)
+	if err != nil {
+		return fmt.Errorf("parse %s failed, err: %w", *file, err)
+	}
+	if manifest.App == "" {
+		manifest.App = g.app
+	}
+	if g.app != "" && manifest.App != g.app {
+		return fmt.Errorf("manifest belongs to app: %s, not app: %s", manifest.App, g.app)
+	}
+
+	report, err := g.client().SyncTimers(ctx, &vo.SyncTimersReq{
+		TimerManifest: *manifest,
+		Prune:         *prune,
+		Selector:      *labelSelector,
+		DryRun:        !*apply,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, result := range report.Results {
+		printSyncResult(result)
+	}
+	fmt.Printf("\n%d to create, %d to update, %d to delete, %d unchanged, %d failed\n",
+		report.Created, report.Updated, report.Deleted, report.Unchanged, report.Failed)
+	if report.DryRun {
+		fmt.Println("dry run, re-run with -apply to make the changes")
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d timers failed", report.Failed)
+	}
+	return nil
+}
+
+// printSyncResult 按 +、~、- 标记新建、更新和删除，更新时逐个输出有差异的字段
+func printSyncResult(result *vo.SyncTimerResult) {
+	var mark string
+	switch result.Action {
+	case consts.SyncCreate:
+		mark = "+"
+	case consts.SyncUpdate:
+		mark = "~"
+	case consts.SyncDelete:
+		mark = "-"
+	default:
+		return
+	}
+
+	line := fmt.Sprintf("%s %s", mark, result.Name)
+	if result.ID > 0 {
+		line += fmt.Sprintf(" (id: %d)", result.ID)
+	}
+	if result.Result == consts.BulkFailed {
+		line += "  failed: " + result.Reason
+	}
+	fmt.Println(line)
+
+	fields := make([]string, 0, len(result.Diff))
+	for field := range result.Diff {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		diff := result.Diff[field]
+		fmt.Printf("    %s: %s -> %s\n", field, diffValue(diff.Before), diffValue(diff.After))
+	}
+}
+
+func diffValue(v interface{}) string {
+	if v == nil {
+		return "<none>"
+	}
+	body, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(body)
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gotimer_web/client"
+	"gotimer_web/common/consts"
+	"gotimer_web/common/model/vo"
+)
+
+func runList(ctx context.Context, g *globalFlags, args []string) error {
+	fs := newFlagSet("list")
+	labelSelector := fs.String("selector", "", "标签选择器，如 team=billing,env!=dev")
+	name := fs.String("name", "", "按名称模糊匹配")
+	_ = fs.Parse(args)
+	if err := g.requireApp(); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSTATUS\tSCHEDULE\tVERSION\tLABELS")
+	printTimer := func(timer *vo.Timer) {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s\n", timer.ID, timer.Name, timerStatus(timer.Status), schedule(timer), timer.Version, labels(timer.Labels))
+	}
+
+	c := g.client()
+	if *name == "" {
+		it := client.NewTimerIterator(c, vo.GetAppTimersReq{App: g.app, Selector: *labelSelector})
+		for it.Next(ctx) {
+			printTimer(it.Timer())
+		}
+		if err := it.Err(); err != nil {
+			return err
+		}
+		return w.Flush()
+	}
+
+	// 按名称查询没有迭代器，逐页查询直到取完
+	req := vo.GetTimersByNameReq{App: g.app, FuzzyName: *name, Selector: *labelSelector, PageLimiter: vo.PageLimiter{Index: 1, Size: 100}}
+	for {
+		timers, total, err := c.GetTimersByName(ctx, &req)
+		if err != nil {
+			return err
+		}
+		for _, timer := range timers {
+			printTimer(timer)
+		}
+		if len(timers) < req.Size || int64(req.Index*req.Size) >= total {
+			break
+		}
+		req.Index++
+	}
+	return w.Flush()
+}
+
+func runGet(ctx context.Context, g *globalFlags, args []string) error {
+	fs := newFlagSet("get")
+	_ = fs.Parse(args)
+	ids, err := parseIDs(fs.Args(), 1)
+	if err != nil {
+		return err
+	}
+	if err := g.requireApp(); err != nil {
+		return err
+	}
+
+	timer, err := g.client().GetTimer(ctx, g.app, ids[0])
+	if err != nil {
+		return err
+	}
+	return printJSON(timer)
+}
+
+func runCreate(ctx context.Context, g *globalFlags, args []string) error {
+	fs := newFlagSet("create")
+	file := fs.String("f", "", "定时器定义的 yaml 文件，字段与 http 接口的 json 字段一致，- 表示标准输入")
+	_ = fs.Parse(args)
+	if *file == "" {
+		fs.Usage()
+		return fmt.Errorf("empty file")
+	}
+
+	body, err := readFile(*file)
+	if err != nil {
+		return err
+	}
+	var timer vo.Timer
+	if err := vo.UnmarshalYAML(body, &timer); err != nil {
+		return fmt.Errorf("parse %s failed, err: %w", *file, err)
+	}
+	if timer.App == "" {
+		timer.App = g.app
+	}
+
+	id, err := g.client().CreateTimer(ctx, &timer)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("timer created, id: %d\n", id)
+	return nil
+}
+
+func runEnable(ctx context.Context, g *globalFlags, args []string) error {
+	return forEachTimer(ctx, g, "enable", args, g.client().EnableTimer)
+}
+
+func runDisable(ctx context.Context, g *globalFlags, args []string) error {
+	return forEachTimer(ctx, g, "disable", args, g.client().UnableTimer)
+}
+
+func runDelete(ctx context.Context, g *globalFlags, args []string) error {
+	return forEachTimer(ctx, g, "delete", args, g.client().DeleteTimer)
+}
+
+// forEachTimer 逐个处理 args 中的定时器，单个失败不影响其余定时器
+func forEachTimer(ctx context.Context, g *globalFlags, name string, args []string, do func(ctx context.Context, app string, id uint) error) error {
+	fs := newFlagSet(name)
+	_ = fs.Parse(args)
+	ids, err := parseIDs(fs.Args(), -1)
+	if err != nil {
+		return err
+	}
+	if err := g.requireApp(); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, id := range ids {
+		if err := do(ctx, g.app, id); err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "timer %d: %v\n", id, err)
+			continue
+		}
+		fmt.Printf("timer %d: %sd\n", id, strings.TrimSuffix(name, "e"))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d timers failed", failed, len(ids))
+	}
+	return nil
+}
+
+func runNow(ctx context.Context, g *globalFlags, args []string) error {
+	fs := newFlagSet("run-now")
+	_ = fs.Parse(args)
+	ids, err := parseIDs(fs.Args(), 1)
+	if err != nil {
+		return err
+	}
+	if err := g.requireApp(); err != nil {
+		return err
+	}
+
+	taskID, err := g.client().RunTimer(ctx, g.app, ids[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("timer %d triggered, task id: %d\n", ids[0], taskID)
+	return nil
+}
+
+// runTail 输出最近的执行记录，-f 时定期轮询，新出现或状态变化的记录再次输出
+func runTail(ctx context.Context, g *globalFlags, args []string) error {
+	fs := newFlagSet("tail")
+	n := fs.Int("n", 10, "首次输出的记录条数")
+	follow := fs.Bool("f", false, "持续输出新的执行记录")
+	interval := fs.Duration("interval", 5*time.Second, "-f 时的轮询间隔")
+	_ = fs.Parse(args)
+	ids, err := parseIDs(fs.Args(), 1)
+	if err != nil {
+		return err
+	}
+	if err := g.requireApp(); err != nil {
+		return err
+	}
+
+	c := g.client()
+	req := vo.GetTasksReq{App: g.app, TimerID: ids[0], PageLimiter: vo.PageLimiter{Index: 1, Size: *n}}
+	seen := make(map[uint]int)
+	poll := func() error {
+		tasks, _, err := c.GetTasks(ctx, &req)
+		if err != nil {
+			return err
+		}
+		// 接口按执行时间倒序返回，按时间正序输出
+		for i := len(tasks) - 1; i >= 0; i-- {
+			task := tasks[i]
+			if status, ok := seen[task.ID]; ok && status == task.Status {
+				continue
+			}
+			seen[task.ID] = task.Status
+			printTask(task)
+		}
+		return nil
+	}
+
+	if err := poll(); err != nil || !*follow {
+		return err
+	}
+	// 轮询时多取一些，避免两次轮询之间的记录被遗漏
+	if req.Size < 50 {
+		req.Size = 50
+	}
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := poll(); err != nil {
+				fmt.Fprintf(os.Stderr, "poll tasks failed, err: %v\n", err)
+			}
+		}
+	}
+}
+
+func runCron(ctx context.Context, g *globalFlags, args []string) error {
+	fs := newFlagSet("cron")
+	n := fs.Int("n", 10, "试算的触发次数")
+	tz := fs.String("tz", "", "IANA 时区，如 Asia/Shanghai，默认服务器本地时区")
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expect exactly one cron expr, quote it in shell")
+	}
+
+	preview, err := g.client().PreviewCron(ctx, &vo.CronPreviewReq{Cron: fs.Arg(0), N: *n, TZ: *tz})
+	if err != nil {
+		return err
+	}
+	fmt.Println(preview.Description)
+	for _, next := range preview.Nexts {
+		fmt.Printf("  %s\n", next.Format(time.RFC3339))
+	}
+	for _, warning := range preview.Warnings {
+		fmt.Printf("warning: %s\n", warning)
+	}
+	return nil
+}
+
+func printTask(task *vo.Task) {
+	line := fmt.Sprintf("%s  task=%d  %-9s  attempt=%d  http=%d  cost=%dms  source=%s",
+		task.RunTimer.Format(time.RFC3339), task.ID, taskStatus(task.Status), task.Attempt, task.HTTPStatus, task.CostTime, taskSource(task.Source))
+	if task.FailReason != "" {
+		line += "  reason=" + task.FailReason
+	}
+	fmt.Println(line)
+}
+
+func parseIDs(args []string, expect int) ([]uint, error) {
+	if expect > 0 && len(args) != expect {
+		return nil, fmt.Errorf("expect %d timer id, got: %d", expect, len(args))
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("expect at least one timer id")
+	}
+	ids := make([]uint, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("invalid timer id: %s", arg)
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
+
+func readFile(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func schedule(timer *vo.Timer) string {
+	if timer.Type == consts.OnceTimer && timer.RunAt != nil {
+		return "at " + timer.RunAt.Format(time.RFC3339)
+	}
+	if timer.Timezone != "" {
+		return fmt.Sprintf("%s (%s)", timer.Cron, timer.Timezone)
+	}
+	return timer.Cron
+}
+
+func labels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func timerStatus(status consts.TimerStatus) string {
+	switch status {
+	case consts.Unabled:
+		return "unabled"
+	case consts.Enabled:
+		return "enabled"
+	case consts.Finished:
+		return "finished"
+	}
+	return strconv.Itoa(status.ToInt())
+}
+
+func taskStatus(status int) string {
+	switch consts.TaskStatus(status) {
+	case consts.NotRunned:
+		return "pending"
+	case consts.Running:
+		return "running"
+	case consts.Successed:
+		return "succeeded"
+	case consts.Failed:
+		return "failed"
+	case consts.Cancelled:
+		return "cancelled"
+	case consts.Retrying:
+		return "retrying"
+	case consts.Missed:
+		return "missed"
+	case consts.Skipped:
+		return "skipped"
+	}
+	return strconv.Itoa(status)
+}
+
+func taskSource(source int) string {
+	if source == consts.ManualTrigger.ToInt() {
+		return "manual"
+	}
+	return "schedule"
+}
@@ -70,6 +70,14 @@ var gConf GloablConf = GloablConf{
 
 	WebServer: &WebServerAppConf{
 		Port: 8092,
+		// 是否开启接口认证，关闭时所有调用方均视为管理员
+		EnableAuth: true,
+		// 管理员初始凭证，用于签发其他凭证，为空时只能使用库中的凭证
+		AdminToken: "",
+		// gRPC 服务端口，为 0 时不启动
+		GRPCPort: 9092,
+		// 允许跨域访问的来源，默认不允许
+		AllowedOrigins: nil,
 	},
 	Redis: &RedisConfig{
 		Network: "tcp",
@@ -1,7 +1,12 @@
 package conf
 
 type WebServerAppConf struct {
-	Port int `yaml:"port"`
+	Port       int    `yaml:"port"`
+	EnableAuth bool   `yaml:"enableAuth"`
+	AdminToken string `yaml:"adminToken"`
+	GRPCPort   int    `yaml:"grpcPort"`
+	// 允许跨域访问的来源，如 https://console.example.com，为空时不允许跨域访问
+	AllowedOrigins []string `yaml:"allowedOrigins"`
 }
 
 var defaultWebServerAppConfProvider *WebServerAppConfProvider
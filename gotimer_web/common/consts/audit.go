@@ -0,0 +1,15 @@
+package consts
+
+// AuditAction 审计日志记录的定时器操作
+type AuditAction string
+
+const (
+	AuditCreate   AuditAction = "create"
+	AuditUpdate   AuditAction = "update"
+	AuditDelete   AuditAction = "delete"
+	AuditEnable   AuditAction = "enable"
+	AuditDisable  AuditAction = "disable"
+	AuditRollback AuditAction = "rollback"
+	// 按声明式定义同步
+	AuditSync AuditAction = "sync"
+)
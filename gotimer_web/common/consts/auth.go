@@ -0,0 +1,15 @@
+package consts
+
+// Role 调用方角色
+type Role int
+
+func (r Role) ToInt() int {
+	return int(r)
+}
+
+const (
+	// 只能操作所属 app 的定时器
+	AppRole Role = 1
+	// 管理员，可以跨 app 操作
+	AdminRole Role = 2
+)
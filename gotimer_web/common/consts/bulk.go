@@ -0,0 +1,21 @@
+package consts
+
+// BulkAction 批量操作的类型
+type BulkAction string
+
+const (
+	BulkEnable  BulkAction = "enable"
+	BulkDisable BulkAction = "disable"
+	BulkDelete  BulkAction = "delete"
+)
+
+// BulkResult 批量操作中单个定时器的处理结果
+type BulkResult string
+
+const (
+	BulkSucceeded BulkResult = "succeeded"
+	BulkSkipped   BulkResult = "skipped"
+	BulkFailed    BulkResult = "failed"
+	// dry-run 时表示将被变更
+	BulkPlanned BulkResult = "planned"
+)
@@ -0,0 +1,17 @@
+package consts
+
+// SyncAction 按声明式定义同步时单个定时器的变更类型
+type SyncAction string
+
+const (
+	SyncCreate    SyncAction = "create"
+	SyncUpdate    SyncAction = "update"
+	SyncDelete    SyncAction = "delete"
+	SyncUnchanged SyncAction = "unchanged"
+)
+
+// 导出声明式定义的文件格式
+const (
+	ManifestYAML = "yaml"
+	ManifestJSON = "json"
+)
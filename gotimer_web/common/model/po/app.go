@@ -0,0 +1,20 @@
+package po
+
+import "gorm.io/gorm"
+
+// App 接入方应用，配额字段为 0 时表示不限制
+type App struct {
+	gorm.Model
+	Name                   string `gorm:"column:name;NOT NULL"`                      // 应用名，与 timer.app 对应
+	Owner                  string `gorm:"column:owner;NOT NULL"`                     // 负责人
+	Contact                string `gorm:"column:contact"`                            // 联系方式
+	DefaultHeaders         string `gorm:"column:default_headers;default:null"`       // 回调默认请求头，json 格式
+	DefaultTimeoutSeconds  int    `gorm:"column:default_timeout_seconds;NOT NULL"`   // 回调默认超时时间，单位：s
+	MaxTimers              int    `gorm:"column:max_timers;NOT NULL"`                // 定时器数量上限
+	MaxEnabledTimers       int    `gorm:"column:max_enabled_timers;NOT NULL"`        // 激活态定时器数量上限
+	MinCronIntervalSeconds int    `gorm:"column:min_cron_interval_seconds;NOT NULL"` // cron 相邻两次触发的最小间隔，单位：s
+}
+
+func (a *App) TableName() string {
+	return "app"
+}
@@ -0,0 +1,18 @@
+package po
+
+import "time"
+
+// AuditLog 定时器变更的审计日志，只增不改
+type AuditLog struct {
+	ID        uint      `gorm:"column:id;primaryKey"`
+	App       string    `gorm:"column:app;NOT NULL"`      // 定时器所属应用名
+	TimerID   uint      `gorm:"column:timer_id;NOT NULL"` // 定时器 id
+	Actor     string    `gorm:"column:actor;NOT NULL"`    // 操作人
+	Action    string    `gorm:"column:action;NOT NULL"`   // 操作类型
+	Diff      string    `gorm:"column:diff"`              // 变更前后有差异的字段，json 格式
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (a *AuditLog) TableName() string {
+	return "audit_log"
+}
@@ -0,0 +1,16 @@
+package po
+
+import "gorm.io/gorm"
+
+// APIKey 调用 web 接口的凭证，库中只保存哈希值
+type APIKey struct {
+	gorm.Model
+	App     string `gorm:"column:app;NOT NULL"`      // 所属应用名，管理员可为空
+	Name    string `gorm:"column:name;NOT NULL"`     // 凭证用途说明
+	KeyHash string `gorm:"column:key_hash;NOT NULL"` // 凭证的 sha256 哈希
+	Role    int    `gorm:"column:role;NOT NULL"`     // 角色，1:app, 2:管理员
+}
+
+func (a *APIKey) TableName() string {
+	return "api_key"
+}
@@ -0,0 +1,17 @@
+package po
+
+import "gorm.io/gorm"
+
+// Calendar 节假日/停机窗口日历，被定时器引用后用于过滤执行时机
+type Calendar struct {
+	gorm.Model
+	App         string `gorm:"column:app;NOT NULL"`         // 所属应用名
+	Name        string `gorm:"column:name;NOT NULL"`        // 日历名
+	Description string `gorm:"column:description"`          // 日历说明
+	Timezone    string `gorm:"column:timezone"`             // 按日期配置的条目所在时区，为空时使用服务器本地时区
+	Entries     string `gorm:"column:entries;default:null"` // 日期或时间段列表，json 格式
+}
+
+func (c *Calendar) TableName() string {
+	return "calendar"
+}
@@ -0,0 +1,14 @@
+package po
+
+// TimerLabel 定时器的标签，一个键值对一行
+type TimerLabel struct {
+	ID      uint   `gorm:"column:id;primaryKey"`
+	App     string `gorm:"column:app;NOT NULL"`         // 定时器所属应用名
+	TimerID uint   `gorm:"column:timer_id;NOT NULL"`    // 定时器 id
+	Key     string `gorm:"column:label_key;NOT NULL"`   // 标签键
+	Value   string `gorm:"column:label_value;NOT NULL"` // 标签值
+}
+
+func (l *TimerLabel) TableName() string {
+	return "timer_label"
+}
@@ -0,0 +1,18 @@
+package po
+
+import "time"
+
+// TimerVersion 定时器定义的历史版本，只增不改
+type TimerVersion struct {
+	ID        uint      `gorm:"column:id;primaryKey"`
+	App       string    `gorm:"column:app;NOT NULL"`      // 定时器所属应用名
+	TimerID   uint      `gorm:"column:timer_id;NOT NULL"` // 定时器 id
+	Version   int       `gorm:"column:version;NOT NULL"`  // 版本号
+	Snapshot  string    `gorm:"column:snapshot;NOT NULL"` // 该版本的定时器定义，json 格式
+	Actor     string    `gorm:"column:actor;NOT NULL"`    // 操作人
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (v *TimerVersion) TableName() string {
+	return "timer_version"
+}
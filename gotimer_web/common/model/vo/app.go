@@ -0,0 +1,113 @@
+package vo
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gotimer_web/common/model/po"
+)
+
+// App 接入方应用，配额字段为 0 时表示不限制
+type App struct {
+	Name                   string            `json:"name" binding:"required"`          // 应用名
+	Owner                  string            `json:"owner" binding:"required"`         // 负责人
+	Contact                string            `json:"contact,omitempty"`                // 联系方式
+	DefaultHeaders         map[string]string `json:"defaultHeaders,omitempty"`         // 回调默认请求头，定时器未设置同名请求头时生效
+	DefaultTimeoutSeconds  int               `json:"defaultTimeoutSeconds,omitempty"`  // 回调默认超时时间，单位：s
+	MaxTimers              int               `json:"maxTimers,omitempty"`              // 定时器数量上限
+	MaxEnabledTimers       int               `json:"maxEnabledTimers,omitempty"`       // 激活态定时器数量上限
+	MinCronIntervalSeconds int               `json:"minCronIntervalSeconds,omitempty"` // cron 相邻两次触发的最小间隔，单位：s
+	CreatedAt              time.Time         `json:"createdAt"`
+	UpdatedAt              time.Time         `json:"updatedAt"`
+}
+
+func NewApp(app *po.App) (*App, error) {
+	var headers map[string]string
+	if app.DefaultHeaders != "" {
+		if err := json.Unmarshal([]byte(app.DefaultHeaders), &headers); err != nil {
+			return nil, err
+		}
+	}
+
+	return &App{
+		Name:                   app.Name,
+		Owner:                  app.Owner,
+		Contact:                app.Contact,
+		DefaultHeaders:         headers,
+		DefaultTimeoutSeconds:  app.DefaultTimeoutSeconds,
+		MaxTimers:              app.MaxTimers,
+		MaxEnabledTimers:       app.MaxEnabledTimers,
+		MinCronIntervalSeconds: app.MinCronIntervalSeconds,
+		CreatedAt:              app.CreatedAt,
+		UpdatedAt:              app.UpdatedAt,
+	}, nil
+}
+
+func (a *App) Check() error {
+	if a.DefaultTimeoutSeconds < 0 || a.MaxTimers < 0 || a.MaxEnabledTimers < 0 || a.MinCronIntervalSeconds < 0 {
+		return errors.New("default timeout and quotas of app can not be negative")
+	}
+	if a.MaxTimers > 0 && a.MaxEnabledTimers > a.MaxTimers {
+		return errors.New("maxEnabledTimers can not be greater than maxTimers")
+	}
+	return nil
+}
+
+func (a *App) ToPO() (*po.App, error) {
+	if err := a.Check(); err != nil {
+		return nil, err
+	}
+
+	app := po.App{
+		Name:                   a.Name,
+		Owner:                  a.Owner,
+		Contact:                a.Contact,
+		DefaultTimeoutSeconds:  a.DefaultTimeoutSeconds,
+		MaxTimers:              a.MaxTimers,
+		MaxEnabledTimers:       a.MaxEnabledTimers,
+		MinCronIntervalSeconds: a.MinCronIntervalSeconds,
+	}
+	if len(a.DefaultHeaders) > 0 {
+		headers, err := json.Marshal(a.DefaultHeaders)
+		if err != nil {
+			return nil, err
+		}
+		app.DefaultHeaders = string(headers)
+	}
+	return &app, nil
+}
+
+type AppReq struct {
+	Name string `form:"name" json:"name" binding:"required"`
+}
+
+type GetAppsReq struct {
+	PageLimiter
+}
+
+type GetAppResp struct {
+	CodeMsg
+	Data *App `json:"data"`
+}
+
+func NewGetAppResp(app *App, codeMsg CodeMsg) *GetAppResp {
+	return &GetAppResp{
+		CodeMsg: codeMsg,
+		Data:    app,
+	}
+}
+
+type GetAppsResp struct {
+	CodeMsg
+	Data  []*App `json:"data"`
+	Total int64  `json:"total"`
+}
+
+func NewGetAppsResp(apps []*App, total int64, codeMsg CodeMsg) *GetAppsResp {
+	return &GetAppsResp{
+		CodeMsg: codeMsg,
+		Data:    apps,
+		Total:   total,
+	}
+}
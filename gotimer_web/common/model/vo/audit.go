@@ -0,0 +1,134 @@
+package vo
+
+import (
+	"encoding/json"
+	"time"
+
+	"gotimer_web/common/model/po"
+)
+
+// 主键及创建、修改时间不参与比对
+var auditIgnoredFields = map[string]bool{
+	"ID":        true,
+	"CreatedAt": true,
+	"UpdatedAt": true,
+	"DeletedAt": true,
+}
+
+// FieldDiff 单个字段变更前后的取值
+type FieldDiff struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// NewAuditDiff 比对定时器变更前后有差异的字段，before 为 nil 表示新建，after 为 nil 表示删除
+func NewAuditDiff(before, after *po.Timer) (string, error) {
+	beforeFields, err := timerFields(before)
+	if err != nil {
+		return "", err
+	}
+	afterFields, err := timerFields(after)
+	if err != nil {
+		return "", err
+	}
+
+	diff := diffFields(beforeFields, afterFields)
+	for k := range diff {
+		if auditIgnoredFields[k] {
+			delete(diff, k)
+		}
+	}
+
+	body, err := json.Marshal(diff)
+	return string(body), err
+}
+
+// diffFields 比对两组字段，只保留取值不同的字段
+func diffFields(before, after map[string]interface{}) map[string]*FieldDiff {
+	diff := make(map[string]*FieldDiff)
+	for k, v := range before {
+		diff[k] = &FieldDiff{Before: v}
+	}
+	for k, v := range after {
+		if d, ok := diff[k]; ok {
+			d.After = v
+			continue
+		}
+		diff[k] = &FieldDiff{After: v}
+	}
+	for k, d := range diff {
+		if jsonEqual(d.Before, d.After) {
+			delete(diff, k)
+		}
+	}
+	return diff
+}
+
+func timerFields(timer *po.Timer) (map[string]interface{}, error) {
+	if timer == nil {
+		return nil, nil
+	}
+	body, err := json.Marshal(timer)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	return fields, json.Unmarshal(body, &fields)
+}
+
+func jsonEqual(a, b interface{}) bool {
+	aBody, _ := json.Marshal(a)
+	bBody, _ := json.Marshal(b)
+	return string(aBody) == string(bBody)
+}
+
+type AuditLog struct {
+	ID        uint            `json:"id"`
+	App       string          `json:"app"`
+	TimerID   uint            `json:"timerID"`
+	Actor     string          `json:"actor"`  // 操作人
+	Action    string          `json:"action"` // 操作类型，create/update/delete/enable/disable/rollback/sync
+	Diff      json.RawMessage `json:"diff"`   // 变更前后有差异的字段
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+func NewAuditLogs(logs []*po.AuditLog) []*AuditLog {
+	vLogs := make([]*AuditLog, 0, len(logs))
+	for _, log := range logs {
+		vLog := AuditLog{
+			ID:        log.ID,
+			App:       log.App,
+			TimerID:   log.TimerID,
+			Actor:     log.Actor,
+			Action:    log.Action,
+			CreatedAt: log.CreatedAt,
+		}
+		if log.Diff != "" {
+			vLog.Diff = json.RawMessage(log.Diff)
+		}
+		vLogs = append(vLogs, &vLog)
+	}
+	return vLogs
+}
+
+type GetAuditLogsReq struct {
+	PageLimiter
+	App     string    `form:"app"`                      // 应用名，非管理员调用时以凭证所属应用为准
+	TimerID uint      `form:"timerID"`                  // 定时器 id
+	Start   time.Time `form:"start" time_format:"unix"` // 操作时间下限，unix 秒
+	End     time.Time `form:"end" time_format:"unix"`   // 操作时间上限，unix 秒
+}
+
+type GetAuditLogsResp struct {
+	CodeMsg
+	Data  []*AuditLog `json:"data"`
+	Total int64       `json:"total"`
+}
+
+func NewGetAuditLogsResp(logs []*AuditLog, total int64, codeMsg CodeMsg) *GetAuditLogsResp {
+	return &GetAuditLogsResp{
+		CodeMsg: codeMsg,
+		Data:    logs,
+		Total:   total,
+	}
+}
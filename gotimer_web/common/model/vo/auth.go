@@ -0,0 +1,109 @@
+package vo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gotimer_web/common/consts"
+	"gotimer_web/common/model/po"
+)
+
+// Principal 通过认证的调用方
+type Principal struct {
+	App     string      `json:"app"`
+	Role    consts.Role `json:"role"`
+	KeyID   uint        `json:"keyID,omitempty"` // 凭证 id，配置中的管理员凭证及未开启认证时为 0
+	KeyName string      `json:"keyName"`         // 凭证用途说明
+}
+
+type principalCtxKey struct{}
+
+// WithPrincipal 将调用方身份写入 ctx，供 service 层记录操作人
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalCtxKey{}, principal)
+}
+
+// PrincipalFromContext 取出 ctx 中的调用方身份，不存在时返回 nil
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalCtxKey{}).(*Principal)
+	return principal
+}
+
+// Actor 审计日志中记录的操作人
+func (p *Principal) Actor() string {
+	if p == nil {
+		return "system"
+	}
+	if p.KeyID == 0 {
+		return p.KeyName
+	}
+	return fmt.Sprintf("%s(key#%d)", p.KeyName, p.KeyID)
+}
+
+func (p *Principal) IsAdmin() bool {
+	return p.Role == consts.AdminRole
+}
+
+// CanAccess 调用方是否有权操作 app 下的资源
+func (p *Principal) CanAccess(app string) bool {
+	return p.IsAdmin() || p.App == app
+}
+
+type CreateAPIKeyReq struct {
+	App  string      `json:"app"`                     // 所属应用名，管理员凭证可为空
+	Name string      `json:"name" binding:"required"` // 凭证用途说明
+	Role consts.Role `json:"role"`                    // 角色，1:app(默认), 2:管理员
+}
+
+type GetAPIKeysReq struct {
+	App string `form:"app"`
+}
+
+type APIKeyReq struct {
+	ID uint `json:"id" binding:"required"`
+}
+
+// APIKey 调用凭证，仅在新建时返回明文
+type APIKey struct {
+	ID        uint        `json:"id"`
+	App       string      `json:"app"`
+	Name      string      `json:"name"`
+	Role      consts.Role `json:"role"`
+	Key       string      `json:"key,omitempty"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+func NewAPIKey(key *po.APIKey) *APIKey {
+	return &APIKey{
+		ID:        key.ID,
+		App:       key.App,
+		Name:      key.Name,
+		Role:      consts.Role(key.Role),
+		CreatedAt: key.CreatedAt,
+	}
+}
+
+type GetAPIKeysResp struct {
+	CodeMsg
+	Data []*APIKey `json:"data"`
+}
+
+func NewGetAPIKeysResp(keys []*APIKey, codeMsg CodeMsg) *GetAPIKeysResp {
+	return &GetAPIKeysResp{
+		CodeMsg: codeMsg,
+		Data:    keys,
+	}
+}
+
+type CreateAPIKeyResp struct {
+	CodeMsg
+	Data *APIKey `json:"data"`
+}
+
+func NewCreateAPIKeyResp(key *APIKey, codeMsg CodeMsg) *CreateAPIKeyResp {
+	return &CreateAPIKeyResp{
+		CodeMsg: codeMsg,
+		Data:    key,
+	}
+}
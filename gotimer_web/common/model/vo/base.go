@@ -8,21 +8,22 @@ type Errorer interface {
 	Error() error
 }
 
+// CodeMsg 所有接口响应的公共部分，code 为 0 表示成功
 type CodeMsg struct {
-	code int32  `yaml:"Code"`
-	Msg  string `yaml:"Msg"`
+	Code int32  `json:"code" yaml:"Code"`
+	Msg  string `json:"Msg" yaml:"Msg"`
 	Err  error  `yaml:"Err"`
 }
 
 func (c *CodeMsg) Error() error {
-	if c.code == successCode {
+	if c.Code == successCode {
 		return nil
 	}
-	return fmt.Errorf("code: %d,msg: %s", c.code, c.Msg)
+	return fmt.Errorf("code: %d,msg: %s", c.Code, c.Msg)
 }
 func NewCodeMsg(code int32, msg string) CodeMsg {
 	cm := CodeMsg{
-		code: code,
+		Code: code,
 		Msg:  msg,
 	}
 	cm.Err = cm.Error()
@@ -0,0 +1,126 @@
+package vo
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"gotimer_web/common/consts"
+	"gotimer_web/common/model/po"
+)
+
+// BulkTimersReq 批量操作的筛选条件，各条件之间为且的关系，至少指定一个
+type BulkTimersReq struct {
+	App          string `json:"app"`          // 应用名，非管理员调用时以凭证所属应用为准
+	Selector     string `json:"selector"`     // 标签选择器，如 team=billing,env!=dev
+	CallbackHost string `json:"callbackHost"` // 回调地址的 host，带端口时按 host:port 匹配
+	IDs          []uint `json:"ids"`          // 定时器 id 列表
+	DryRun       bool   `json:"dryRun"`       // 只列出将被变更的定时器，不实际执行
+}
+
+func (r *BulkTimersReq) Check() error {
+	if r.App == "" && r.Selector == "" && r.CallbackHost == "" && len(r.IDs) == 0 {
+		return errors.New("empty filter of bulk operation")
+	}
+	return nil
+}
+
+// BulkSkipReason 定时器已处于目标状态或无法变更时返回跳过原因
+func BulkSkipReason(action consts.BulkAction, timer *po.Timer) string {
+	switch action {
+	case consts.BulkEnable:
+		if timer.Status == consts.Enabled.ToInt() {
+			return "already enabled"
+		}
+		if timer.Status != consts.Unabled.ToInt() {
+			return "not unabled status"
+		}
+		if timer.IsOnce() && !timer.RunAt.After(time.Now()) {
+			return "runAt has passed"
+		}
+	case consts.BulkDisable:
+		if timer.Status != consts.Enabled.ToInt() {
+			return "not enabled"
+		}
+	}
+	return ""
+}
+
+// MatchCallbackHost 精确匹配回调地址的 host，host 带端口时连同端口一起比较
+func MatchCallbackHost(timer *po.Timer, host string) bool {
+	if host == "" {
+		return true
+	}
+
+	var param NotifyHTTPParam
+	if err := json.Unmarshal([]byte(timer.NotifyHTTPParam), &param); err != nil {
+		return false
+	}
+	u, err := url.Parse(param.URL)
+	if err != nil {
+		return false
+	}
+	if strings.Contains(host, ":") {
+		return strings.EqualFold(u.Host, host)
+	}
+	return strings.EqualFold(u.Hostname(), host)
+}
+
+// BulkTimerResult 批量操作中单个定时器的处理结果
+type BulkTimerResult struct {
+	ID     uint              `json:"id"`
+	App    string            `json:"app"`
+	Name   string            `json:"name"`
+	Result consts.BulkResult `json:"result"`           // succeeded/skipped/failed，dry-run 时为 planned 或 skipped
+	Reason string            `json:"reason,omitempty"` // 跳过或失败的原因
+}
+
+// BulkTimersReport 批量操作的结果报告
+type BulkTimersReport struct {
+	Action    consts.BulkAction  `json:"action"`
+	DryRun    bool               `json:"dryRun"`
+	Matched   int                `json:"matched"` // 命中筛选条件的定时器个数
+	Planned   int                `json:"planned"`
+	Succeeded int                `json:"succeeded"`
+	Skipped   int                `json:"skipped"`
+	Failed    int                `json:"failed"`
+	Results   []*BulkTimerResult `json:"results"`
+}
+
+func NewBulkTimersReport(action consts.BulkAction, dryRun bool) *BulkTimersReport {
+	return &BulkTimersReport{
+		Action:  action,
+		DryRun:  dryRun,
+		Results: []*BulkTimerResult{},
+	}
+}
+
+// Add 记录单个定时器的处理结果并累计计数
+func (r *BulkTimersReport) Add(result *BulkTimerResult) {
+	r.Matched++
+	switch result.Result {
+	case consts.BulkPlanned:
+		r.Planned++
+	case consts.BulkSucceeded:
+		r.Succeeded++
+	case consts.BulkSkipped:
+		r.Skipped++
+	case consts.BulkFailed:
+		r.Failed++
+	}
+	r.Results = append(r.Results, result)
+}
+
+type BulkTimersResp struct {
+	CodeMsg
+	Data *BulkTimersReport `json:"data"`
+}
+
+func NewBulkTimersResp(report *BulkTimersReport, codeMsg CodeMsg) *BulkTimersResp {
+	return &BulkTimersResp{
+		CodeMsg: codeMsg,
+		Data:    report,
+	}
+}
@@ -0,0 +1,319 @@
+package vo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+	"gotimer_web/common/consts"
+	"gotimer_web/common/model/po"
+)
+
+// 单次同步最多声明的定时器个数
+const maxManifestTimers = 10000
+
+// TimerManifest 应用下定时器的声明式定义，定时器以 (app, name) 标识，字段与 http 接口的 json 字段一致
+type TimerManifest struct {
+	App    string   `json:"app"`
+	Timers []*Timer `json:"timers"`
+}
+
+// NewTimerSpec 定时器定义中可声明的部分，id、应用和版本号由服务端维护，不属于声明内容
+func NewTimerSpec(timer *po.Timer, labels map[string]string) (*Timer, error) {
+	vTimer, err := NewTimer(timer)
+	if err != nil {
+		return nil, err
+	}
+	vTimer.ID, vTimer.App, vTimer.Version = 0, "", 0
+	if len(labels) > 0 {
+		vTimer.Labels = labels
+	}
+	return vTimer, nil
+}
+
+// NewTimerSpecDiff 比对两份声明有差异的字段，字段名与 json 字段一致
+func NewTimerSpecDiff(before, after *Timer) (map[string]*FieldDiff, error) {
+	beforeFields, err := specFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := specFields(after)
+	if err != nil {
+		return nil, err
+	}
+	return diffFields(beforeFields, afterFields), nil
+}
+
+func specFields(timer *Timer) (map[string]interface{}, error) {
+	body, err := json.Marshal(timer)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	return fields, json.Unmarshal(body, &fields)
+}
+
+// Marshal 按 yaml 或 json 格式输出，yaml 中字段的顺序与 json 保持一致，便于在 git 中比对
+func (m *TimerManifest) Marshal(format string) ([]byte, error) {
+	body, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case consts.ManifestJSON:
+		return append(body, '\n'), nil
+	case consts.ManifestYAML:
+		dec := json.NewDecoder(bytes.NewReader(body))
+		ordered, err := decodeOrdered(dec)
+		if err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(ordered)
+	}
+	return nil, fmt.Errorf("invalid manifest format: %s", format)
+}
+
+// UnmarshalTimerManifest 解析 yaml 或 json 格式的声明式定义
+func UnmarshalTimerManifest(body []byte) (*TimerManifest, error) {
+	var manifest TimerManifest
+	if err := UnmarshalYAML(body, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// UnmarshalYAML yaml 中的字段名与 json 字段一致，先转为 json 再解析。json 是 yaml 的子集，同样可以解析
+func UnmarshalYAML(body []byte, v interface{}) error {
+	var raw interface{}
+	if err := yaml.Unmarshal(body, &raw); err != nil {
+		return err
+	}
+	data, err := json.Marshal(normalizeYAML(raw))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// normalizeYAML yaml.v2 解析出的 map 的 key 为 interface{}，转为 json 可序列化的 map[string]interface{}
+func normalizeYAML(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			m[fmt.Sprint(k)] = normalizeYAML(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range val {
+			val[i] = normalizeYAML(item)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+// decodeOrdered 按 json 中的字段顺序解析为 yaml.MapSlice
+func decodeOrdered(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+
+	switch delim {
+	case '{':
+		m := yaml.MapSlice{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			m = append(m, yaml.MapItem{Key: key, Value: value})
+		}
+		_, err = dec.Token()
+		return m, err
+	case '[':
+		items := make([]interface{}, 0)
+		for dec.More() {
+			item, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		_, err = dec.Token()
+		return items, err
+	}
+	return nil, fmt.Errorf("unexpected json delim: %v", delim)
+}
+
+type ExportTimersReq struct {
+	App      string `form:"app" binding:"required"`
+	Selector string `form:"selector"` // 标签选择器，只导出匹配的定时器
+	Format   string `form:"format"`   // yaml 或 json 时以文件形式返回，为空时返回 data 字段
+}
+
+type ExportTimersResp struct {
+	CodeMsg
+	Data *TimerManifest `json:"data"`
+}
+
+func NewExportTimersResp(manifest *TimerManifest, codeMsg CodeMsg) *ExportTimersResp {
+	return &ExportTimersResp{
+		CodeMsg: codeMsg,
+		Data:    manifest,
+	}
+}
+
+type SyncTimersReq struct {
+	TimerManifest
+	Prune    bool   `json:"prune"`    // 删除声明中不存在的定时器
+	Selector string `json:"selector"` // 标签选择器，prune 时只删除匹配的定时器，避免误删不受声明管理的定时器
+	DryRun   bool   `json:"dryRun"`   // 只输出变更计划，不实际执行
+}
+
+// Check 声明中的定时器需属于同一应用且名称不重复，status 为空时表示不管理激活状态
+func (r *SyncTimersReq) Check() error {
+	if r.App == "" {
+		return errors.New("empty app of manifest")
+	}
+	if len(r.Timers) > maxManifestTimers {
+		return fmt.Errorf("%d timers declared, more than the max: %d of a manifest", len(r.Timers), maxManifestTimers)
+	}
+
+	names := make(map[string]bool, len(r.Timers))
+	for i, timer := range r.Timers {
+		if timer == nil || timer.Name == "" {
+			return fmt.Errorf("empty name of timer: %d in manifest", i)
+		}
+		if timer.App != "" && timer.App != r.App {
+			return fmt.Errorf("timer: %s belongs to app: %s, not the manifest app: %s", timer.Name, timer.App, r.App)
+		}
+		if names[timer.Name] {
+			return fmt.Errorf("duplicated timer name: %s in manifest", timer.Name)
+		}
+		names[timer.Name] = true
+	}
+	return nil
+}
+
+// SyncTimerPlan 单个声明与库中定时器的比对结果
+type SyncTimerPlan struct {
+	Diff map[string]*FieldDiff
+	// 需要创建或更新定义，只有激活状态不同时不更新，不产生新版本
+	Update bool
+	// 需要调整到的激活状态，为 0 时不调整
+	Status consts.TimerStatus
+}
+
+// NewSyncTimerPlan 比对库中的定时器 cur 与补全默认值后的声明 desired，cur 为 nil 表示库中不存在.
+// desired.Status 为声明的激活状态，为空时不管理激活状态，已结束的定时器不再调整激活状态
+func NewSyncTimerPlan(cur, desired *Timer) (*SyncTimerPlan, error) {
+	wantStatus := desired.Status
+	if wantStatus != consts.Unabled && wantStatus != consts.Enabled {
+		wantStatus = 0
+	}
+	if cur == nil {
+		plan := SyncTimerPlan{Update: true}
+		if wantStatus == consts.Enabled {
+			plan.Status = consts.Enabled
+		}
+		return &plan, nil
+	}
+
+	curSpec, spec := *cur, *desired
+	curSpec.ID, curSpec.App, curSpec.Version = 0, "", 0
+	if wantStatus == 0 || cur.Status == consts.Finished {
+		wantStatus, spec.Status = 0, cur.Status
+	}
+	diff, err := NewTimerSpecDiff(&curSpec, &spec)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := SyncTimerPlan{Diff: diff}
+	if _, statusChanged := diff["status"]; len(diff) > 1 || (len(diff) == 1 && !statusChanged) {
+		plan.Update = true
+	}
+	switch {
+	case wantStatus == consts.Enabled && cur.Status != consts.Enabled:
+		plan.Status = consts.Enabled
+	case wantStatus == consts.Unabled && cur.Status == consts.Enabled:
+		plan.Status = consts.Unabled
+	}
+	return &plan, nil
+}
+
+// SyncTimerResult 同步中单个定时器的变更
+type SyncTimerResult struct {
+	Name   string                `json:"name"`
+	ID     uint                  `json:"id,omitempty"`
+	Action consts.SyncAction     `json:"action"`
+	Diff   map[string]*FieldDiff `json:"diff,omitempty"`   // update 时有差异的字段
+	Result consts.BulkResult     `json:"result"`           // succeeded/failed，dry-run 时为 planned，unchanged 时为 skipped
+	Reason string                `json:"reason,omitempty"` // 失败的原因
+}
+
+// SyncTimersReport 同步的变更计划及执行结果
+type SyncTimersReport struct {
+	App       string             `json:"app"`
+	DryRun    bool               `json:"dryRun"`
+	Prune     bool               `json:"prune"`
+	Created   int                `json:"created"`
+	Updated   int                `json:"updated"`
+	Deleted   int                `json:"deleted"`
+	Unchanged int                `json:"unchanged"`
+	Failed    int                `json:"failed"`
+	Results   []*SyncTimerResult `json:"results"`
+}
+
+func NewSyncTimersReport(req *SyncTimersReq) *SyncTimersReport {
+	return &SyncTimersReport{
+		App:     req.App,
+		DryRun:  req.DryRun,
+		Prune:   req.Prune,
+		Results: []*SyncTimerResult{},
+	}
+}
+
+// Add 记录单个定时器的变更并累计计数，dry-run 时按计划的变更计数
+func (r *SyncTimersReport) Add(result *SyncTimerResult) {
+	r.Results = append(r.Results, result)
+	if result.Result == consts.BulkFailed {
+		r.Failed++
+		return
+	}
+	switch result.Action {
+	case consts.SyncCreate:
+		r.Created++
+	case consts.SyncUpdate:
+		r.Updated++
+	case consts.SyncDelete:
+		r.Deleted++
+	case consts.SyncUnchanged:
+		r.Unchanged++
+	}
+}
+
+type SyncTimersResp struct {
+	CodeMsg
+	Data *SyncTimersReport `json:"data"`
+}
+
+func NewSyncTimersResp(report *SyncTimersReport, codeMsg CodeMsg) *SyncTimersResp {
+	return &SyncTimersResp{
+		CodeMsg: codeMsg,
+		Data:    report,
+	}
+}
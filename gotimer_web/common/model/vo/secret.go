@@ -0,0 +1,65 @@
+package vo
+
+import (
+	"time"
+
+	"gotimer_web/common/model/po"
+)
+
+type SecretReq struct {
+	App string `form:"app" json:"app" binding:"required"`
+	ID  uint   `form:"id" json:"id" binding:"required"`
+}
+
+type GetSecretsReq struct {
+	App string `form:"app" binding:"required"`
+}
+
+type RotateSecretReq struct {
+	App string `json:"app" binding:"required"`
+}
+
+// AppSecret 回调签名密钥，仅在新建时返回明文
+type AppSecret struct {
+	ID        uint      `json:"id"`
+	App       string    `json:"app"`
+	Secret    string    `json:"secret"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func NewAppSecret(secret *po.AppSecret, masked bool) *AppSecret {
+	s := secret.Secret
+	if masked && len(s) > 4 {
+		s = "****" + s[len(s)-4:]
+	}
+	return &AppSecret{
+		ID:        secret.ID,
+		App:       secret.App,
+		Secret:    s,
+		CreatedAt: secret.CreatedAt,
+	}
+}
+
+type GetSecretsResp struct {
+	CodeMsg
+	Data []*AppSecret `json:"data"`
+}
+
+func NewGetSecretsResp(secrets []*AppSecret, codeMsg CodeMsg) *GetSecretsResp {
+	return &GetSecretsResp{
+		CodeMsg: codeMsg,
+		Data:    secrets,
+	}
+}
+
+type RotateSecretResp struct {
+	CodeMsg
+	Data *AppSecret `json:"data"`
+}
+
+func NewRotateSecretResp(secret *AppSecret, codeMsg CodeMsg) *RotateSecretResp {
+	return &RotateSecretResp{
+		CodeMsg: codeMsg,
+		Data:    secret,
+	}
+}
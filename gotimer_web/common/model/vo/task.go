@@ -6,18 +6,27 @@ import (
 )
 
 type Task struct {
-	ID       uint      `json:"id"`       // 任务 ID
-	App      string    `json:"app"`      // 定义ID
-	TimerID  uint      `json:"timerID"`  // 定义ID
-	Output   string    `json:"output"`   // 执行结果
-	RunTimer time.Time `json:"runTimer"` // 执行时间
-	CostTime int       `json:"costTime"` // 执行耗时
-	Status   int       `json:"status"`   // 当前状态
+	ID           uint      `json:"id"`                   // 任务 ID
+	App          string    `json:"app"`                  // 定义ID
+	TimerID      uint      `json:"timerID"`              // 定义ID
+	Output       string    `json:"output"`               // 执行结果
+	RunTimer     time.Time `json:"runTimer"`             // 执行时间
+	CostTime     int       `json:"costTime"`             // 执行耗时，单位：ms
+	Status       int       `json:"status"`               // 当前状态
+	Attempt      int       `json:"attempt"`              // 已执行次数，包含重试
+	FailReason   string    `json:"failReason,omitempty"` // 最近一次执行的错误信息，成功时为空
+	HTTPStatus   int       `json:"httpStatus"`           // 最近一次回调的 http 状态码，请求未得到响应时为 0
+	Truncated    bool      `json:"truncated"`            // 执行结果是否被截断
+	Source       int       `json:"source"`               // 触发来源，1:调度触发, 2:手动触发
+	TimerVersion int       `json:"timerVersion"`         // 生成该 task 的定时器版本，0 表示版本记录上线前生成
+	FullOutput   string    `json:"fullOutput,omitempty"` // 被截断时的完整响应体，仅在请求时返回
 }
 
 type GetTasksReq struct {
 	PageLimiter
-	TimerID uint `form:"timerID" binding:"required"`
+	App            string `form:"app"` // 定时器所属应用名，非管理员调用时以凭证所属应用为准
+	TimerID        uint   `form:"timerID" binding:"required"`
+	WithFullOutput bool   `form:"withFullOutput"` // 是否返回被截断任务的完整响应体
 }
 
 type GetTaskResp struct {
@@ -36,13 +45,19 @@ func NewGetTasksResp(tasks []*Task, total int64, codeMsg CodeMsg) *GetTaskResp {
 
 func NewTask(task *po.Task) *Task {
 	return &Task{
-		ID:       task.ID,
-		App:      task.App,
-		TimerID:  task.TimerID,
-		Output:   task.Output,
-		RunTimer: task.RunTimer,
-		CostTime: task.CostTime,
-		Status:   task.Status,
+		ID:           task.ID,
+		App:          task.App,
+		TimerID:      task.TimerID,
+		Output:       task.Output,
+		RunTimer:     task.RunTimer,
+		CostTime:     task.CostTime,
+		Status:       task.Status,
+		Attempt:      task.Attempt,
+		FailReason:   task.FailReason,
+		HTTPStatus:   task.HTTPStatus,
+		Truncated:    task.Truncated,
+		Source:       task.Source,
+		TimerVersion: task.TimerVersion,
 	}
 }
 
@@ -55,11 +70,17 @@ func NewTasks(tasks []*po.Task) []*Task {
 }
 func (t *Task) ToPo() *po.Task {
 	return &po.Task{
-		TimerID:  t.ID,
-		App:      t.App,
-		Output:   t.Output,
-		RunTimer: t.RunTimer,
-		CostTime: t.CostTime,
-		Status:   t.Status,
+		TimerID:      t.ID,
+		App:          t.App,
+		Output:       t.Output,
+		RunTimer:     t.RunTimer,
+		CostTime:     t.CostTime,
+		Status:       t.Status,
+		Attempt:      t.Attempt,
+		FailReason:   t.FailReason,
+		HTTPStatus:   t.HTTPStatus,
+		Truncated:    t.Truncated,
+		Source:       t.Source,
+		TimerVersion: t.TimerVersion,
 	}
 }
@@ -3,33 +3,176 @@ package vo
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
 	"gotimer_web/common/consts"
 	"gotimer_web/common/model/po"
+	"gotimer_web/pkg/jsonpath"
+	"gotimer_web/pkg/selector"
+)
+
+const (
+	// 后移后的执行时机需早于下一次迁移，保证执行前已写入 redis
+	maxJitterSeconds = 30 * 60
+	// 单个定时器的标签数上限
+	maxLabels = 32
 )
 
 type Timer struct {
-	ID              uint               `json:"id,omitempty"`
-	App             string             `json:"app,omitempty" binding:"required"`             // 定时器定义名称
-	Name            string             `json:"name,omitempty" binding:"required"`            // 定时器定义名称
-	Status          consts.TimerStatus `json:"status"`                                       // 定时器定义状态，1:未激活, 2:已激活
-	Cron            string             `json:"cron,omitempty" binding:"required"`            // 定时器定时配置
-	NotifyHTTPParam *NotifyHTTPParam   `json:"notifyHTTPParam,omitempty" binding:"required"` // http 回调参数
+	ID                uint                     `json:"id,omitempty"`
+	App               string                   `json:"app,omitempty" binding:"required"`             // 定时器定义名称
+	Name              string                   `json:"name,omitempty" binding:"required"`            // 定时器定义名称
+	Status            consts.TimerStatus       `json:"status"`                                       // 定时器定义状态，1:未激活, 2:已激活, 3:已结束
+	Type              consts.TimerType         `json:"type,omitempty"`                               // 定时器类型，1:cron 周期执行(默认), 2:runAt 单次执行
+	Cron              string                   `json:"cron,omitempty"`                               // 定时器定时配置，cron 类型必填
+	RunAt             *time.Time               `json:"runAt,omitempty"`                              // 单次执行的时刻，runAt 类型必填
+	Timezone          string                   `json:"timezone,omitempty"`                           // 解析 cron 使用的 IANA 时区，如 Asia/Shanghai，默认服务器本地时区
+	NotifyHTTPParam   *NotifyHTTPParam         `json:"notifyHTTPParam,omitempty" binding:"required"` // http 回调参数
+	RetryPolicy       *RetryPolicy             `json:"retryPolicy,omitempty"`                        // 回调失败的重试策略，为空时不重试
+	SuccessCriteria   *SuccessCriteria         `json:"successCriteria,omitempty"`                    // 回调成功的判定条件，为空时 2xx 即成功
+	MisfirePolicy     consts.MisfirePolicy     `json:"misfirePolicy,omitempty"`                      // 错过执行时机后的补偿策略，1:补偿一次(默认), 2:全部补偿, 3:跳过
+	ConcurrencyPolicy consts.ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`                  // 上一次执行未结束时的并发策略，1:允许(默认), 2:跳过, 3:替代
+	IncludeCalendars  []string                 `json:"includeCalendars,omitempty"`                   // 只在这些日历内执行，为空时不限制
+	ExcludeCalendars  []string                 `json:"excludeCalendars,omitempty"`                   // 不在这些日历内执行，如节假日、停机窗口
+	JitterSeconds     int                      `json:"jitterSeconds,omitempty"`                      // 执行时机的最大后移秒数，用于错开同一时刻扎堆的定时器，0 表示不后移
+	Version           int                      `json:"version,omitempty"`                            // 定义的版本号，只读
+	Labels            map[string]string        `json:"labels,omitempty"`                             // 标签，如 team: billing，更新时为 null 表示不修改
 }
 type NotifyHTTPParam struct {
 	Method string            `json:"method,omitempty" binding:"required"` // POST,GET 方法
 	URL    string            `json:"url,omitempty" binding:"required"`    // URL 路径
 	Header map[string]string `json:"header,omitempty"`                    // header 请求头
 	Body   string            `json:"body,omitempty"`                      // 请求参数体
+	// 回调超时时间，单位：s，为 0 时使用执行器默认的超时时间
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+}
+
+const (
+	// 重试策略允许的最大执行次数，包含首次执行
+	maxRetryAttempts = 20
+	// 单次重试前的最大等待时间，按倍数放大后超出时以此为准，单位：s
+	maxRetryBackoffSeconds = 3600
+	// 重试等待时间允许的最大放大倍数
+	maxRetryMultiplier = 10
+)
+
+type RetryPolicy struct {
+	MaxAttempts           int     `json:"maxAttempts"`                    // 最大执行次数，包含首次执行，不超过 20
+	InitialBackoffSeconds int     `json:"initialBackoffSeconds"`          // 首次重试前的等待时间，单位：s，不超过 3600
+	Multiplier            float64 `json:"multiplier,omitempty"`           // 每次重试等待时间的放大倍数，取值 [1, 10]，默认为 1. 放大后的等待时间不超过 3600s
+	RetryableStatusCodes  []int   `json:"retryableStatusCodes,omitempty"` // 可重试的 http 状态码，为空时任意失败都重试；请求未得到响应时总是重试
+}
+
+// Retryable 第 attempt 次执行失败后是否还能重试，statusCode 为 0 表示请求未得到响应
+func (r *RetryPolicy) Retryable(attempt, statusCode int) bool {
+	if r == nil || attempt >= r.MaxAttempts {
+		return false
+	}
+	if statusCode == 0 || len(r.RetryableStatusCodes) == 0 {
+		return true
+	}
+	for _, code := range r.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// Backoff 第 attempt 次执行失败后，距离下一次重试的等待时间，不超过 maxRetryBackoffSeconds
+func (r *RetryPolicy) Backoff(attempt int) time.Duration {
+	multiplier := r.Multiplier
+	if multiplier == 0 {
+		multiplier = 1
+	}
+	seconds := float64(r.InitialBackoffSeconds) * math.Pow(multiplier, float64(attempt-1))
+	if seconds > maxRetryBackoffSeconds {
+		seconds = maxRetryBackoffSeconds
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+type SuccessCriteria struct {
+	StatusRanges []StatusRange `json:"statusRanges,omitempty"` // 视为成功的 http 状态码区间，为空时默认 [200, 299]
+	Assertion    string        `json:"assertion,omitempty"`    // 针对 json 响应体的断言，如 $.code == 0
+}
+
+type StatusRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+var defaultStatusRanges = []StatusRange{{Min: 200, Max: 299}}
+
+// Evaluate 判定回调是否成功，失败时返回原因. criteria 为空时按默认条件判定
+func (c *SuccessCriteria) Evaluate(statusCode int, body []byte) error {
+	ranges := defaultStatusRanges
+	if c != nil && len(c.StatusRanges) > 0 {
+		ranges = c.StatusRanges
+	}
+
+	accepted := false
+	for _, r := range ranges {
+		if statusCode >= r.Min && statusCode <= r.Max {
+			accepted = true
+			break
+		}
+	}
+	if !accepted {
+		return fmt.Errorf("unexpected http status: %d", statusCode)
+	}
+
+	if c == nil || c.Assertion == "" {
+		return nil
+	}
+	assertion, err := jsonpath.Parse(c.Assertion)
+	if err != nil {
+		return err
+	}
+	return assertion.Evaluate(body)
+}
+
+func (c *SuccessCriteria) Check() error {
+	for _, r := range c.StatusRanges {
+		if r.Min < 100 || r.Max > 599 || r.Min > r.Max {
+			return fmt.Errorf("invalid status range of success criteria: [%d, %d]", r.Min, r.Max)
+		}
+	}
+	if c.Assertion == "" {
+		return nil
+	}
+	_, err := jsonpath.Parse(c.Assertion)
+	return err
+}
+
+func (r *RetryPolicy) Check() error {
+	if r.MaxAttempts < 1 || r.MaxAttempts > maxRetryAttempts {
+		return fmt.Errorf("invalid maxAttempts of retry policy: %d, should be in [1, %d]", r.MaxAttempts, maxRetryAttempts)
+	}
+	if r.InitialBackoffSeconds < 0 || r.InitialBackoffSeconds > maxRetryBackoffSeconds {
+		return fmt.Errorf("invalid initialBackoffSeconds of retry policy: %d, should be in [0, %d]", r.InitialBackoffSeconds, maxRetryBackoffSeconds)
+	}
+	// 0 表示未指定，按 1 处理
+	if r.Multiplier != 0 && (r.Multiplier < 1 || r.Multiplier > maxRetryMultiplier) {
+		return fmt.Errorf("invalid multiplier of retry policy: %v, should be in [1, %d]", r.Multiplier, maxRetryMultiplier)
+	}
+	return nil
 }
 
 type GetAppTimersReq struct {
-	App string `form:"app" binding:"required"`
+	App      string `form:"app" binding:"required"`
+	Selector string `form:"selector"` // 标签选择器，如 team=billing,env!=dev
 	PageLimiter
 }
 
 type GetTimersByNameReq struct {
 	App       string `form:"app" binding:"required"`
 	FuzzyName string `form:"fuzzyName" binding:"required"`
+	Selector  string `form:"selector"` // 标签选择器，如 team=billing,env!=dev
 	PageLimiter
 }
 
@@ -59,6 +202,18 @@ func NewCreateTimersResp(id uint, codeMsg CodeMsg) *CreateTimerResp {
 	}
 }
 
+type RunTimerResp struct {
+	CodeMsg
+	TaskID uint `json:"taskID"`
+}
+
+func NewRunTimerResp(taskID uint, codeMsg CodeMsg) *RunTimerResp {
+	return &RunTimerResp{
+		TaskID:  taskID,
+		CodeMsg: codeMsg,
+	}
+}
+
 type TimerReq struct {
 	App string `form:"app" json:"app" binding:"required"`
 	ID  uint   `form:"id" json:"id" binding:"required"`
@@ -82,16 +237,50 @@ func NewTimer(timer *po.Timer) (*Timer, error) {
 		return nil, err
 	}
 
+	var retryPolicy *RetryPolicy
+	if timer.RetryPolicy != "" {
+		retryPolicy = &RetryPolicy{}
+		if err := json.Unmarshal([]byte(timer.RetryPolicy), retryPolicy); err != nil {
+			return nil, err
+		}
+	}
+
+	var successCriteria *SuccessCriteria
+	if timer.SuccessCriteria != "" {
+		successCriteria = &SuccessCriteria{}
+		if err := json.Unmarshal([]byte(timer.SuccessCriteria), successCriteria); err != nil {
+			return nil, err
+		}
+	}
+
 	return &Timer{
-		ID:              timer.ID,
-		App:             timer.App,
-		Name:            timer.Name,
-		Status:          consts.TimerStatus(timer.Status),
-		Cron:            timer.Cron,
-		NotifyHTTPParam: &param,
+		ID:                timer.ID,
+		App:               timer.App,
+		Name:              timer.Name,
+		Status:            consts.TimerStatus(timer.Status),
+		Type:              consts.TimerType(timer.Type),
+		Cron:              timer.Cron,
+		RunAt:             timer.RunAt,
+		Timezone:          timer.Timezone,
+		NotifyHTTPParam:   &param,
+		RetryPolicy:       retryPolicy,
+		SuccessCriteria:   successCriteria,
+		MisfirePolicy:     consts.MisfirePolicy(timer.MisfirePolicy),
+		ConcurrencyPolicy: consts.ConcurrencyPolicy(timer.ConcurrencyPolicy),
+		IncludeCalendars:  splitCalendars(timer.IncludeCalendars),
+		ExcludeCalendars:  splitCalendars(timer.ExcludeCalendars),
+		JitterSeconds:     timer.JitterSeconds,
+		Version:           timer.Version,
 	}, nil
 }
 
+func splitCalendars(names string) []string {
+	if names == "" {
+		return nil
+	}
+	return strings.Split(names, ",")
+}
+
 func NewTimers(timers []*po.Timer) ([]*Timer, error) {
 	vTimers := make([]*Timer, 0, len(timers))
 	for _, timer := range timers {
@@ -108,10 +297,73 @@ func (t *Timer) Check() error {
 	if t.NotifyHTTPParam == nil {
 		return errors.New("empty notify http params")
 	}
+	if t.NotifyHTTPParam.TimeoutSeconds < 0 {
+		return fmt.Errorf("invalid timeoutSeconds of notify http params: %d", t.NotifyHTTPParam.TimeoutSeconds)
+	}
+
+	switch t.Type {
+	case consts.CronTimer:
+		if t.Cron == "" {
+			return errors.New("empty cron of cron timer")
+		}
+	case consts.OnceTimer:
+		if t.RunAt == nil {
+			return errors.New("empty runAt of once timer")
+		}
+	default:
+		return fmt.Errorf("invalid timer type: %d", t.Type)
+	}
+
+	switch t.MisfirePolicy {
+	case consts.MisfireFireOnce, consts.MisfireFireAll, consts.MisfireSkip:
+	default:
+		return fmt.Errorf("invalid misfire policy: %d", t.MisfirePolicy)
+	}
+
+	switch t.ConcurrencyPolicy {
+	case consts.ConcurrencyAllow, consts.ConcurrencyForbid, consts.ConcurrencyReplace:
+	default:
+		return fmt.Errorf("invalid concurrency policy: %d", t.ConcurrencyPolicy)
+	}
+
+	if t.JitterSeconds < 0 || t.JitterSeconds > maxJitterSeconds {
+		return fmt.Errorf("invalid jitterSeconds: %d, should be in [0, %d]", t.JitterSeconds, maxJitterSeconds)
+	}
+
+	for _, name := range append(append([]string{}, t.IncludeCalendars...), t.ExcludeCalendars...) {
+		if name == "" || strings.Contains(name, ",") {
+			return fmt.Errorf("invalid calendar name: %q", name)
+		}
+	}
+
+	if len(t.Labels) > maxLabels {
+		return fmt.Errorf("too many labels: %d, max: %d", len(t.Labels), maxLabels)
+	}
+	if err := selector.ValidateLabels(t.Labels); err != nil {
+		return err
+	}
+
+	if t.RetryPolicy != nil {
+		if err := t.RetryPolicy.Check(); err != nil {
+			return err
+		}
+	}
+	if t.SuccessCriteria != nil {
+		return t.SuccessCriteria.Check()
+	}
 	return nil
 }
 
 func (t *Timer) ToPO() (*po.Timer, error) {
+	if t.Type == 0 {
+		t.Type = consts.CronTimer
+	}
+	if t.MisfirePolicy == 0 {
+		t.MisfirePolicy = consts.MisfireFireOnce
+	}
+	if t.ConcurrencyPolicy == 0 {
+		t.ConcurrencyPolicy = consts.ConcurrencyAllow
+	}
 	if err := t.Check(); err != nil {
 		return nil, err
 	}
@@ -122,11 +374,34 @@ func (t *Timer) ToPO() (*po.Timer, error) {
 	}
 
 	timer := po.Timer{
-		App:             t.App,
-		Name:            t.Name,
-		Status:          t.Status.ToInt(),
-		Cron:            t.Cron,
-		NotifyHTTPParam: string(param),
+		Model:             gorm.Model{ID: t.ID},
+		App:               t.App,
+		Name:              t.Name,
+		Status:            t.Status.ToInt(),
+		Type:              t.Type.ToInt(),
+		Cron:              t.Cron,
+		RunAt:             t.RunAt,
+		Timezone:          t.Timezone,
+		NotifyHTTPParam:   string(param),
+		MisfirePolicy:     t.MisfirePolicy.ToInt(),
+		ConcurrencyPolicy: t.ConcurrencyPolicy.ToInt(),
+		IncludeCalendars:  strings.Join(t.IncludeCalendars, ","),
+		ExcludeCalendars:  strings.Join(t.ExcludeCalendars, ","),
+		JitterSeconds:     t.JitterSeconds,
+	}
+	if t.RetryPolicy != nil {
+		retryPolicy, err := json.Marshal(t.RetryPolicy)
+		if err != nil {
+			return nil, err
+		}
+		timer.RetryPolicy = string(retryPolicy)
+	}
+	if t.SuccessCriteria != nil {
+		successCriteria, err := json.Marshal(t.SuccessCriteria)
+		if err != nil {
+			return nil, err
+		}
+		timer.SuccessCriteria = string(successCriteria)
 	}
 	if timer.Status == 0 {
 		timer.Status = consts.Unabled.ToInt()
@@ -134,7 +409,47 @@ func (t *Timer) ToPO() (*po.Timer, error) {
 	return &timer, nil
 }
 
+type CronPreviewReq struct {
+	Cron    string `form:"cron" binding:"required"`
+	N       int    `form:"n"`       // 返回的触发时机个数，默认 10，最多 100
+	TZ      string `form:"tz"`      // 解析 cron 使用的 IANA 时区，默认服务器本地时区
+	TimerID uint   `form:"timerID"` // cron 含 H 时用于哈希的定时器 id，为空时按 0 计算
+}
+
+// CronPreview cron 表达式的试算结果
+type CronPreview struct {
+	Nexts       []time.Time `json:"nexts"`       // 接下来的触发时机
+	Description string      `json:"description"` // 可读的调度描述
+	Warnings    []string    `json:"warnings"`    // 可能不符合预期的调度
+}
+
+type CronPreviewResp struct {
+	CodeMsg
+	Data *CronPreview `json:"data"`
+}
+
+func NewCronPreviewResp(preview *CronPreview, codeMsg CodeMsg) *CronPreviewResp {
+	return &CronPreviewResp{
+		CodeMsg: codeMsg,
+		Data:    preview,
+	}
+}
+
 type MinuteBucker struct {
 	Minute string
 	Bucket int
 }
+
+// ScheduleChanged 定时器类型、cron 表达式、时区、引用的日历、抖动窗口或单次执行时刻任一发生变化，都需要重新生成执行时机
+func ScheduleChanged(old, cur *po.Timer) bool {
+	if old.Type != cur.Type || old.Cron != cur.Cron || old.Timezone != cur.Timezone {
+		return true
+	}
+	if old.IncludeCalendars != cur.IncludeCalendars || old.ExcludeCalendars != cur.ExcludeCalendars || old.JitterSeconds != cur.JitterSeconds {
+		return true
+	}
+	if old.RunAt == nil || cur.RunAt == nil {
+		return old.RunAt != cur.RunAt
+	}
+	return !old.RunAt.Equal(*cur.RunAt)
+}
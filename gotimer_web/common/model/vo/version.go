@@ -0,0 +1,109 @@
+package vo
+
+import (
+	"encoding/json"
+	"time"
+
+	"gotimer_web/common/model/po"
+)
+
+// NewTimerSnapshot 定时器定义中调度、回调及策略相关的配置，作为版本内容.
+// id、应用、名称、状态及版本号不属于版本内容
+func NewTimerSnapshot(timer *po.Timer) (string, error) {
+	vTimer, err := NewTimer(timer)
+	if err != nil {
+		return "", err
+	}
+	vTimer.ID, vTimer.App, vTimer.Name, vTimer.Status, vTimer.Version = 0, "", "", 0, 0
+
+	body, err := json.Marshal(vTimer)
+	return string(body), err
+}
+
+// TimerVersion 定时器定义的历史版本
+type TimerVersion struct {
+	Version   int             `json:"version"`
+	Actor     string          `json:"actor"`    // 操作人
+	Snapshot  json.RawMessage `json:"snapshot"` // 该版本的定时器定义
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+func NewTimerVersions(versions []*po.TimerVersion) []*TimerVersion {
+	vVersions := make([]*TimerVersion, 0, len(versions))
+	for _, version := range versions {
+		vVersions = append(vVersions, &TimerVersion{
+			Version:   version.Version,
+			Actor:     version.Actor,
+			Snapshot:  json.RawMessage(version.Snapshot),
+			CreatedAt: version.CreatedAt,
+		})
+	}
+	return vVersions
+}
+
+// TimerVersionDiff 两个版本间有差异的字段
+type TimerVersionDiff struct {
+	From int                   `json:"from"`
+	To   int                   `json:"to"`
+	Diff map[string]*FieldDiff `json:"diff"`
+}
+
+func NewTimerVersionDiff(from, to *po.TimerVersion) (*TimerVersionDiff, error) {
+	var fromFields, toFields map[string]interface{}
+	if err := json.Unmarshal([]byte(from.Snapshot), &fromFields); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(to.Snapshot), &toFields); err != nil {
+		return nil, err
+	}
+	return &TimerVersionDiff{
+		From: from.Version,
+		To:   to.Version,
+		Diff: diffFields(fromFields, toFields),
+	}, nil
+}
+
+type GetTimerVersionsReq struct {
+	PageLimiter
+	App string `form:"app" binding:"required"`
+	ID  uint   `form:"id" binding:"required"`
+}
+
+type GetTimerVersionsResp struct {
+	CodeMsg
+	Data  []*TimerVersion `json:"data"`
+	Total int64           `json:"total"`
+}
+
+func NewGetTimerVersionsResp(versions []*TimerVersion, total int64, codeMsg CodeMsg) *GetTimerVersionsResp {
+	return &GetTimerVersionsResp{
+		CodeMsg: codeMsg,
+		Data:    versions,
+		Total:   total,
+	}
+}
+
+type DiffTimerVersionsReq struct {
+	App  string `form:"app" binding:"required"`
+	ID   uint   `form:"id" binding:"required"`
+	From int    `form:"from" binding:"required"` // 比对的起始版本
+	To   int    `form:"to" binding:"required"`   // 比对的目标版本
+}
+
+type DiffTimerVersionsResp struct {
+	CodeMsg
+	Data *TimerVersionDiff `json:"data"`
+}
+
+func NewDiffTimerVersionsResp(diff *TimerVersionDiff, codeMsg CodeMsg) *DiffTimerVersionsResp {
+	return &DiffTimerVersionsResp{
+		CodeMsg: codeMsg,
+		Data:    diff,
+	}
+}
+
+type RollbackTimerReq struct {
+	App     string `json:"app" binding:"required"`
+	ID      uint   `json:"id" binding:"required"`
+	Version int    `json:"version" binding:"required"` // 回滚到的版本
+}
@@ -48,6 +48,13 @@ func GetEnableLockKey(app string) string { return fmt.Sprintf("enable_timer_lock
 
 func GetCreateLockKey(app string) string { return fmt.Sprintf("create_timer_lock_%s", app) }
 
+func GetRunLockKey(timerID uint) string { return fmt.Sprintf("run_timer_lock_%d", timerID) }
+
+func GetBulkLockKey(app string) string { return fmt.Sprintf("bulk_timer_lock_%s", app) }
+
+// GetDistributionLockKey 分布式锁在 redis 中实际使用的 key
+func GetDistributionLockKey(key string) string { return "FTIMER_LOCK_PREFIX_" + key }
+
 func GetForwardTwoMigrateStepEnd(cur time.Time, diff time.Duration) time.Time {
 	end := cur.Add(diff)
 	return time.Date(end.Year(), end.Month(), end.Day(), end.Hour(), end.Minute(), 0, 0, time.Local)
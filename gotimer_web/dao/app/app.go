@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gotimer_web/common/model/po"
+	"gotimer_web/pkg/mysql"
+)
+
+type AppDAO struct {
+	client *mysql.Client
+}
+
+func NewAppDAO(client *mysql.Client) *AppDAO {
+	return &AppDAO{
+		client: client,
+	}
+}
+
+func (a *AppDAO) CreateApp(ctx context.Context, app *po.App) error {
+	return a.client.DB.WithContext(ctx).Create(app).Error
+}
+
+func (a *AppDAO) UpdateApp(ctx context.Context, app *po.App) error {
+	return a.client.DB.WithContext(ctx).Select("*").Omit("id", "created_at", "deleted_at").
+		Where("name = ?", app.Name).Updates(app).Error
+}
+
+func (a *AppDAO) DeleteApp(ctx context.Context, name string) error {
+	res := a.client.DB.WithContext(ctx).Where("name = ?", name).Delete(&po.App{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (a *AppDAO) GetApp(ctx context.Context, name string) (*po.App, error) {
+	var app po.App
+	return &app, a.client.DB.WithContext(ctx).Where("name = ?", name).First(&app).Error
+}
+
+func (a *AppDAO) GetApps(ctx context.Context, offset, limit int) ([]*po.App, error) {
+	var apps []*po.App
+	return apps, a.client.DB.WithContext(ctx).Order("id DESC").Offset(offset).Limit(limit).Find(&apps).Error
+}
+
+func (a *AppDAO) Count(ctx context.Context) (int64, error) {
+	var cnt int64
+	return cnt, a.client.DB.WithContext(ctx).Model(&po.App{}).Count(&cnt).Error
+}
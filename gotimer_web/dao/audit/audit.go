@@ -0,0 +1,36 @@
+package audit
+
+import (
+	"context"
+
+	"gotimer_web/common/model/po"
+	"gotimer_web/pkg/mysql"
+)
+
+type AuditDAO struct {
+	client *mysql.Client
+}
+
+func NewAuditDAO(client *mysql.Client) *AuditDAO {
+	return &AuditDAO{
+		client: client,
+	}
+}
+
+func (a *AuditDAO) GetAuditLogs(ctx context.Context, opts ...Option) ([]*po.AuditLog, error) {
+	db := a.client.DB.WithContext(ctx).Model(&po.AuditLog{})
+	for _, opt := range opts {
+		db = opt(db)
+	}
+	var logs []*po.AuditLog
+	return logs, db.Find(&logs).Error
+}
+
+func (a *AuditDAO) Count(ctx context.Context, opts ...Option) (int64, error) {
+	db := a.client.DB.WithContext(ctx).Model(&po.AuditLog{})
+	for _, opt := range opts {
+		db = opt(db)
+	}
+	var cnt int64
+	return cnt, db.Count(&cnt).Error
+}
@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Option func(*gorm.DB) *gorm.DB
+
+func WithApp(app string) Option {
+	return func(d *gorm.DB) *gorm.DB {
+		return d.Where("app = ?", app)
+	}
+}
+
+func WithTimerID(timerID uint) Option {
+	return func(d *gorm.DB) *gorm.DB {
+		return d.Where("timer_id = ?", timerID)
+	}
+}
+
+func WithStartTime(start time.Time) Option {
+	return func(d *gorm.DB) *gorm.DB {
+		return d.Where("created_at >= ?", start)
+	}
+}
+
+func WithEndTime(end time.Time) Option {
+	return func(d *gorm.DB) *gorm.DB {
+		return d.Where("created_at < ?", end)
+	}
+}
+
+func WithPageLimit(offset, limit int) Option {
+	return func(d *gorm.DB) *gorm.DB {
+		return d.Offset(offset).Limit(limit)
+	}
+}
+
+func WithDesc() Option {
+	return func(d *gorm.DB) *gorm.DB {
+		return d.Order("id DESC")
+	}
+}
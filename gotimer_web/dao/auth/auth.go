@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gotimer_web/common/model/po"
+	"gotimer_web/pkg/mysql"
+)
+
+type APIKeyDAO struct {
+	client *mysql.Client
+}
+
+func NewAPIKeyDAO(client *mysql.Client) *APIKeyDAO {
+	return &APIKeyDAO{
+		client: client,
+	}
+}
+
+func (a *APIKeyDAO) CreateAPIKey(ctx context.Context, key *po.APIKey) error {
+	return a.client.DB.WithContext(ctx).Create(key).Error
+}
+
+func (a *APIKeyDAO) GetAPIKeyByHash(ctx context.Context, keyHash string) (*po.APIKey, error) {
+	var key po.APIKey
+	return &key, a.client.DB.WithContext(ctx).Where("key_hash = ?", keyHash).First(&key).Error
+}
+
+// GetAPIKeys 获取 app 下的凭证，app 为空时返回全部
+func (a *APIKeyDAO) GetAPIKeys(ctx context.Context, app string) ([]*po.APIKey, error) {
+	db := a.client.DB.WithContext(ctx)
+	if app != "" {
+		db = db.Where("app = ?", app)
+	}
+	var keys []*po.APIKey
+	return keys, db.Order("id DESC").Find(&keys).Error
+}
+
+func (a *APIKeyDAO) DeleteAPIKey(ctx context.Context, id uint) error {
+	res := a.client.DB.WithContext(ctx).Delete(&po.APIKey{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
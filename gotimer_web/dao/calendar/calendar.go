@@ -0,0 +1,67 @@
+package calendar
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gotimer_web/common/model/po"
+	"gotimer_web/pkg/mysql"
+)
+
+type CalendarDAO struct {
+	client *mysql.Client
+}
+
+func NewCalendarDAO(client *mysql.Client) *CalendarDAO {
+	return &CalendarDAO{
+		client: client,
+	}
+}
+
+func (c *CalendarDAO) CreateCalendar(ctx context.Context, calendar *po.Calendar) error {
+	return c.client.DB.WithContext(ctx).Create(calendar).Error
+}
+
+func (c *CalendarDAO) UpdateCalendar(ctx context.Context, calendar *po.Calendar) error {
+	return c.client.DB.WithContext(ctx).Model(&po.Calendar{}).Where("app = ? AND name = ?", calendar.App, calendar.Name).
+		Updates(map[string]interface{}{
+			"description": calendar.Description,
+			"timezone":    calendar.Timezone,
+			"entries":     calendar.Entries,
+		}).Error
+}
+
+func (c *CalendarDAO) DeleteCalendar(ctx context.Context, app, name string) error {
+	res := c.client.DB.WithContext(ctx).Where("app = ? AND name = ?", app, name).Delete(&po.Calendar{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (c *CalendarDAO) GetCalendar(ctx context.Context, app, name string) (*po.Calendar, error) {
+	var calendar po.Calendar
+	return &calendar, c.client.DB.WithContext(ctx).Where("app = ? AND name = ?", app, name).First(&calendar).Error
+}
+
+// GetCalendars 获取 app 下的日历，names 为空时返回全部
+func (c *CalendarDAO) GetCalendars(ctx context.Context, app string, names ...string) ([]*po.Calendar, error) {
+	db := c.client.DB.WithContext(ctx).Where("app = ?", app)
+	if len(names) > 0 {
+		db = db.Where("name IN ?", names)
+	}
+	var calendars []*po.Calendar
+	return calendars, db.Order("id DESC").Find(&calendars).Error
+}
+
+// GetTimerCalendars 获取定时器引用的日历
+func (c *CalendarDAO) GetTimerCalendars(ctx context.Context, timer *po.Timer) ([]*po.Calendar, error) {
+	names := timer.CalendarNames()
+	if len(names) == 0 {
+		return nil, nil
+	}
+	return c.GetCalendars(ctx, timer.App, names...)
+}
@@ -0,0 +1,55 @@
+package secret
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gotimer_web/common/model/po"
+	"gotimer_web/pkg/mysql"
+)
+
+// MaxActiveSecrets 同一 app 同时生效的密钥数量，轮换期间新旧密钥同时生效
+const MaxActiveSecrets = 2
+
+type SecretDAO struct {
+	client *mysql.Client
+}
+
+func NewSecretDAO(client *mysql.Client) *SecretDAO {
+	return &SecretDAO{
+		client: client,
+	}
+}
+
+// GetActiveSecrets 获取 app 下生效的密钥，新密钥在前
+func (s *SecretDAO) GetActiveSecrets(ctx context.Context, app string) ([]*po.AppSecret, error) {
+	var secrets []*po.AppSecret
+	return secrets, s.client.DB.WithContext(ctx).Where("app = ?", app).Order("id DESC").Limit(MaxActiveSecrets).Find(&secrets).Error
+}
+
+// RotateSecret 新增密钥，仅保留最新的 MaxActiveSecrets 个密钥生效
+func (s *SecretDAO) RotateSecret(ctx context.Context, secret *po.AppSecret) error {
+	return s.client.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(secret).Error; err != nil {
+			return err
+		}
+
+		var activeIDs []uint
+		if err := tx.Model(&po.AppSecret{}).Where("app = ?", secret.App).Order("id DESC").Limit(MaxActiveSecrets).
+			Pluck("id", &activeIDs).Error; err != nil {
+			return err
+		}
+		return tx.Where("app = ? AND id NOT IN ?", secret.App, activeIDs).Delete(&po.AppSecret{}).Error
+	})
+}
+
+func (s *SecretDAO) DeleteSecret(ctx context.Context, app string, id uint) error {
+	res := s.client.DB.WithContext(ctx).Where("app = ?", app).Delete(&po.AppSecret{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
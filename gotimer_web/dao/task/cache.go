@@ -66,6 +66,19 @@ func (t *TaskCache) BatchCreateTasks(ctx context.Context, tasks []*po.Task, star
 	return err
 }
 
+// 从 redis 的 zset 中移除批量 task 对应的成员
+func (t *TaskCache) BatchDeleteTasks(ctx context.Context, tasks []*po.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	commands := make([]*redis.Command, 0, len(tasks))
+	for _, task := range tasks {
+		commands = append(commands, redis.NewZRemCommand(t.GetTableName(task), utils.UnionTimerIDUnix(task.TimerID, task.RunTimer.UnixMilli())))
+	}
+	_, err := t.client.Transaction(ctx, commands...)
+	return err
+}
+
 // 输入开始结束时间，得到一段时间内的持久化模型切片
 func (t *TaskCache) GetTasksByTime(ctx context.Context, table string, start, end int64) ([]*po.Task, error) {
 	timerIDUnixs, err := t.client.ZrangeByScore(ctx, table, start, end)
@@ -13,6 +13,12 @@ func WithTaskID(id uint) Option {
 	}
 }
 
+func WithApp(app string) Option {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("app = ?", app)
+	}
+}
+
 func WithTimerID(timeID uint) Option {
 	return func(db *gorm.DB) *gorm.DB {
 		return db.Where("timer_id = ?", timeID)
@@ -43,6 +49,12 @@ func WithStatus(status int32) Option {
 	}
 }
 
+func WithSource(source int32) Option {
+	return func(d *gorm.DB) *gorm.DB {
+		return d.Where("source = ?", source)
+	}
+}
+
 func WithStatuses(statuses []int32) Option {
 	return func(d *gorm.DB) *gorm.DB {
 		return d.Where("status IN ?", statuses)
@@ -41,6 +41,11 @@ func (t *TaskDAO) UpdateTask(ctx context.Context, task *po.Task) error {
 	return t.client.DB.WithContext(ctx).Updates(task).Error
 }
 
+func (t *TaskDAO) GetTaskOutputs(ctx context.Context, taskIDs []uint) ([]*po.TaskOutput, error) {
+	var outputs []*po.TaskOutput
+	return outputs, t.client.DB.WithContext(ctx).Where("task_id IN ?", taskIDs).Find(&outputs).Error
+}
+
 // 使用这些选项来修改数据库查询，并计算符合条件的记录数量，返回数量和任何遇到的错误。
 func (t *TaskDAO) Count(ctx context.Context, opts ...Option) (int64, error) {
 	db := t.client.DB.WithContext(ctx).Model(&po.Task{})
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"gorm.io/gorm"
+	"gotimer_web/pkg/selector"
 )
 
 type Option func(*gorm.DB) *gorm.DB
@@ -55,3 +56,51 @@ func WithPageLimit(offset, limit int) Option {
 		return d.Offset(offset).Limit(limit)
 	}
 }
+
+// WithCalendar 引用了日历 name 的定时器
+func WithCalendar(name string) Option {
+	return func(d *gorm.DB) *gorm.DB {
+		return d.Where("(FIND_IN_SET(?, include_calendars) > 0 OR FIND_IN_SET(?, exclude_calendars) > 0)", name, name)
+	}
+}
+
+// WithLabelSelector 标签满足选择器的定时器，每个条件对应 timer_label 上的一个 (NOT) EXISTS 子查询
+func WithLabelSelector(s selector.Selector) Option {
+	return func(d *gorm.DB) *gorm.DB {
+		for _, r := range s {
+			sub := d.Session(&gorm.Session{NewDB: true}).Table("timer_label").Select("1").
+				Where("timer_label.timer_id = timer.id AND timer_label.label_key = ?", r.Key)
+			switch r.Operator {
+			case selector.Equals, selector.In:
+				d = d.Where("EXISTS (?)", sub.Where("timer_label.label_value IN ?", r.Values))
+			case selector.NotEquals, selector.NotIn:
+				d = d.Where("NOT EXISTS (?)", sub.Where("timer_label.label_value IN ?", r.Values))
+			case selector.Exists:
+				d = d.Where("EXISTS (?)", sub)
+			case selector.DoesNotExist:
+				d = d.Where("NOT EXISTS (?)", sub)
+			}
+		}
+		return d
+	}
+}
+
+// WithIDAfter id 大于 id 的定时器，配合 WithIDAsc 按 id 游标分批读取
+func WithIDAfter(id uint) Option {
+	return func(d *gorm.DB) *gorm.DB {
+		return d.Where("id > ?", id)
+	}
+}
+
+func WithIDAsc() Option {
+	return func(d *gorm.DB) *gorm.DB {
+		return d.Order("id ASC")
+	}
+}
+
+// WithCallbackHost 回调地址中包含 host 的定时器，只做粗筛，精确匹配由调用方完成
+func WithCallbackHost(host string) Option {
+	return func(d *gorm.DB) *gorm.DB {
+		return d.Where("JSON_UNQUOTE(JSON_EXTRACT(notify_http_param, '$.url')) LIKE ?", "%://"+host+"%")
+	}
+}
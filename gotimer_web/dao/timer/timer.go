@@ -2,7 +2,11 @@ package timer
 
 import (
 	"context"
+	"time"
+
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gotimer_web/common/consts"
 	"gotimer_web/common/model/po"
 	"gotimer_web/pkg/log"
 	"gotimer_web/pkg/mysql"
@@ -76,6 +80,57 @@ func (t *TimerDAO) BatchCreateRecords(ctx context.Context, tasks []*po.Task) err
 	return t.client.DB.Model(&po.Task{}).WithContext(ctx).CreateInBatches(tasks, len(tasks)).Error
 }
 
+// 查询定时器在 start 之后处于指定状态的 task
+func (t *TimerDAO) GetRecordsAfter(ctx context.Context, timerID uint, start time.Time, status consts.TaskStatus) ([]*po.Task, error) {
+	var tasks []*po.Task
+	return tasks, t.client.DB.WithContext(ctx).Model(&po.Task{}).
+		Where("timer_id = ? AND run_timer >= ? AND status = ?", timerID, start, status.ToInt()).
+		Scan(&tasks).Error
+}
+
+// 查询定时器处于指定状态的全部 task
+func (t *TimerDAO) GetRecords(ctx context.Context, timerID uint, status consts.TaskStatus) ([]*po.Task, error) {
+	var tasks []*po.Task
+	return tasks, t.client.DB.WithContext(ctx).Model(&po.Task{}).
+		Where("timer_id = ? AND status = ?", timerID, status.ToInt()).
+		Scan(&tasks).Error
+}
+
+// 将 task 批量置为已取消，保留流水记录
+func (t *TimerDAO) BatchCancelRecords(ctx context.Context, tasks []*po.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	return t.client.DB.WithContext(ctx).Model(&po.Task{}).Where("id IN ?", ids).Update("status", consts.Cancelled.ToInt()).Error
+}
+
+// ReviveRecord 以重新生成的 task 覆盖同一执行时机已取消或已跳过的 task，保留原记录的 id
+func (t *TimerDAO) ReviveRecord(ctx context.Context, task *po.Task) error {
+	return t.client.DB.WithContext(ctx).Model(task).Select("status", "fail_reason", "timer_version").Updates(task).Error
+}
+
+// 物理删除 task，保证之后重新生成相同执行时间的 task 时不会触发 timer_id + run_timer 唯一键冲突
+func (t *TimerDAO) BatchDeleteRecords(ctx context.Context, tasks []*po.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	return t.client.DB.WithContext(ctx).Unscoped().Delete(&po.Task{}, ids).Error
+}
+
+// LockApp 在事务中以 SELECT ... FOR UPDATE 锁定应用记录，同一应用的配额校验与写入在事务提交前串行执行
+func (t *TimerDAO) LockApp(ctx context.Context, name string) error {
+	var app po.App
+	return t.client.DB.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("name = ?", name).First(&app).Error
+}
+
 // 查询时用FOR UPDATE上锁
 func (t *TimerDAO) DoWithLock(ctx context.Context, id uint, do func(ctx context.Context, dao *TimerDAO, timer *po.Timer) error) error {
 	return t.client.Transaction(func(tx *gorm.DB) error {
@@ -92,3 +147,53 @@ func (t *TimerDAO) DoWithLock(ctx context.Context, id uint, do func(ctx context.
 		return do(ctx, NewTimerDAO(mysql.NewClient(tx)), &timer)
 	})
 }
+
+// CreateAuditLog 写入审计日志，在 Transaction 或 DoWithLock 中调用时与定时器变更处于同一事务
+func (t *TimerDAO) CreateAuditLog(ctx context.Context, auditLog *po.AuditLog) error {
+	return t.client.DB.WithContext(ctx).Create(auditLog).Error
+}
+
+// CreateTimerVersion 写入定时器的历史版本，与定时器变更处于同一事务
+func (t *TimerDAO) CreateTimerVersion(ctx context.Context, version *po.TimerVersion) error {
+	return t.client.DB.WithContext(ctx).Create(version).Error
+}
+
+func (t *TimerDAO) GetTimerVersion(ctx context.Context, timerID uint, version int) (*po.TimerVersion, error) {
+	var timerVersion po.TimerVersion
+	return &timerVersion, t.client.DB.WithContext(ctx).Where("timer_id = ? AND version = ?", timerID, version).First(&timerVersion).Error
+}
+
+// ReplaceTimer 以 timer 覆盖定时器的全部字段，零值字段同样写入
+func (t *TimerDAO) ReplaceTimer(ctx context.Context, timer *po.Timer) error {
+	return t.client.DB.WithContext(ctx).Model(timer).Select("*").Omit("id", "created_at", "deleted_at").Updates(timer).Error
+}
+
+// ReplaceTimerLabels 以 labels 覆盖定时器的全部标签
+func (t *TimerDAO) ReplaceTimerLabels(ctx context.Context, timer *po.Timer, labels map[string]string) error {
+	db := t.client.DB.WithContext(ctx)
+	if err := db.Where("timer_id = ?", timer.ID).Delete(&po.TimerLabel{}).Error; err != nil {
+		return err
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+
+	rows := make([]*po.TimerLabel, 0, len(labels))
+	for key, value := range labels {
+		rows = append(rows, &po.TimerLabel{
+			App:     timer.App,
+			TimerID: timer.ID,
+			Key:     key,
+			Value:   value,
+		})
+	}
+	return db.Create(&rows).Error
+}
+
+func (t *TimerDAO) GetTimerLabels(ctx context.Context, timerIDs []uint) ([]*po.TimerLabel, error) {
+	var labels []*po.TimerLabel
+	if len(timerIDs) == 0 {
+		return labels, nil
+	}
+	return labels, t.client.DB.WithContext(ctx).Where("timer_id IN ?", timerIDs).Find(&labels).Error
+}
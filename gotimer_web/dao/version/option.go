@@ -0,0 +1,29 @@
+package version
+
+import "gorm.io/gorm"
+
+type Option func(*gorm.DB) *gorm.DB
+
+func WithTimerID(timerID uint) Option {
+	return func(d *gorm.DB) *gorm.DB {
+		return d.Where("timer_id = ?", timerID)
+	}
+}
+
+func WithVersions(versions ...int) Option {
+	return func(d *gorm.DB) *gorm.DB {
+		return d.Where("version IN ?", versions)
+	}
+}
+
+func WithPageLimit(offset, limit int) Option {
+	return func(d *gorm.DB) *gorm.DB {
+		return d.Offset(offset).Limit(limit)
+	}
+}
+
+func WithDesc() Option {
+	return func(d *gorm.DB) *gorm.DB {
+		return d.Order("version DESC")
+	}
+}
@@ -0,0 +1,36 @@
+package version
+
+import (
+	"context"
+
+	"gotimer_web/common/model/po"
+	"gotimer_web/pkg/mysql"
+)
+
+type VersionDAO struct {
+	client *mysql.Client
+}
+
+func NewVersionDAO(client *mysql.Client) *VersionDAO {
+	return &VersionDAO{
+		client: client,
+	}
+}
+
+func (v *VersionDAO) GetTimerVersions(ctx context.Context, opts ...Option) ([]*po.TimerVersion, error) {
+	db := v.client.DB.WithContext(ctx).Model(&po.TimerVersion{})
+	for _, opt := range opts {
+		db = opt(db)
+	}
+	var versions []*po.TimerVersion
+	return versions, db.Find(&versions).Error
+}
+
+func (v *VersionDAO) Count(ctx context.Context, opts ...Option) (int64, error) {
+	db := v.client.DB.WithContext(ctx).Model(&po.TimerVersion{})
+	for _, opt := range opts {
+		db = opt(db)
+	}
+	var cnt int64
+	return cnt, db.Count(&cnt).Error
+}
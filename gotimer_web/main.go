@@ -9,18 +9,23 @@ import (
 	"os/signal"
 	"runtime"
 	"syscall"
+	// 内置时区数据库，镜像中缺少 tzdata 时定时器时区依然可用
+	_ "time/tzdata"
 )
 
 func main() {
 	migratorApp := app.GetMigratorApp()
 	//schedulerApp := app.GetSchedulerApp()
 	webServer := app.GetWebServer()
+	grpcServer := app.GetGRPCServer()
 
 	migratorApp.Start()
 	//schedulerApp.Start()
 	//defer schedulerApp.Stop()
 
 	webServer.Start()
+	grpcServer.Start()
+	defer grpcServer.Stop()
 
 	//go func() {
 	//	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {})
@@ -0,0 +1,43 @@
+package mq
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"gopkg.in/yaml.v2"
+)
+
+type PulsarClient struct {
+	Client pulsar.Client
+}
+
+type PulsarConfig struct {
+	URL string `yaml:"url"`
+}
+
+// GetPulsarClient 按 conf.yml 中的 url 创建 Pulsar 客户端
+func GetPulsarClient() (*PulsarClient, error) {
+	configFile, err := os.ReadFile("conf.yml")
+	if err != nil {
+		return nil, fmt.Errorf("read config file failed, err: %w", err)
+	}
+
+	var config PulsarConfig
+	if err := yaml.Unmarshal(configFile, &config); err != nil {
+		return nil, fmt.Errorf("unmarshal config file failed, err: %w", err)
+	}
+
+	client, err := pulsar.NewClient(pulsar.ClientOptions{
+		URL:               config.URL,
+		OperationTimeout:  30 * time.Second,
+		ConnectionTimeout: 30 * time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create pulsar client failed, url: %s, err: %w", config.URL, err)
+	}
+	return &PulsarClient{
+		Client: client,
+	}, nil
+}
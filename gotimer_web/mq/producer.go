@@ -0,0 +1,53 @@
+package mq
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+)
+
+// TriggerProducer 向 trigger-topic 投递 timerID_unix 格式的消息，由 executor 消费执行.
+// 首次投递时才连接 Pulsar，连接失败时下一次投递重试，Pulsar 不可用不影响 web 服务启动和其余接口
+type TriggerProducer struct {
+	mu       sync.Mutex
+	producer pulsar.Producer
+}
+
+func NewTriggerProducer() *TriggerProducer {
+	return &TriggerProducer{}
+}
+
+func (t *TriggerProducer) Send(ctx context.Context, timerIDUnixKey string) error {
+	producer, err := t.getProducer()
+	if err != nil {
+		return fmt.Errorf("trigger producer is unavailable, err: %w", err)
+	}
+	_, err = producer.Send(ctx, &pulsar.ProducerMessage{
+		Payload: []byte(timerIDUnixKey),
+	})
+	return err
+}
+
+func (t *TriggerProducer) getProducer() (pulsar.Producer, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.producer != nil {
+		return t.producer, nil
+	}
+
+	pc, err := GetPulsarClient()
+	if err != nil {
+		return nil, err
+	}
+	producer, err := pc.Client.CreateProducer(pulsar.ProducerOptions{
+		Topic: "trigger-topic",
+	})
+	if err != nil {
+		pc.Client.Close()
+		return nil, err
+	}
+	t.producer = producer
+	return producer, nil
+}
@@ -0,0 +1,650 @@
+// gotimer 的 gRPC 接口，与 http 接口共用 service 层.
+// 修改后在 gotimer_web 目录下执行以下命令重新生成代码:
+// protoc --go_out=. --go_opt=module=gotimer_web --go-grpc_out=. --go-grpc_opt=module=gotimer_web pb/gotimer.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: pb/gotimer.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	TimerService_CreateTimer_FullMethodName       = "/gotimer.v1.TimerService/CreateTimer"
+	TimerService_GetTimer_FullMethodName          = "/gotimer.v1.TimerService/GetTimer"
+	TimerService_UpdateTimer_FullMethodName       = "/gotimer.v1.TimerService/UpdateTimer"
+	TimerService_DeleteTimer_FullMethodName       = "/gotimer.v1.TimerService/DeleteTimer"
+	TimerService_ListTimers_FullMethodName        = "/gotimer.v1.TimerService/ListTimers"
+	TimerService_EnableTimer_FullMethodName       = "/gotimer.v1.TimerService/EnableTimer"
+	TimerService_DisableTimer_FullMethodName      = "/gotimer.v1.TimerService/DisableTimer"
+	TimerService_RunTimer_FullMethodName          = "/gotimer.v1.TimerService/RunTimer"
+	TimerService_PreviewCron_FullMethodName       = "/gotimer.v1.TimerService/PreviewCron"
+	TimerService_ListTimerVersions_FullMethodName = "/gotimer.v1.TimerService/ListTimerVersions"
+	TimerService_DiffTimerVersions_FullMethodName = "/gotimer.v1.TimerService/DiffTimerVersions"
+	TimerService_RollbackTimer_FullMethodName     = "/gotimer.v1.TimerService/RollbackTimer"
+	TimerService_BulkTimers_FullMethodName        = "/gotimer.v1.TimerService/BulkTimers"
+)
+
+// TimerServiceClient is the client API for TimerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TimerServiceClient interface {
+	CreateTimer(ctx context.Context, in *Timer, opts ...grpc.CallOption) (*CreateTimerResponse, error)
+	GetTimer(ctx context.Context, in *TimerRequest, opts ...grpc.CallOption) (*Timer, error)
+	UpdateTimer(ctx context.Context, in *UpdateTimerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DeleteTimer(ctx context.Context, in *TimerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListTimers(ctx context.Context, in *ListTimersRequest, opts ...grpc.CallOption) (*ListTimersResponse, error)
+	EnableTimer(ctx context.Context, in *TimerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DisableTimer(ctx context.Context, in *TimerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// 立即触发一次定时器，返回手动触发的 task id
+	RunTimer(ctx context.Context, in *TimerRequest, opts ...grpc.CallOption) (*RunTimerResponse, error)
+	PreviewCron(ctx context.Context, in *PreviewCronRequest, opts ...grpc.CallOption) (*CronPreview, error)
+	ListTimerVersions(ctx context.Context, in *ListTimerVersionsRequest, opts ...grpc.CallOption) (*ListTimerVersionsResponse, error)
+	DiffTimerVersions(ctx context.Context, in *DiffTimerVersionsRequest, opts ...grpc.CallOption) (*TimerVersionDiff, error)
+	RollbackTimer(ctx context.Context, in *RollbackTimerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	BulkTimers(ctx context.Context, in *BulkTimersRequest, opts ...grpc.CallOption) (*BulkTimersReport, error)
+}
+
+type timerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTimerServiceClient(cc grpc.ClientConnInterface) TimerServiceClient {
+	return &timerServiceClient{cc}
+}
+
+func (c *timerServiceClient) CreateTimer(ctx context.Context, in *Timer, opts ...grpc.CallOption) (*CreateTimerResponse, error) {
+	out := new(CreateTimerResponse)
+	err := c.cc.Invoke(ctx, TimerService_CreateTimer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timerServiceClient) GetTimer(ctx context.Context, in *TimerRequest, opts ...grpc.CallOption) (*Timer, error) {
+	out := new(Timer)
+	err := c.cc.Invoke(ctx, TimerService_GetTimer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timerServiceClient) UpdateTimer(ctx context.Context, in *UpdateTimerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TimerService_UpdateTimer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timerServiceClient) DeleteTimer(ctx context.Context, in *TimerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TimerService_DeleteTimer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timerServiceClient) ListTimers(ctx context.Context, in *ListTimersRequest, opts ...grpc.CallOption) (*ListTimersResponse, error) {
+	out := new(ListTimersResponse)
+	err := c.cc.Invoke(ctx, TimerService_ListTimers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timerServiceClient) EnableTimer(ctx context.Context, in *TimerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TimerService_EnableTimer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timerServiceClient) DisableTimer(ctx context.Context, in *TimerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TimerService_DisableTimer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timerServiceClient) RunTimer(ctx context.Context, in *TimerRequest, opts ...grpc.CallOption) (*RunTimerResponse, error) {
+	out := new(RunTimerResponse)
+	err := c.cc.Invoke(ctx, TimerService_RunTimer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timerServiceClient) PreviewCron(ctx context.Context, in *PreviewCronRequest, opts ...grpc.CallOption) (*CronPreview, error) {
+	out := new(CronPreview)
+	err := c.cc.Invoke(ctx, TimerService_PreviewCron_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timerServiceClient) ListTimerVersions(ctx context.Context, in *ListTimerVersionsRequest, opts ...grpc.CallOption) (*ListTimerVersionsResponse, error) {
+	out := new(ListTimerVersionsResponse)
+	err := c.cc.Invoke(ctx, TimerService_ListTimerVersions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timerServiceClient) DiffTimerVersions(ctx context.Context, in *DiffTimerVersionsRequest, opts ...grpc.CallOption) (*TimerVersionDiff, error) {
+	out := new(TimerVersionDiff)
+	err := c.cc.Invoke(ctx, TimerService_DiffTimerVersions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timerServiceClient) RollbackTimer(ctx context.Context, in *RollbackTimerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TimerService_RollbackTimer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timerServiceClient) BulkTimers(ctx context.Context, in *BulkTimersRequest, opts ...grpc.CallOption) (*BulkTimersReport, error) {
+	out := new(BulkTimersReport)
+	err := c.cc.Invoke(ctx, TimerService_BulkTimers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TimerServiceServer is the server API for TimerService service.
+// All implementations must embed UnimplementedTimerServiceServer
+// for forward compatibility
+type TimerServiceServer interface {
+	CreateTimer(context.Context, *Timer) (*CreateTimerResponse, error)
+	GetTimer(context.Context, *TimerRequest) (*Timer, error)
+	UpdateTimer(context.Context, *UpdateTimerRequest) (*emptypb.Empty, error)
+	DeleteTimer(context.Context, *TimerRequest) (*emptypb.Empty, error)
+	ListTimers(context.Context, *ListTimersRequest) (*ListTimersResponse, error)
+	EnableTimer(context.Context, *TimerRequest) (*emptypb.Empty, error)
+	DisableTimer(context.Context, *TimerRequest) (*emptypb.Empty, error)
+	// 立即触发一次定时器，返回手动触发的 task id
+	RunTimer(context.Context, *TimerRequest) (*RunTimerResponse, error)
+	PreviewCron(context.Context, *PreviewCronRequest) (*CronPreview, error)
+	ListTimerVersions(context.Context, *ListTimerVersionsRequest) (*ListTimerVersionsResponse, error)
+	DiffTimerVersions(context.Context, *DiffTimerVersionsRequest) (*TimerVersionDiff, error)
+	RollbackTimer(context.Context, *RollbackTimerRequest) (*emptypb.Empty, error)
+	BulkTimers(context.Context, *BulkTimersRequest) (*BulkTimersReport, error)
+	mustEmbedUnimplementedTimerServiceServer()
+}
+
+// UnimplementedTimerServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedTimerServiceServer struct {
+}
+
+func (UnimplementedTimerServiceServer) CreateTimer(context.Context, *Timer) (*CreateTimerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTimer not implemented")
+}
+func (UnimplementedTimerServiceServer) GetTimer(context.Context, *TimerRequest) (*Timer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTimer not implemented")
+}
+func (UnimplementedTimerServiceServer) UpdateTimer(context.Context, *UpdateTimerRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTimer not implemented")
+}
+func (UnimplementedTimerServiceServer) DeleteTimer(context.Context, *TimerRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTimer not implemented")
+}
+func (UnimplementedTimerServiceServer) ListTimers(context.Context, *ListTimersRequest) (*ListTimersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTimers not implemented")
+}
+func (UnimplementedTimerServiceServer) EnableTimer(context.Context, *TimerRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnableTimer not implemented")
+}
+func (UnimplementedTimerServiceServer) DisableTimer(context.Context, *TimerRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableTimer not implemented")
+}
+func (UnimplementedTimerServiceServer) RunTimer(context.Context, *TimerRequest) (*RunTimerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunTimer not implemented")
+}
+func (UnimplementedTimerServiceServer) PreviewCron(context.Context, *PreviewCronRequest) (*CronPreview, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PreviewCron not implemented")
+}
+func (UnimplementedTimerServiceServer) ListTimerVersions(context.Context, *ListTimerVersionsRequest) (*ListTimerVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTimerVersions not implemented")
+}
+func (UnimplementedTimerServiceServer) DiffTimerVersions(context.Context, *DiffTimerVersionsRequest) (*TimerVersionDiff, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffTimerVersions not implemented")
+}
+func (UnimplementedTimerServiceServer) RollbackTimer(context.Context, *RollbackTimerRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollbackTimer not implemented")
+}
+func (UnimplementedTimerServiceServer) BulkTimers(context.Context, *BulkTimersRequest) (*BulkTimersReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BulkTimers not implemented")
+}
+func (UnimplementedTimerServiceServer) mustEmbedUnimplementedTimerServiceServer() {}
+
+// UnsafeTimerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TimerServiceServer will
+// result in compilation errors.
+type UnsafeTimerServiceServer interface {
+	mustEmbedUnimplementedTimerServiceServer()
+}
+
+func RegisterTimerServiceServer(s grpc.ServiceRegistrar, srv TimerServiceServer) {
+	s.RegisterService(&TimerService_ServiceDesc, srv)
+}
+
+func _TimerService_CreateTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Timer)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimerServiceServer).CreateTimer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimerService_CreateTimer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimerServiceServer).CreateTimer(ctx, req.(*Timer))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimerService_GetTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimerServiceServer).GetTimer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimerService_GetTimer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimerServiceServer).GetTimer(ctx, req.(*TimerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimerService_UpdateTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTimerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimerServiceServer).UpdateTimer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimerService_UpdateTimer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimerServiceServer).UpdateTimer(ctx, req.(*UpdateTimerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimerService_DeleteTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimerServiceServer).DeleteTimer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimerService_DeleteTimer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimerServiceServer).DeleteTimer(ctx, req.(*TimerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimerService_ListTimers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTimersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimerServiceServer).ListTimers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimerService_ListTimers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimerServiceServer).ListTimers(ctx, req.(*ListTimersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimerService_EnableTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimerServiceServer).EnableTimer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimerService_EnableTimer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimerServiceServer).EnableTimer(ctx, req.(*TimerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimerService_DisableTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimerServiceServer).DisableTimer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimerService_DisableTimer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimerServiceServer).DisableTimer(ctx, req.(*TimerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimerService_RunTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimerServiceServer).RunTimer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimerService_RunTimer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimerServiceServer).RunTimer(ctx, req.(*TimerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimerService_PreviewCron_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreviewCronRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimerServiceServer).PreviewCron(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimerService_PreviewCron_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimerServiceServer).PreviewCron(ctx, req.(*PreviewCronRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimerService_ListTimerVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTimerVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimerServiceServer).ListTimerVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimerService_ListTimerVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimerServiceServer).ListTimerVersions(ctx, req.(*ListTimerVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimerService_DiffTimerVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffTimerVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimerServiceServer).DiffTimerVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimerService_DiffTimerVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimerServiceServer).DiffTimerVersions(ctx, req.(*DiffTimerVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimerService_RollbackTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackTimerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimerServiceServer).RollbackTimer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimerService_RollbackTimer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimerServiceServer).RollbackTimer(ctx, req.(*RollbackTimerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimerService_BulkTimers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BulkTimersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimerServiceServer).BulkTimers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimerService_BulkTimers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimerServiceServer).BulkTimers(ctx, req.(*BulkTimersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TimerService_ServiceDesc is the grpc.ServiceDesc for TimerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TimerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gotimer.v1.TimerService",
+	HandlerType: (*TimerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateTimer",
+			Handler:    _TimerService_CreateTimer_Handler,
+		},
+		{
+			MethodName: "GetTimer",
+			Handler:    _TimerService_GetTimer_Handler,
+		},
+		{
+			MethodName: "UpdateTimer",
+			Handler:    _TimerService_UpdateTimer_Handler,
+		},
+		{
+			MethodName: "DeleteTimer",
+			Handler:    _TimerService_DeleteTimer_Handler,
+		},
+		{
+			MethodName: "ListTimers",
+			Handler:    _TimerService_ListTimers_Handler,
+		},
+		{
+			MethodName: "EnableTimer",
+			Handler:    _TimerService_EnableTimer_Handler,
+		},
+		{
+			MethodName: "DisableTimer",
+			Handler:    _TimerService_DisableTimer_Handler,
+		},
+		{
+			MethodName: "RunTimer",
+			Handler:    _TimerService_RunTimer_Handler,
+		},
+		{
+			MethodName: "PreviewCron",
+			Handler:    _TimerService_PreviewCron_Handler,
+		},
+		{
+			MethodName: "ListTimerVersions",
+			Handler:    _TimerService_ListTimerVersions_Handler,
+		},
+		{
+			MethodName: "DiffTimerVersions",
+			Handler:    _TimerService_DiffTimerVersions_Handler,
+		},
+		{
+			MethodName: "RollbackTimer",
+			Handler:    _TimerService_RollbackTimer_Handler,
+		},
+		{
+			MethodName: "BulkTimers",
+			Handler:    _TimerService_BulkTimers_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pb/gotimer.proto",
+}
+
+const (
+	TaskService_ListTasks_FullMethodName = "/gotimer.v1.TaskService/ListTasks"
+)
+
+// TaskServiceClient is the client API for TaskService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TaskServiceClient interface {
+	ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*ListTasksResponse, error)
+}
+
+type taskServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTaskServiceClient(cc grpc.ClientConnInterface) TaskServiceClient {
+	return &taskServiceClient{cc}
+}
+
+func (c *taskServiceClient) ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*ListTasksResponse, error) {
+	out := new(ListTasksResponse)
+	err := c.cc.Invoke(ctx, TaskService_ListTasks_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TaskServiceServer is the server API for TaskService service.
+// All implementations must embed UnimplementedTaskServiceServer
+// for forward compatibility
+type TaskServiceServer interface {
+	ListTasks(context.Context, *ListTasksRequest) (*ListTasksResponse, error)
+	mustEmbedUnimplementedTaskServiceServer()
+}
+
+// UnimplementedTaskServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedTaskServiceServer struct {
+}
+
+func (UnimplementedTaskServiceServer) ListTasks(context.Context, *ListTasksRequest) (*ListTasksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTasks not implemented")
+}
+func (UnimplementedTaskServiceServer) mustEmbedUnimplementedTaskServiceServer() {}
+
+// UnsafeTaskServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TaskServiceServer will
+// result in compilation errors.
+type UnsafeTaskServiceServer interface {
+	mustEmbedUnimplementedTaskServiceServer()
+}
+
+func RegisterTaskServiceServer(s grpc.ServiceRegistrar, srv TaskServiceServer) {
+	s.RegisterService(&TaskService_ServiceDesc, srv)
+}
+
+func _TaskService_ListTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskServiceServer).ListTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskService_ListTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskServiceServer).ListTasks(ctx, req.(*ListTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TaskService_ServiceDesc is the grpc.ServiceDesc for TaskService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TaskService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gotimer.v1.TaskService",
+	HandlerType: (*TaskServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListTasks",
+			Handler:    _TaskService_ListTasks_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pb/gotimer.proto",
+}
@@ -0,0 +1,141 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gorhill/cronexpr"
+)
+
+// 与 cronexpr 支持的预定义表达式一致，统一展开为 7 段
+var macros = map[string]string{
+	"@yearly":   "0 0 0 1 1 * *",
+	"@annually": "0 0 0 1 1 * *",
+	"@monthly":  "0 0 0 1 * * *",
+	"@weekly":   "0 0 0 * * 0 *",
+	"@daily":    "0 0 0 * * * *",
+	"@hourly":   "0 0 * * * * *",
+}
+
+var (
+	monthNames = []string{"", "January", "February", "March", "April", "May", "June", "July",
+		"August", "September", "October", "November", "December"}
+	weekdayNames = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+)
+
+// Describe 将 cron 表达式翻译为可读的英文描述，无法识别的字段按原文输出
+func (c *CronParser) Describe(cron string) (string, error) {
+	resolved, err := c.ResolveHash(cron, 0)
+	if err != nil {
+		return "", err
+	}
+	if _, err := cronexpr.Parse(resolved); err != nil {
+		return "", err
+	}
+
+	sec, min, hour, dom, month, dow, year := splitFields(cron)
+
+	var parts []string
+	if isNumber(sec) && isNumber(min) && isNumber(hour) {
+		h, _ := strconv.Atoi(hour)
+		m, _ := strconv.Atoi(min)
+		s, _ := strconv.Atoi(sec)
+		parts = append(parts, fmt.Sprintf("at %02d:%02d:%02d", h, m, s))
+	} else {
+		// 由粗到细描述时分秒，秒固定为 0 时省略
+		if hour != "*" {
+			parts = append(parts, describeField(hour, "hour", nil))
+		}
+		switch {
+		case isNumber(min) && hour == "*":
+			parts = append(parts, fmt.Sprintf("at minute %s of every hour", min))
+		case isNumber(min):
+			parts = append(parts, "at minute "+min)
+		case min != "*" || hour != "*":
+			parts = append(parts, describeField(min, "minute", nil))
+		}
+		if sec != "0" {
+			parts = append(parts, describeField(sec, "second", nil))
+		}
+		if sec == "0" && min == "*" && hour == "*" {
+			parts = append(parts, "every minute")
+		}
+	}
+
+	if dom != "*" && dom != "?" {
+		parts = append(parts, "on "+describeField(dom, "day-of-month", nil))
+	}
+	if month != "*" {
+		parts = append(parts, "in "+describeField(month, "month", monthNames))
+	}
+	if dow != "*" && dow != "?" {
+		parts = append(parts, "on "+describeField(dow, "day-of-week", weekdayNames))
+	}
+	if year != "*" {
+		parts = append(parts, "in "+describeField(year, "year", nil))
+	}
+
+	desc := strings.Join(parts, ", ")
+	return strings.ToUpper(desc[:1]) + desc[1:], nil
+}
+
+// DomAndDowRestricted 日和周字段是否同时受限，此时任一字段命中即触发
+func (c *CronParser) DomAndDowRestricted(cron string) bool {
+	_, _, _, dom, _, dow, _ := splitFields(cron)
+	return dom != "*" && dom != "?" && dow != "*" && dow != "?"
+}
+
+// 将 5、6 段及预定义表达式统一拆分为 秒 分 时 日 月 周 年 7 段，规则同 cronexpr
+func splitFields(cron string) (sec, min, hour, dom, month, dow, year string) {
+	cron = strings.TrimSpace(cron)
+	if macro, ok := macros[strings.ToLower(cron)]; ok {
+		cron = macro
+	}
+
+	fields := strings.Fields(cron)
+	switch len(fields) {
+	case 5:
+		fields = append(append([]string{"0"}, fields...), "*")
+	case 6:
+		fields = append([]string{"0"}, fields...)
+	}
+	return fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], fields[6]
+}
+
+// describeField 描述单个字段，names 不为空时将数字替换为名称
+func describeField(field, unit string, names []string) string {
+	name := func(v string) string {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 && n < len(names) {
+			return names[n]
+		}
+		return v
+	}
+
+	switch {
+	case strings.HasPrefix(field, "H"):
+		return fmt.Sprintf("%s %s (hashed per timer)", unit, field)
+	case field == "*":
+		return "every " + unit
+	case strings.HasPrefix(field, "*/"):
+		return fmt.Sprintf("every %s %ss", field[2:], unit)
+	case strings.Contains(field, ","):
+		values := strings.Split(field, ",")
+		for i, v := range values {
+			values[i] = name(v)
+		}
+		return fmt.Sprintf("%s %s", unit, strings.Join(values, ", "))
+	case strings.Contains(field, "-") && !strings.Contains(field, "/"):
+		bounds := strings.SplitN(field, "-", 2)
+		return fmt.Sprintf("%s %s through %s", unit, name(bounds[0]), name(bounds[1]))
+	case isNumber(field) && names != nil:
+		return name(field)
+	default:
+		return fmt.Sprintf("%s %s", unit, field)
+	}
+}
+
+func isNumber(field string) bool {
+	_, err := strconv.Atoi(field)
+	return err == nil
+}
@@ -0,0 +1,29 @@
+package cron
+
+import "testing"
+
+func TestDescribe(t *testing.T) {
+	cases := []struct {
+		cron string
+		want string
+	}{
+		{cron: "30 9 * * *", want: "At 09:30:00"},
+		{cron: "*/5 * * * *", want: "Every 5 minutes"},
+		{cron: "* * * * *", want: "Every minute"},
+		{cron: "0 9 * * 1-5", want: "At 09:00:00, on day-of-week Monday through Friday"},
+		{cron: "0 0 1 1,7 *", want: "At 00:00:00, on day-of-month 1, in month January, July"},
+		{cron: "@hourly", want: "At minute 0 of every hour"},
+		{cron: "0 */2 * * *", want: "Every 2 hours, at minute 0"},
+		{cron: "* * * * * * *", want: "Every second"},
+		{cron: "H H(9-17) * * *", want: "Hour H(9-17) (hashed per timer), minute H (hashed per timer)"},
+	}
+	for _, c := range cases {
+		got, err := NewCronParser().Describe(c.cron)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Fatalf("cron: %s, want: %q, got: %q", c.cron, c.want, got)
+		}
+	}
+}
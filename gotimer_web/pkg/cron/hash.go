@@ -0,0 +1,89 @@
+package cron
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 各字段可取值的范围，日取 1-28 保证每个月都能命中；年份字段不支持 H
+var hashRanges = []struct{ lo, hi int }{
+	{0, 59}, // 秒
+	{0, 59}, // 分
+	{0, 23}, // 时
+	{1, 28}, // 日
+	{1, 12}, // 月
+	{0, 6},  // 周
+}
+
+var hashPattern = regexp.MustCompile(`^H(?:\((\d+)-(\d+)\))?(?:/(\d+))?$`)
+
+// ResolveHash 将 cron 表达式中的 H 替换为由 seed 哈希出的固定取值，用法同 Jenkins：
+// H 取字段范围内的固定值，H(a-b) 取 [a, b] 内的固定值，H/n 及 H(a-b)/n 以固定偏移为起点每 n 个单位触发一次.
+// 只处理以 H 开头的字段，THU 等含 H 的名称及其余字段原样保留
+func (c *CronParser) ResolveHash(cron string, seed uint64) (string, error) {
+	if !strings.Contains(cron, "H") {
+		return cron, nil
+	}
+
+	fields := strings.Fields(cron)
+	// 5、6 段表达式不含秒字段
+	offset := 0
+	switch len(fields) {
+	case 5, 6:
+		offset = 1
+	case 7:
+	default:
+		return "", fmt.Errorf("invalid cron expr: %s", cron)
+	}
+
+	for i, field := range fields {
+		if !strings.HasPrefix(field, "H") {
+			continue
+		}
+		idx := i + offset
+		if idx >= len(hashRanges) {
+			return "", fmt.Errorf("H is not supported in year field: %s", cron)
+		}
+		resolved, err := resolveHashField(field, hashRanges[idx].lo, hashRanges[idx].hi, hashOf(seed, idx))
+		if err != nil {
+			return "", fmt.Errorf("invalid cron expr: %s, err: %w", cron, err)
+		}
+		fields[i] = resolved
+	}
+	return strings.Join(fields, " "), nil
+}
+
+func resolveHashField(field string, lo, hi int, h uint64) (string, error) {
+	matches := hashPattern.FindStringSubmatch(field)
+	if matches == nil {
+		return "", fmt.Errorf("invalid H field: %s", field)
+	}
+
+	if matches[1] != "" {
+		a, _ := strconv.Atoi(matches[1])
+		b, _ := strconv.Atoi(matches[2])
+		if a < lo || b > hi || a > b {
+			return "", fmt.Errorf("range of H field out of bounds: %s", field)
+		}
+		lo, hi = a, b
+	}
+
+	if matches[3] == "" {
+		return strconv.Itoa(lo + int(h%uint64(hi-lo+1))), nil
+	}
+	step, _ := strconv.Atoi(matches[3])
+	if step <= 0 || step > hi-lo+1 {
+		return "", fmt.Errorf("invalid step of H field: %s", field)
+	}
+	return fmt.Sprintf("%d-%d/%d", lo+int(h%uint64(step)), hi, step), nil
+}
+
+// 同一 seed 在不同字段上取不同的哈希值，避免各字段偏移相同
+func hashOf(seed uint64, field int) uint64 {
+	hasher := fnv.New64a()
+	_, _ = hasher.Write([]byte(fmt.Sprintf("%d:%d", seed, field)))
+	return hasher.Sum64()
+}
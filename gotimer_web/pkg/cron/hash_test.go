@@ -0,0 +1,64 @@
+package cron
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolveHash(t *testing.T) {
+	parser := NewCronParser()
+	cases := []string{"H * * * *", "H(0-29) H(9-17) * * 1-5", "H/15 * * * *", "H(10-40)/10 * * * * * *", "0 0 H H * *"}
+	for _, c := range cases {
+		resolved, err := parser.ResolveHash(c, 42)
+		if err != nil {
+			t.Fatalf("cron: %s, err: %v", c, err)
+		}
+		if !parser.IsValidCronExpr(resolved) {
+			t.Fatalf("cron: %s, invalid resolved expr: %s", c, resolved)
+		}
+		// 同一 seed 的结果固定
+		if again, _ := parser.ResolveHash(c, 42); again != resolved {
+			t.Fatalf("cron: %s, unstable resolved expr: %s, %s", c, resolved, again)
+		}
+	}
+
+	// 星期名称中的 H 不是哈希取值，原样保留
+	for _, c := range []string{"0 9 * * THU", "0 9 * * MON-THU", "H 9 * * THU"} {
+		resolved, err := parser.ResolveHash(c, 42)
+		if err != nil || !strings.HasSuffix(resolved, c[strings.LastIndex(c, " "):]) || !parser.IsValidCronExpr(c) {
+			t.Fatalf("cron: %s, resolved: %s, err: %v", c, resolved, err)
+		}
+	}
+
+	for _, c := range []string{"H(50-70) * * * *", "* * * * * H", "H/0 * * * *", "HH * * * *"} {
+		if _, err := parser.ResolveHash(c, 42); err == nil {
+			t.Fatalf("cron: %s, expect err", c)
+		}
+	}
+}
+
+func TestResolveHashSpread(t *testing.T) {
+	parser := NewCronParser()
+	minutes := make(map[string]bool)
+	for seed := uint64(1); seed <= 100; seed++ {
+		resolved, err := parser.ResolveHash("H * * * *", seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		minutes[resolved] = true
+	}
+	// 100 个定时器应当分散到多个不同的分钟
+	if len(minutes) < 30 {
+		t.Fatalf("hashed minutes not spread, distinct: %d", len(minutes))
+	}
+
+	resolved, _ := parser.ResolveHash("H/15 * * * *", 7)
+	nexts, err := parser.NextsBetweenIn(resolved, time.UTC, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nexts) != 4 {
+		t.Fatalf("unexpected nexts of %s: %v", resolved, nexts)
+	}
+}
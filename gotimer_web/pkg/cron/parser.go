@@ -13,8 +13,13 @@ func NewCronParser() *CronParser {
 	return &CronParser{}
 }
 
+// IsValidCronExpr 校验 cron 表达式，H 按任意 seed 替换后校验
 func (c *CronParser) IsValidCronExpr(cron string) bool {
-	_, err := cronexpr.Parse(cron)
+	resolved, err := c.ResolveHash(cron, 0)
+	if err != nil {
+		return false
+	}
+	_, err = cronexpr.Parse(resolved)
 	return err == nil
 }
 
@@ -36,8 +41,15 @@ func (c *CronParser) NextsBefore(cron string, end time.Time) ([]time.Time, error
 	return c.NextsBetween(cron, time.Now(), end)
 }
 
-// NextsBetween 返回时间段内触发时机的切片
+// NextsBetween 返回时间段 [start, end) 内触发时机的切片，按服务器本地时区解析 cron
 func (c *CronParser) NextsBetween(cron string, start, end time.Time) ([]time.Time, error) {
+	return c.NextsBetweenIn(cron, time.Local, start, end)
+}
+
+// NextsBetweenIn 按 loc 时区的墙上时间解析 cron，返回时间段 [start, end) 内触发时机的切片
+// 夏令时切换时：被跳过的墙上时间顺延到跳变之后，与已有触发时机重合的不重复触发；
+// 重复出现的墙上时间只在第一次出现时触发
+func (c *CronParser) NextsBetweenIn(cron string, loc *time.Location, start, end time.Time) ([]time.Time, error) {
 	if end.Before(start) {
 		return nil, fmt.Errorf("end can not earlier than start,start: %v,end: %v", start, end)
 	}
@@ -48,14 +60,91 @@ func (c *CronParser) NextsBetween(cron string, start, end time.Time) ([]time.Tim
 		return nil, err
 	}
 
-	var nexts []time.Time
-	for start.Before(end) {
-		next := expr.Next(start)
-		if next.UnixNano() < 0 {
-			return nil, fmt.Errorf("fail to parse time from cron: %s", cron)
+	// 在没有夏令时的 UTC 中推算墙上时间，再映射回 loc 中的真实时刻
+	// 从 start 的前一秒开始推算，保证 start 本身也能命中
+	wall := toWallClock(start.Add(-time.Second), loc)
+	var (
+		nexts []time.Time
+		last  time.Time
+	)
+	for {
+		// 之后不再有触发时机
+		if wall = expr.Next(wall); wall.IsZero() {
+			break
+		}
+
+		next := fromWallClock(wall, loc)
+		if !next.Before(end) {
+			break
+		}
+		if next.Before(start) || (!last.IsZero() && !next.After(last)) {
+			continue
 		}
 		nexts = append(nexts, next)
-		start = next
+		last = next
 	}
 	return nexts, nil
 }
+
+// NextNIn 按 loc 时区的墙上时间解析 cron，返回 start 及之后的至多 n 个触发时机，夏令时处理同 NextsBetweenIn
+func (c *CronParser) NextNIn(cron string, loc *time.Location, start time.Time, n int) ([]time.Time, error) {
+	expr, err := cronexpr.Parse(cron)
+	if err != nil {
+		return nil, err
+	}
+
+	wall := toWallClock(start.Add(-time.Second), loc)
+	var (
+		nexts []time.Time
+		last  time.Time
+	)
+	for len(nexts) < n {
+		if wall = expr.Next(wall); wall.IsZero() {
+			break
+		}
+
+		next := fromWallClock(wall, loc)
+		if next.Before(start) || (!last.IsZero() && !next.After(last)) {
+			continue
+		}
+		nexts = append(nexts, next)
+		last = next
+	}
+	return nexts, nil
+}
+
+// 取 t 在 loc 时区下的墙上时间，以 UTC 表示
+func toWallClock(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+// 将墙上时间映射为 loc 时区下的真实时刻
+// 墙上时间落在夏令时跳变的空档内时，time.Date 会按跳变后的偏移回退到跳变之前，
+// 这里改为按跳变前的偏移换算，得到跳变之后对应的时刻
+func fromWallClock(wall time.Time, loc *time.Location) time.Time {
+	t := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, loc)
+	if t.Hour() == wall.Hour() && t.Minute() == wall.Minute() {
+		return t
+	}
+	_, offset := t.Zone()
+	return wall.Add(-time.Duration(offset) * time.Second).In(loc)
+}
+
+// MinInterval 推算 cron 表达式接下来 n 次触发时机中相邻两次的最小间隔，按墙上时间计算
+// 触发时机不足两次时返回 0，含 H 的表达式需先调用 ResolveHash
+func (c *CronParser) MinInterval(cron string, n int) (time.Duration, error) {
+	expr, err := cronexpr.Parse(cron)
+	if err != nil {
+		return 0, err
+	}
+
+	nexts := expr.NextN(time.Now().UTC(), uint(n))
+	var min time.Duration
+	for i := 1; i < len(nexts); i++ {
+		if gap := nexts[i].Sub(nexts[i-1]); min == 0 || gap < min {
+			min = gap
+		}
+	}
+	return min, nil
+}
@@ -0,0 +1,125 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextsBetweenIn(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	nexts, err := NewCronParser().NextsBetweenIn("0 9 * * *", loc, start, start.Add(48*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 上海 09:00 即 UTC 01:00
+	if len(nexts) != 2 || !nexts[0].Equal(start.Add(time.Hour)) || !nexts[1].Equal(start.Add(25*time.Hour)) {
+		t.Fatalf("unexpected nexts: %v", nexts)
+	}
+}
+
+func TestNextsBetweenInIncludeStart(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	nexts, err := NewCronParser().NextsBetweenIn("0 * * * *", time.UTC, start, start.Add(2*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nexts) != 2 || !nexts[0].Equal(start) || !nexts[1].Equal(start.Add(time.Hour)) {
+		t.Fatalf("unexpected nexts: %v", nexts)
+	}
+}
+
+func TestNextsBetweenInSpringForward(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2024-03-10 02:00 跳到 03:00，02:30 不存在
+	start := time.Date(2024, 3, 10, 0, 0, 0, 0, loc)
+	nexts, err := NewCronParser().NextsBetweenIn("30 2 * * *", loc, start, start.Add(48*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nexts) != 2 {
+		t.Fatalf("expect fire once a day, got: %v", nexts)
+	}
+	if got := nexts[0].In(loc); got.Day() != 10 || got.Hour() != 3 || got.Minute() != 30 {
+		t.Fatalf("skipped wall time should fire after the gap, got: %v", got)
+	}
+
+	nexts, err = NewCronParser().NextsBetweenIn("*/30 * * * *", loc, start, start.Add(6*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 跳变前后都保持每 30 分钟触发一次，不重复也不遗漏
+	if len(nexts) != 12 {
+		t.Fatalf("unexpected nexts: %v", nexts)
+	}
+	for i := 1; i < len(nexts); i++ {
+		if nexts[i].Sub(nexts[i-1]) != 30*time.Minute {
+			t.Fatalf("unexpected gap between %v and %v", nexts[i-1], nexts[i])
+		}
+	}
+}
+
+func TestNextsBetweenInFallBack(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2024-11-03 02:00 回拨到 01:00，01:30 出现两次
+	start := time.Date(2024, 11, 3, 0, 0, 0, 0, loc)
+	nexts, err := NewCronParser().NextsBetweenIn("30 1 * * *", loc, start, start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nexts) != 1 {
+		t.Fatalf("expect fire once, got: %v", nexts)
+	}
+
+	// 从第二次出现的 01:00 开始推算，01:30 已经触发过，不再触发
+	second := time.Date(2024, 11, 3, 6, 0, 0, 0, time.UTC)
+	nexts, err = NewCronParser().NextsBetweenIn("30 1 * * *", loc, second, second.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nexts) != 0 {
+		t.Fatalf("expect no double fire, got: %v", nexts)
+	}
+}
+
+func TestMinInterval(t *testing.T) {
+	cases := []struct {
+		cron string
+		want time.Duration
+	}{
+		{cron: "* * * * * * *", want: time.Second},
+		{cron: "*/5 * * * *", want: 5 * time.Minute},
+		// 每天 00:00 与 00:01 各触发一次，最小间隔取决于相邻的两次
+		{cron: "0,1 0 * * *", want: time.Minute},
+	}
+	for _, c := range cases {
+		got, err := NewCronParser().MinInterval(c.cron, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Fatalf("cron: %s, want: %v, got: %v", c.cron, c.want, got)
+		}
+	}
+}
+
+func TestNextNIn(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	nexts, err := NewCronParser().NextNIn("0 0 0 1 1 * 2024-2025", time.UTC, start, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 年份受限，只剩两次触发时机
+	if len(nexts) != 2 || !nexts[0].Equal(start) || !nexts[1].Equal(start.AddDate(1, 0, 0)) {
+		t.Fatalf("unexpected nexts: %v", nexts)
+	}
+}
@@ -0,0 +1,190 @@
+package crontab
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// 支持的 CronJob apiVersion，batch/v1beta1 在 Kubernetes 1.25 中移除，字段与 batch/v1 一致
+var cronJobAPIVersions = map[string]bool{
+	"batch/v1":      true,
+	"batch/v1beta1": true,
+}
+
+const defaultNamespace = "default"
+
+// cronJob CronJob 清单中转换需要的字段，kind 为 List 时为 items 中的清单
+type cronJob struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name      string            `yaml:"name"`
+		Namespace string            `yaml:"namespace"`
+		Labels    map[string]string `yaml:"labels"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Schedule          string `yaml:"schedule"`
+		TimeZone          string `yaml:"timeZone"`
+		Suspend           bool   `yaml:"suspend"`
+		ConcurrencyPolicy string `yaml:"concurrencyPolicy"`
+		JobTemplate       struct {
+			Spec struct {
+				Template struct {
+					Spec struct {
+						Containers []container `yaml:"containers"`
+					} `yaml:"spec"`
+				} `yaml:"template"`
+			} `yaml:"spec"`
+		} `yaml:"jobTemplate"`
+	} `yaml:"spec"`
+	Items []*cronJob `yaml:"items"`
+}
+
+type container struct {
+	Image   string   `yaml:"image"`
+	Command []string `yaml:"command"`
+	Args    []string `yaml:"args"`
+	Env     []struct {
+		Name  string `yaml:"name"`
+		Value string `yaml:"value"`
+	} `yaml:"env"`
+}
+
+// ParseCronJobs 解析包含一个或多个 yaml 文档的 CronJob 清单，支持 kind 为 List 的清单.
+// 其他 kind 的文档作为无法转换的条目返回；schedule 中 CRON_TZ= 或 TZ= 前缀指定的时区与 spec.timeZone 等效
+func ParseCronJobs(source string, body []byte) ([]*Entry, []*Skipped, error) {
+	var (
+		entries []*Entry
+		skipped []*Skipped
+	)
+
+	dec := yaml.NewDecoder(bytes.NewReader(body))
+	for doc := 1; ; doc++ {
+		var job cronJob
+		err := dec.Decode(&job)
+		if errors.Is(err, io.EOF) {
+			return entries, skipped, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse document %d of %s failed, err: %w", doc, source, err)
+		}
+
+		jobs := []*cronJob{&job}
+		if job.Kind == "List" {
+			jobs = job.Items
+		}
+		for _, item := range jobs {
+			// 空文档，如文件末尾的 ---
+			if item == nil || (item.Kind == "" && item.APIVersion == "") {
+				continue
+			}
+			entry, err := convertCronJob(item)
+			if err != nil {
+				skipped = append(skipped, &Skipped{
+					Source: source,
+					Line:   doc,
+					Name:   item.Metadata.Name,
+					Text:   item.Kind + "/" + item.Metadata.Name,
+					Reason: err.Error(),
+				})
+				continue
+			}
+			entry.Source, entry.Line = source, doc
+			entries = append(entries, entry)
+		}
+	}
+}
+
+func convertCronJob(job *cronJob) (*Entry, error) {
+	if job.Kind != "CronJob" {
+		return nil, fmt.Errorf("kind: %s is not a CronJob", job.Kind)
+	}
+	if !cronJobAPIVersions[job.APIVersion] {
+		return nil, fmt.Errorf("unsupported apiVersion: %s", job.APIVersion)
+	}
+	if job.Metadata.Name == "" {
+		return nil, errors.New("empty metadata.name")
+	}
+
+	entry := Entry{
+		Name:              job.Metadata.Name,
+		Namespace:         job.Metadata.Namespace,
+		Spec:              job.Spec.Schedule,
+		Timezone:          job.Spec.TimeZone,
+		Labels:            job.Metadata.Labels,
+		Suspend:           job.Spec.Suspend,
+		ConcurrencyPolicy: job.Spec.ConcurrencyPolicy,
+	}
+	if entry.Namespace == "" {
+		entry.Namespace = defaultNamespace
+	}
+
+	spec := strings.TrimSpace(job.Spec.Schedule)
+	if tz, rest, ok := cutTZ(spec); ok {
+		if entry.Timezone != "" && entry.Timezone != tz {
+			return nil, fmt.Errorf("timezone: %s in schedule conflicts with spec.timeZone: %s", tz, entry.Timezone)
+		}
+		entry.Timezone, spec = tz, rest
+	}
+	if err := checkTimezone(entry.Timezone); err != nil {
+		return nil, err
+	}
+	expr, err := ConvertSchedule(spec)
+	if err != nil {
+		return nil, err
+	}
+	entry.Cron = expr
+
+	switch entry.ConcurrencyPolicy {
+	case "", "Allow", "Forbid", "Replace":
+	default:
+		return nil, fmt.Errorf("unsupported concurrencyPolicy: %s", entry.ConcurrencyPolicy)
+	}
+
+	if containers := job.Spec.JobTemplate.Spec.Template.Spec.Containers; len(containers) > 0 {
+		c := containers[0]
+		entry.Image = c.Image
+		entry.Command = joinCommand(append(append([]string{}, c.Command...), c.Args...))
+		for _, env := range c.Env {
+			// valueFrom 引用的 Secret、ConfigMap 等无法转换
+			if env.Value == "" {
+				continue
+			}
+			if entry.Env == nil {
+				entry.Env = make(map[string]string, len(c.Env))
+			}
+			entry.Env[env.Name] = env.Value
+		}
+	}
+	return &entry, nil
+}
+
+// cutTZ 拆分 schedule 中 CRON_TZ=Asia/Shanghai 或 TZ=Asia/Shanghai 形式的时区前缀
+func cutTZ(spec string) (string, string, bool) {
+	for _, prefix := range []string{cronTZ + "=", "TZ="} {
+		if !strings.HasPrefix(spec, prefix) {
+			continue
+		}
+		tz, rest, _ := strings.Cut(strings.TrimPrefix(spec, prefix), " ")
+		return tz, strings.TrimSpace(rest), true
+	}
+	return "", spec, false
+}
+
+// joinCommand 按 shell 的写法拼接命令和参数，含空白或引号的参数用单引号括起
+func joinCommand(args []string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, " \t\n'\"\\$`") {
+			quoted = append(quoted, arg)
+			continue
+		}
+		quoted = append(quoted, "'"+strings.ReplaceAll(arg, "'", `'\''`)+"'")
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,260 @@
+// Package crontab 解析 crontab 文件和 Kubernetes CronJob 清单，将调度配置转换为定时器使用的 7 段 cron 表达式，
+// 用于将已有的定时任务迁移为定时器. 无法转换的条目不中断解析，连同原因一起返回
+package crontab
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gotimer_web/pkg/cron"
+)
+
+// crontab 中的预定义表达式，@reboot 没有调度时机，无法转换
+var specials = map[string]string{
+	"@yearly":   "0 0 0 1 1 * *",
+	"@annually": "0 0 0 1 1 * *",
+	"@monthly":  "0 0 0 1 * * *",
+	"@weekly":   "0 0 0 * * 0 *",
+	"@daily":    "0 0 0 * * * *",
+	"@midnight": "0 0 0 * * * *",
+	"@hourly":   "0 0 * * * * *",
+}
+
+var (
+	ErrReboot = errors.New("@reboot runs at system startup, no schedule to convert")
+
+	envRegexp     = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\s*=\s*(.*)$`)
+	nonWordRegexp = regexp.MustCompile(`[^a-z0-9]+`)
+	parser        = cron.NewCronParser()
+)
+
+// crontab 中指定调度时区的环境变量，TZ 只影响命令的环境变量，不影响调度
+const cronTZ = "CRON_TZ"
+
+// 由命令生成名称时保留的最大长度，不含哈希后缀
+const maxNamePrefixLen = 40
+
+// Entry 可以转换为定时器的一条定时任务
+type Entry struct {
+	Source            string            // 来源文件
+	Line              int               // crontab 中的行号；CronJob 为所在文档的序号，均从 1 开始
+	Name              string            // CronJob 的 metadata.name；crontab 由命令和调度生成，条目顺序变化时保持不变
+	Namespace         string            // CronJob 所在的命名空间
+	Spec              string            // 原始的调度配置
+	Cron              string            // 转换后的 7 段 cron 表达式
+	Timezone          string            // IANA 时区，为空时为服务器本地时区
+	User              string            // 系统 crontab 中执行命令的用户
+	Command           string            // 执行的命令，CronJob 为首个容器的 command 和 args
+	Image             string            // CronJob 首个容器的镜像
+	Env               map[string]string // crontab 中在该条目之前声明的环境变量，CronJob 首个容器中直接赋值的环境变量
+	Labels            map[string]string // CronJob 的 metadata.labels
+	Suspend           bool              // CronJob 是否已暂停
+	ConcurrencyPolicy string            // CronJob 的并发策略，Allow、Forbid 或 Replace
+}
+
+// Skipped 无法转换的条目及原因
+type Skipped struct {
+	Source string
+	Line   int
+	Name   string // CronJob 的 metadata.name，crontab 为空
+	Text   string // crontab 中的原文，CronJob 为 kind/name
+	Reason string
+}
+
+func (s *Skipped) String() string {
+	if s.Name != "" {
+		return fmt.Sprintf("%s:%d %s: %s", s.Source, s.Line, s.Name, s.Reason)
+	}
+	return fmt.Sprintf("%s:%d %q: %s", s.Source, s.Line, s.Text, s.Reason)
+}
+
+// ConvertSchedule 将 5 段的 crontab 调度配置或预定义表达式转换为 秒 分 时 日 月 周 年 7 段的 cron 表达式
+func ConvertSchedule(spec string) (string, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@") {
+		if spec == "@reboot" {
+			return "", ErrReboot
+		}
+		if expr, ok := specials[spec]; ok {
+			return expr, nil
+		}
+		return "", fmt.Errorf("unsupported special: %s", spec)
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return "", fmt.Errorf("expect 5 fields of schedule, got: %d", len(fields))
+	}
+	expr := "0 " + strings.Join(fields, " ") + " *"
+	if hasHash(fields) || !parser.IsValidCronExpr(expr) {
+		return "", fmt.Errorf("invalid schedule: %s", spec)
+	}
+	return expr, nil
+}
+
+// hasHash 定时器的 cron 中 H 为哈希取值，crontab 中没有该语法；月份和星期的名称均不以 H 开头
+func hasHash(fields []string) bool {
+	for _, field := range fields {
+		for _, part := range strings.Split(field, ",") {
+			if strings.HasPrefix(strings.ToUpper(part), "H") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseCrontab 解析 crontab 文件，system 为 true 时按 /etc/crontab、/etc/cron.d 的格式解析，命令之前多一列用户名.
+// 支持 NAME=value 形式的环境变量，CRON_TZ 指定其后条目的调度时区
+func ParseCrontab(source string, r io.Reader, system bool) ([]*Entry, []*Skipped, error) {
+	var (
+		entries []*Entry
+		skipped []*Skipped
+		tz      string
+		tzErr   error
+		env     = make(map[string]string)
+	)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		skip := func(reason string) {
+			skipped = append(skipped, &Skipped{Source: source, Line: line, Text: text, Reason: reason})
+		}
+
+		if m := envRegexp.FindStringSubmatch(text); m != nil {
+			value := unquote(m[2])
+			env[m[1]] = value
+			if m[1] == cronTZ {
+				tz, tzErr = value, checkTimezone(value)
+			}
+			continue
+		}
+
+		entry, err := parseLine(text, system)
+		if err != nil {
+			skip(err.Error())
+			continue
+		}
+		if tzErr != nil {
+			skip(tzErr.Error())
+			continue
+		}
+		entry.Source, entry.Line, entry.Timezone = source, line, tz
+		entry.Env = copyEnv(env)
+		entry.Name = commandName(entry)
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return entries, skipped, nil
+}
+
+// parseLine 解析单行定时任务，预定义表达式占 1 列，常规调度配置占 5 列
+func parseLine(text string, system bool) (*Entry, error) {
+	n := 5
+	if strings.HasPrefix(text, "@") {
+		n = 1
+	}
+	if system {
+		n++
+	}
+
+	fields, command := splitFields(text, n)
+	if len(fields) < n || command == "" {
+		if system {
+			return nil, fmt.Errorf("expect schedule, user and command")
+		}
+		return nil, fmt.Errorf("expect schedule and command")
+	}
+
+	entry := Entry{Command: command}
+	if system {
+		entry.User, fields = fields[n-1], fields[:n-1]
+	}
+	entry.Spec = strings.Join(fields, " ")
+	expr, err := ConvertSchedule(entry.Spec)
+	if err != nil {
+		return nil, err
+	}
+	entry.Cron = expr
+	return &entry, nil
+}
+
+// splitFields 拆分出前 n 列，其余部分原样作为命令
+func splitFields(text string, n int) ([]string, string) {
+	fields := make([]string, 0, n)
+	rest := text
+	for len(fields) < n {
+		rest = strings.TrimLeft(rest, " \t")
+		if rest == "" {
+			break
+		}
+		end := strings.IndexAny(rest, " \t")
+		if end < 0 {
+			end = len(rest)
+		}
+		fields = append(fields, rest[:end])
+		rest = rest[end:]
+	}
+	return fields, strings.TrimSpace(rest)
+}
+
+// commandName 由命令的可执行文件名和调度、命令的哈希生成名称，如 backup-sh-1a2b3c4d
+func commandName(entry *Entry) string {
+	program := entry.Command
+	if i := strings.IndexAny(program, " \t;|&><"); i > 0 {
+		program = program[:i]
+	}
+	prefix := strings.Trim(nonWordRegexp.ReplaceAllString(strings.ToLower(filepath.Base(program)), "-"), "-")
+	if len(prefix) > maxNamePrefixLen {
+		prefix = strings.TrimRight(prefix[:maxNamePrefixLen], "-")
+	}
+	if prefix == "" {
+		prefix = "cron"
+	}
+
+	h := fnv.New32a()
+	_, _ = io.WriteString(h, entry.User+"\n"+entry.Spec+"\n"+entry.Command)
+	return fmt.Sprintf("%s-%08x", prefix, h.Sum32())
+}
+
+func checkTimezone(tz string) error {
+	if tz == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return fmt.Errorf("unknown timezone: %s", tz)
+	}
+	return nil
+}
+
+func unquote(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+func copyEnv(env map[string]string) map[string]string {
+	if len(env) == 0 {
+		return nil
+	}
+	cp := make(map[string]string, len(env))
+	for k, v := range env {
+		cp[k] = v
+	}
+	return cp
+}
@@ -0,0 +1,177 @@
+package crontab
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConvertSchedule(t *testing.T) {
+	cases := map[string]string{
+		"*/5 * * * *":       "0 */5 * * * * *",
+		"30 2 * * mon-fri":  "0 30 2 * * mon-fri *",
+		"0 0 1,15 * 7":      "0 0 0 1,15 * 7 *",
+		"  0 12 * jan thu ": "0 0 12 * jan thu *",
+		"@midnight":         "0 0 0 * * * *",
+		"@hourly":           "0 0 * * * * *",
+	}
+	for spec, expect := range cases {
+		expr, err := ConvertSchedule(spec)
+		if err != nil || expr != expect {
+			t.Fatalf("spec: %q, expect: %q, got: %q, err: %v", spec, expect, expr, err)
+		}
+	}
+
+	if _, err := ConvertSchedule("@reboot"); !errors.Is(err, ErrReboot) {
+		t.Fatalf("@reboot, expect ErrReboot, got: %v", err)
+	}
+	for _, spec := range []string{"@every 5m", "* * * *", "0 0 * * * *", "61 * * * *", "H * * * *", "0 H(0-5) * * *"} {
+		if _, err := ConvertSchedule(spec); err == nil {
+			t.Fatalf("spec: %q, expect err", spec)
+		}
+	}
+}
+
+func TestParseCrontab(t *testing.T) {
+	content := `# m h dom mon dow command
+MAILTO=ops@example.com
+*/10 * * * * /opt/jobs/backup.sh --full > /dev/null 2>&1
+@reboot /opt/jobs/warmup.sh
+CRON_TZ="Asia/Shanghai"
+0 9 * * 1-5 curl -s http://example.com/report
+@weekly
+CRON_TZ=Mars/Olympus
+0 0 * * * /opt/jobs/cleanup.sh
+`
+	entries, skipped, err := ParseCrontab("crontab", strings.NewReader(content), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || len(skipped) != 3 {
+		t.Fatalf("expect 2 entries and 3 skipped, got: %d, %d", len(entries), len(skipped))
+	}
+
+	backup := entries[0]
+	if backup.Line != 3 || backup.Cron != "0 */10 * * * * *" || backup.Command != "/opt/jobs/backup.sh --full > /dev/null 2>&1" ||
+		backup.Timezone != "" || backup.Env["MAILTO"] != "ops@example.com" || !strings.HasPrefix(backup.Name, "backup-sh-") {
+		t.Fatalf("unexpected entry: %+v", backup)
+	}
+	if report := entries[1]; report.Timezone != "Asia/Shanghai" || report.Cron != "0 0 9 * * 1-5 *" || !strings.HasPrefix(report.Name, "curl-") {
+		t.Fatalf("unexpected entry: %+v", report)
+	}
+
+	reasons := []string{ErrReboot.Error(), "expect schedule and command", "unknown timezone: Mars/Olympus"}
+	for i, reason := range reasons {
+		if skipped[i].Reason != reason {
+			t.Fatalf("skipped: %d, expect reason: %q, got: %q", i, reason, skipped[i].Reason)
+		}
+	}
+
+	// 名称只与调度和命令相关，与条目所在的行无关
+	again, _, _ := ParseCrontab("crontab", strings.NewReader("\n\n"+strings.Split(content, "\n")[2]), false)
+	if len(again) != 1 || again[0].Name != backup.Name {
+		t.Fatalf("expect stable name: %s, got: %+v", backup.Name, again)
+	}
+
+	system, skipped, err := ParseCrontab("/etc/crontab", strings.NewReader("17 * * * * root cd / && run-parts /etc/cron.hourly\n@daily root\n"), true)
+	if err != nil || len(system) != 1 || len(skipped) != 1 {
+		t.Fatalf("parse system crontab, entries: %v, skipped: %v, err: %v", system, skipped, err)
+	}
+	if system[0].User != "root" || system[0].Spec != "17 * * * *" || system[0].Command != "cd / && run-parts /etc/cron.hourly" {
+		t.Fatalf("unexpected system entry: %+v", system[0])
+	}
+}
+
+func TestParseCronJobs(t *testing.T) {
+	content := `apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: report
+  namespace: billing
+  labels:
+    team: billing
+spec:
+  schedule: "0 8 * * *"
+  timeZone: Asia/Shanghai
+  concurrencyPolicy: Forbid
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: report
+            image: registry.example.com/report:1.2
+            command: ["sh", "-c"]
+            args: ["echo 'done'"]
+            env:
+            - name: MODE
+              value: daily
+            - name: TOKEN
+              valueFrom:
+                secretKeyRef: {name: report, key: token}
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: batch/v1beta1
+  kind: CronJob
+  metadata:
+    name: legacy
+  spec:
+    schedule: CRON_TZ=UTC @hourly
+    suspend: true
+- apiVersion: batch/v1
+  kind: CronJob
+  metadata:
+    name: boot
+  spec:
+    schedule: "@reboot"
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: conflict
+spec:
+  schedule: TZ=UTC 0 * * * *
+  timeZone: Asia/Tokyo
+---
+`
+	entries, skipped, err := ParseCronJobs("jobs.yaml", []byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || len(skipped) != 3 {
+		t.Fatalf("expect 2 entries and 3 skipped, got: %d, %d", len(entries), len(skipped))
+	}
+
+	report := entries[0]
+	if report.Name != "report" || report.Namespace != "billing" || report.Cron != "0 0 8 * * * *" || report.Timezone != "Asia/Shanghai" ||
+		report.ConcurrencyPolicy != "Forbid" || report.Image != "registry.example.com/report:1.2" || report.Labels["team"] != "billing" {
+		t.Fatalf("unexpected entry: %+v", report)
+	}
+	if report.Command != `sh -c 'echo '\''done'\'''` || len(report.Env) != 1 || report.Env["MODE"] != "daily" {
+		t.Fatalf("unexpected container of entry: %+v", report)
+	}
+	if legacy := entries[1]; legacy.Line != 2 || legacy.Namespace != "default" || legacy.Timezone != "UTC" || legacy.Cron != "0 0 * * * * *" || !legacy.Suspend {
+		t.Fatalf("unexpected entry: %+v", legacy)
+	}
+
+	names := []string{"boot", "web", "conflict"}
+	for i, name := range names {
+		if skipped[i].Name != name {
+			t.Fatalf("skipped: %d, expect: %s, got: %s", i, name, skipped[i].Name)
+		}
+	}
+	if !strings.Contains(skipped[1].Reason, "not a CronJob") || !strings.Contains(skipped[2].Reason, "conflicts") {
+		t.Fatalf("unexpected skipped: %v, %v", skipped[1], skipped[2])
+	}
+
+	if _, _, err := ParseCronJobs("bad.yaml", []byte("kind: [")); err == nil {
+		t.Fatal("parse invalid yaml, expect err")
+	}
+}
@@ -0,0 +1,54 @@
+package jsonpath
+
+import "testing"
+
+func TestAssertion(t *testing.T) {
+	body := []byte(`{"code":0,"msg":"ok","data":{"list":[{"id":7,"done":true}],"name":"a.b"},"a":{"x==y":1}}`)
+	tests := []struct {
+		expr string
+		pass bool
+	}{
+		{"$.code == 0", true},
+		{"$.code != 0", false},
+		{"$.msg == 'ok'", true},
+		{`$.msg == "fail"`, false},
+		{"$.data.list[0].id >= 7", true},
+		{"$.data.list[0].id < 7", false},
+		{"$.data.list[0].done", true},
+		{"$.data.list[1].done", false},
+		{`$.data["name"] == "a.b"`, true},
+		{"$.missing == null", false},
+		{"$.code == '0'", false},
+		{`$.msg > "=="`, true},
+		{`$.a["x==y"] == 1`, true},
+		{`$.a['x==y'] != 1`, false},
+		{`$.a["x==y"]`, true},
+	}
+	for _, tt := range tests {
+		a, err := Parse(tt.expr)
+		if err != nil {
+			t.Fatalf("parse %s: %v", tt.expr, err)
+		}
+		if err := a.Evaluate(body); (err == nil) != tt.pass {
+			t.Errorf("%s: expect pass %t, got err %v", tt.expr, tt.pass, err)
+		}
+	}
+}
+
+func TestAssertionNotJSON(t *testing.T) {
+	a, err := Parse("$.code == 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Evaluate([]byte("OK")); err == nil {
+		t.Error("expect error for non-json body")
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, expr := range []string{"", "code == 0", "$.code == ", "$.code > true", "$.a[", "$..a", "$.code == {}", "$.code 0", `$.a["x]`} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("expect parse error for %q", expr)
+		}
+	}
+}
@@ -7,8 +7,6 @@ import (
 	"gotimer_web/common/utils"
 )
 
-const ftimerLockKeyPrefix = "FTIMER_LOCK_PREFIX_"
-
 type DistributeLocker interface {
 	Lock(context.Context, int64) error
 	Unlock(context.Context) error
@@ -88,5 +86,5 @@ func (r *ReentrantDistributeLock) ExpireLock(ctx context.Context, expireSeconds
 }
 
 func (r *ReentrantDistributeLock) getLockKey() string {
-	return ftimerLockKeyPrefix + r.key
+	return utils.GetDistributionLockKey(r.key)
 }
@@ -242,6 +242,13 @@ func NewZAddCommand(args ...interface{}) *Command {
 	}
 }
 
+func NewZRemCommand(args ...interface{}) *Command {
+	return &Command{
+		Name: "ZREM",
+		Args: args,
+	}
+}
+
 func NewSetBitCommand(args ...interface{}) *Command {
 	return &Command{
 		Name: "SETBIT",
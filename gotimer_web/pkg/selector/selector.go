@@ -0,0 +1,212 @@
+package selector
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Operator 标签选择器支持的运算符
+type Operator string
+
+const (
+	Equals       Operator = "="
+	NotEquals    Operator = "!="
+	In           Operator = "in"
+	NotIn        Operator = "notin"
+	Exists       Operator = "exists"
+	DoesNotExist Operator = "!"
+)
+
+const maxLabelLen = 63
+
+var (
+	// 标签键允许带 / 分隔的前缀，如 example.com/team
+	labelKeyRegexp   = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._/-]*[A-Za-z0-9])?$`)
+	labelValueRegexp = regexp.MustCompile(`^([A-Za-z0-9]([A-Za-z0-9._-]*[A-Za-z0-9])?)?$`)
+	setRegexp        = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+)
+
+// Requirement 选择器中的单个条件
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Values   []string
+}
+
+// Selector Kubernetes 风格的标签选择器，多个条件之间为且的关系.
+// 支持 k=v、k==v、k!=v、k in (a,b)、k notin (a,b)、k、!k，
+// 与 Kubernetes 一致，k!=v 和 k notin (...) 同样匹配没有标签 k 的定时器
+type Selector []Requirement
+
+func Parse(expr string) (Selector, error) {
+	terms, err := splitTerms(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	selector := make(Selector, 0, len(terms))
+	for _, term := range terms {
+		r, err := parseRequirement(term)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector: %s, err: %w", expr, err)
+		}
+		selector = append(selector, r)
+	}
+	return selector, nil
+}
+
+// 按括号外的逗号切分条件
+func splitTerms(expr string) ([]string, error) {
+	var terms []string
+	depth, start := 0, 0
+	for i, c := range expr {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("invalid label selector: %s, unbalanced parentheses", expr)
+			}
+		case ',':
+			if depth == 0 {
+				terms = append(terms, expr[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("invalid label selector: %s, unbalanced parentheses", expr)
+	}
+	terms = append(terms, expr[start:])
+
+	if len(terms) == 1 && strings.TrimSpace(terms[0]) == "" {
+		return nil, nil
+	}
+	for i := range terms {
+		terms[i] = strings.TrimSpace(terms[i])
+		if terms[i] == "" {
+			return nil, fmt.Errorf("invalid label selector: %s, empty requirement", expr)
+		}
+	}
+	return terms, nil
+}
+
+func parseRequirement(term string) (Requirement, error) {
+	var r Requirement
+	if m := setRegexp.FindStringSubmatch(term); m != nil {
+		r.Key, r.Operator = m[1], Operator(m[2])
+		for _, value := range strings.Split(m[3], ",") {
+			r.Values = append(r.Values, strings.TrimSpace(value))
+		}
+	} else if strings.HasPrefix(term, "!") {
+		r.Key, r.Operator = strings.TrimSpace(term[1:]), DoesNotExist
+	} else if i := strings.Index(term, "!="); i >= 0 {
+		r.Key, r.Operator, r.Values = strings.TrimSpace(term[:i]), NotEquals, []string{strings.TrimSpace(term[i+2:])}
+	} else if i := strings.Index(term, "=="); i >= 0 {
+		r.Key, r.Operator, r.Values = strings.TrimSpace(term[:i]), Equals, []string{strings.TrimSpace(term[i+2:])}
+	} else if i := strings.Index(term, "="); i >= 0 {
+		r.Key, r.Operator, r.Values = strings.TrimSpace(term[:i]), Equals, []string{strings.TrimSpace(term[i+1:])}
+	} else {
+		r.Key, r.Operator = term, Exists
+	}
+
+	if err := ValidateKey(r.Key); err != nil {
+		return r, err
+	}
+	for _, value := range r.Values {
+		if err := ValidateValue(value); err != nil {
+			return r, err
+		}
+	}
+	return r, nil
+}
+
+// Matches 判断标签集合是否满足选择器的全部条件
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		value, ok := labels[r.Key]
+		switch r.Operator {
+		case Equals, In:
+			if !ok || !contains(r.Values, value) {
+				return false
+			}
+		case NotEquals, NotIn:
+			if ok && contains(r.Values, value) {
+				return false
+			}
+		case Exists:
+			if !ok {
+				return false
+			}
+		case DoesNotExist:
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (s Selector) String() string {
+	terms := make([]string, 0, len(s))
+	for _, r := range s {
+		switch r.Operator {
+		case Equals, NotEquals:
+			terms = append(terms, r.Key+string(r.Operator)+r.Values[0])
+		case In, NotIn:
+			terms = append(terms, fmt.Sprintf("%s %s (%s)", r.Key, r.Operator, strings.Join(r.Values, ",")))
+		case Exists:
+			terms = append(terms, r.Key)
+		case DoesNotExist:
+			terms = append(terms, "!"+r.Key)
+		}
+	}
+	return strings.Join(terms, ",")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateKey 标签键由字母、数字及 . _ - / 组成，首尾为字母或数字，不超过 63 个字符
+func ValidateKey(key string) error {
+	if len(key) > maxLabelLen || !labelKeyRegexp.MatchString(key) {
+		return fmt.Errorf("invalid label key: %q", key)
+	}
+	return nil
+}
+
+// ValidateValue 标签值可以为空，非空时由字母、数字及 . _ - 组成，首尾为字母或数字，不超过 63 个字符
+func ValidateValue(value string) error {
+	if len(value) > maxLabelLen || !labelValueRegexp.MatchString(value) {
+		return fmt.Errorf("invalid label value: %q", value)
+	}
+	return nil
+}
+
+// ValidateLabels 校验标签集合中的全部键值
+func ValidateLabels(labels map[string]string) error {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	// 固定校验顺序，保证错误信息稳定
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := ValidateKey(key); err != nil {
+			return err
+		}
+		if err := ValidateValue(labels[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
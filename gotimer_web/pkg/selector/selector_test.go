@@ -0,0 +1,55 @@
+package selector
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	cases := map[string]string{
+		"":                                      "",
+		"team=billing":                          "team=billing",
+		"team==billing, env!=dev":               "team=billing,env!=dev",
+		"env in (prod, staging),tier":           "env in (prod,staging),tier",
+		"!canary,example.com/owner notin (a,b)": "!canary,example.com/owner notin (a,b)",
+		"team=":                                 "team=",
+	}
+	for expr, expect := range cases {
+		s, err := Parse(expr)
+		if err != nil {
+			t.Fatalf("expr: %q, err: %v", expr, err)
+		}
+		if s.String() != expect {
+			t.Fatalf("expr: %q, expect: %q, got: %q", expr, expect, s.String())
+		}
+	}
+
+	for _, expr := range []string{"team=billing,", "env in (prod", "env in prod)", "-team=a", "team=a b", "=a"} {
+		if _, err := Parse(expr); err == nil {
+			t.Fatalf("expr: %q, expect err", expr)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	labels := map[string]string{"team": "billing", "env": "prod"}
+	cases := map[string]bool{
+		"":                      true,
+		"team=billing":          true,
+		"team=billing,env!=dev": true,
+		"team=billing,env=dev":  false,
+		"env in (prod,staging)": true,
+		"env notin (prod)":      false,
+		"owner!=alice":          true,
+		"owner notin (alice)":   true,
+		"owner":                 false,
+		"!owner":                true,
+		"!team":                 false,
+	}
+	for expr, expect := range cases {
+		s, err := Parse(expr)
+		if err != nil {
+			t.Fatalf("expr: %q, err: %v", expr, err)
+		}
+		if s.Matches(labels) != expect {
+			t.Fatalf("expr: %q, expect: %v", expr, expect)
+		}
+	}
+}
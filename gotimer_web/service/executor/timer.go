@@ -2,6 +2,7 @@ package executor
 
 import (
 	"context"
+	"gorm.io/gorm"
 	"gotimer_web/common/conf"
 	"gotimer_web/common/consts"
 	"gotimer_web/common/model/po"
@@ -16,6 +17,7 @@ import (
 type timerDAO interface {
 	GetTimer(context.Context, ...timerdao.Option) (*po.Timer, error)
 	GetTimers(ctx context.Context, opts ...timerdao.Option) ([]*po.Timer, error)
+	UpdateTimer(ctx context.Context, timer *po.Timer) error
 }
 
 type TimerService struct {
@@ -117,6 +119,11 @@ func (t *TimerService) Stop() {
 	t.stop()
 }
 
+// 一次性定时器的 task 执行完毕后，将定时器置为已结束
+func (t *TimerService) FinishTimer(ctx context.Context, id uint) error {
+	return t.timerDAO.UpdateTimer(ctx, &po.Timer{Model: gorm.Model{ID: id}, Status: consts.Finished.ToInt()})
+}
+
 func (t *TimerService) GetTimer(ctx context.Context, id uint) (*vo.Timer, error) {
 	if vTimer, ok := t.timers[id]; ok {
 		return vTimer, nil
@@ -3,7 +3,9 @@ package executor
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"gorm.io/gorm"
 	"gotimer_web/common/consts"
 	"gotimer_web/common/model/vo"
 	"gotimer_web/common/utils"
@@ -65,6 +67,13 @@ func (w *Worker) Work(ctx context.Context, timerIDUnixKey string) error {
 			return nil
 		}
 	}
+
+	// 定时器更新 cron 后旧的执行时机已被清理，去激活或删除后的执行时机已被取消，均不再执行
+	task, err := w.taskDAO.GetTask(ctx, taskdao.WithTimerID(timerID), taskdao.WithRunTimer(time.UnixMilli(unix)))
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && task.Status == consts.Cancelled.ToInt()) {
+		log.WarnContextf(ctx, "task has been removed or cancelled,timerID: %d,exec_time: %v", timerID, time.UnixMilli(unix))
+		return nil
+	}
 	return w.executeAndPostProcess(ctx, timerID, unix)
 }
 
@@ -82,7 +91,15 @@ func (w *Worker) executeAndPostProcess(ctx context.Context, timerID uint, unix i
 	execTime := time.Now()
 	resp, err := w.execute(ctx, timer)
 
-	return w.postProcess(ctx, resp, err, timer.App, timerID, unix, execTime)
+	if err := w.postProcess(ctx, resp, err, timer.App, timerID, unix, execTime); err != nil {
+		return err
+	}
+
+	// 一次性定时器执行完唯一的 task 后进入终态
+	if timer.Type == consts.OnceTimer {
+		return w.timerService.FinishTimer(ctx, timerID)
+	}
+	return nil
 }
 
 func (w *Worker) execute(ctx context.Context, timer *vo.Timer) (map[string]interface{}, error) {
@@ -115,7 +132,7 @@ func (w *Worker) postProcess(ctx context.Context, resp map[string]interface{}, e
 
 	task, err := w.taskDAO.GetTask(ctx, taskdao.WithTimerID(timeID), taskdao.WithRunTimer(time.UnixMilli(unix)))
 	if err != nil {
-		return fmt.Errorf("get task failed,timerID : %d,runTimer: %d,err :%w", timeID, time.UnixMilli(unix), err)
+		return fmt.Errorf("get task failed,timerID : %d,runTimer: %v,err :%w", timeID, time.UnixMilli(unix), err)
 	}
 
 	respBody, _ := json.Marshal(resp)
@@ -2,9 +2,13 @@ package migrator
 
 import (
 	"context"
+	"fmt"
 	mconf "gotimer_web/common/conf"
 	"gotimer_web/common/consts"
+	"gotimer_web/common/model/po"
+	"gotimer_web/common/model/vo"
 	"gotimer_web/common/utils"
+	calendardao "gotimer_web/dao/calendar"
 	taskdao "gotimer_web/dao/task"
 	timerdao "gotimer_web/dao/timer"
 	"gotimer_web/pkg/cron"
@@ -17,6 +21,7 @@ import (
 type Worker struct {
 	timerDAO          *timerdao.TimerDAO
 	taskDAO           *taskdao.TaskDAO
+	calendarDAO       *calendardao.CalendarDAO
 	taskCache         *taskdao.TaskCache
 	cronParser        *cron.CronParser
 	lockService       *redis.Client
@@ -24,12 +29,13 @@ type Worker struct {
 	pool              pool.WorkerPool
 }
 
-func NewWorker(timerDAO *timerdao.TimerDAO, taskDAO *taskdao.TaskDAO, taskCache *taskdao.TaskCache, lockService *redis.Client,
+func NewWorker(timerDAO *timerdao.TimerDAO, taskDAO *taskdao.TaskDAO, calendarDAO *calendardao.CalendarDAO, taskCache *taskdao.TaskCache, lockService *redis.Client,
 	cronParser *cron.CronParser, appConfigProvider *mconf.MigratorAppConfProvider) *Worker {
 	return &Worker{
 		pool:              pool.NewGoWorkerPool(appConfigProvider.Get().WorkersNum),
 		timerDAO:          timerDAO,
 		taskDAO:           taskDAO,
+		calendarDAO:       calendarDAO,
 		taskCache:         taskCache,
 		lockService:       lockService,
 		cronParser:        cronParser,
@@ -37,9 +43,45 @@ func NewWorker(timerDAO *timerdao.TimerDAO, taskDAO *taskdao.TaskDAO, taskCache
 	}
 }
 
+// 一次性定时器只在 runAt 时刻执行，cron 定时器按所属时区由 cron 表达式推算
+func (w *Worker) getExecuteTimes(timer *po.Timer, start, end time.Time) ([]time.Time, error) {
+	if timer.IsOnce() {
+		return timer.RunAtBetween(start, end), nil
+	}
+
+	loc, err := timer.Location()
+	if err != nil {
+		return nil, err
+	}
+	// cron 中的 H 按定时器 id 哈希为固定取值
+	expr, err := w.cronParser.ResolveHash(timer.Cron, uint64(timer.ID))
+	if err != nil {
+		return nil, err
+	}
+	nexts, err := w.cronParser.NextsBetweenIn(expr, loc, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return timer.JitteredTimes(nexts), nil
+}
+
+// 由执行时机生成 task，命中日历过滤的 task 置为已跳过并记录原因. 日历读取失败时返回错误，不生成未经过滤的 task
+func (w *Worker) batchTasks(ctx context.Context, timer *po.Timer, nexts []time.Time) ([]*po.Task, error) {
+	tasks := timer.BatchTasksFromTimer(nexts)
+	calendars, err := w.calendarDAO.GetTimerCalendars(ctx, timer)
+	if err != nil {
+		return nil, fmt.Errorf("get calendars failed, err: %w", err)
+	}
+	if err := vo.ApplyCalendars(timer, tasks, calendars); err != nil {
+		return nil, fmt.Errorf("apply calendars failed, err: %w", err)
+	}
+	return tasks, nil
+}
+
 // start到end内的task： mysql -> redis
 func (w *Worker) migrateToCache(ctx context.Context, start, end time.Time) error {
-	tasks, err := w.taskDAO.GetTasks(ctx, taskdao.WithStartTime(start), taskdao.WithEndTime(end))
+	// 被日历过滤的 task 不需要触发
+	tasks, err := w.taskDAO.GetTasks(ctx, taskdao.WithStartTime(start), taskdao.WithEndTime(end), taskdao.WithStatus(int32(consts.NotRunned)))
 	if err != nil {
 		log.ErrorContextf(ctx, "migrator batch get tasks failed,err: %v", err)
 		return err
@@ -65,9 +107,19 @@ func (w *Worker) migrate(ctx context.Context) error {
 	start, end := utils.GetStartHour(now.Add(time.Duration(conf.MigrateStepMinutes)*time.Minute)), utils.GetStartHour(now.Add(2*time.Duration(conf.MigrateStepMinutes)*time.Minute))
 
 	for _, timer := range timers {
-		nexts, _ := w.cronParser.NextsBetween(timer.Cron, start, end)
+		nexts, err := w.getExecuteTimes(timer, start, end)
+		if err != nil {
+			log.ErrorContextf(ctx, "migrator get execute times for timer: %d failed,err: %v", timer.ID, err)
+			continue
+		}
+		// 日历过滤失败时跳过该定时器，由下一轮迁移重试，避免节假日等被排除的时机被触发
+		tasks, err := w.batchTasks(ctx, timer, nexts)
+		if err != nil {
+			log.ErrorContextf(ctx, "migrator batch tasks for timer: %d failed,err: %v", timer.ID, err)
+			continue
+		}
 		//将task切片从po -> mysq
-		if err := w.timerDAO.BatchCreateRecords(ctx, timer.BatchTasksFromTimer(nexts)); err != nil {
+		if err := w.timerDAO.BatchCreateRecords(ctx, tasks); err != nil {
 			log.ErrorContextf(ctx, "migrator batch create records for timer: %d failed ,err: %v", timer.ID, err)
 		}
 		time.Sleep(5 * time.Second)
@@ -33,8 +33,9 @@ func (t *TaskService) GetTasksByTime(ctx context.Context, key string, bucket int
 	if tasks, err := t.cache.GetTasksByTime(ctx, key, start.UnixMilli(), end.UnixMilli()); err == nil && len(tasks) > 0 {
 		return vo.NewTasks(tasks), nil
 	}
-	//缓存未命中走db
-	tasks, err := t.dao.GetTasks(ctx, dao.WithStartTime(start), dao.WithEndTime(end), dao.WithStatus(int32(consts.NotRunned.ToInt())))
+	//缓存未命中走db，手动触发的 task 已直接投递，不再重复触发
+	tasks, err := t.dao.GetTasks(ctx, dao.WithStartTime(start), dao.WithEndTime(end), dao.WithStatus(int32(consts.NotRunned.ToInt())),
+		dao.WithSource(int32(consts.ScheduledTrigger.ToInt())))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,98 @@
+package webserver
+
+import (
+	"context"
+	"fmt"
+
+	"gotimer_web/common/model/po"
+	"gotimer_web/common/model/vo"
+	appdao "gotimer_web/dao/app"
+	timerdao "gotimer_web/dao/timer"
+)
+
+type AppService struct {
+	dao      appDAO
+	timerDAO timerDAO
+}
+
+func NewAppService(dao *appdao.AppDAO, timerDAO *timerdao.TimerDAO) *AppService {
+	return &AppService{
+		dao:      dao,
+		timerDAO: timerDAO,
+	}
+}
+
+func (a *AppService) CreateApp(ctx context.Context, app *vo.App) error {
+	pApp, err := app.ToPO()
+	if err != nil {
+		return err
+	}
+	return a.dao.CreateApp(ctx, pApp)
+}
+
+func (a *AppService) UpdateApp(ctx context.Context, app *vo.App) error {
+	pApp, err := app.ToPO()
+	if err != nil {
+		return err
+	}
+	if _, err := a.dao.GetApp(ctx, app.Name); err != nil {
+		return err
+	}
+	return a.dao.UpdateApp(ctx, pApp)
+}
+
+// DeleteApp 应用下仍有定时器时不允许删除
+func (a *AppService) DeleteApp(ctx context.Context, name string) error {
+	cnt, err := a.timerDAO.Count(ctx, timerdao.WithApp(name))
+	if err != nil {
+		return err
+	}
+	if cnt > 0 {
+		return fmt.Errorf("app: %s still has %d timers, delete them first", name, cnt)
+	}
+	return a.dao.DeleteApp(ctx, name)
+}
+
+func (a *AppService) GetApp(ctx context.Context, name string) (*vo.App, error) {
+	app, err := a.dao.GetApp(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return vo.NewApp(app)
+}
+
+func (a *AppService) GetApps(ctx context.Context, req *vo.GetAppsReq) ([]*vo.App, int64, error) {
+	total, err := a.dao.Count(ctx)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	offset, limit := req.Get()
+	if total <= int64(offset) {
+		return []*vo.App{}, total, nil
+	}
+
+	apps, err := a.dao.GetApps(ctx, offset, limit)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	vApps := make([]*vo.App, 0, len(apps))
+	for _, app := range apps {
+		vApp, err := vo.NewApp(app)
+		if err != nil {
+			return nil, -1, err
+		}
+		vApps = append(vApps, vApp)
+	}
+	return vApps, total, nil
+}
+
+type appDAO interface {
+	CreateApp(ctx context.Context, app *po.App) error
+	UpdateApp(ctx context.Context, app *po.App) error
+	DeleteApp(ctx context.Context, name string) error
+	GetApp(ctx context.Context, name string) (*po.App, error)
+	GetApps(ctx context.Context, offset, limit int) ([]*po.App, error)
+	Count(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,55 @@
+package webserver
+
+import (
+	"context"
+
+	"gotimer_web/common/model/po"
+	"gotimer_web/common/model/vo"
+	auditdao "gotimer_web/dao/audit"
+)
+
+type AuditService struct {
+	dao auditDAO
+}
+
+func NewAuditService(dao *auditdao.AuditDAO) *AuditService {
+	return &AuditService{
+		dao: dao,
+	}
+}
+
+func (a *AuditService) GetAuditLogs(ctx context.Context, req *vo.GetAuditLogsReq) ([]*vo.AuditLog, int64, error) {
+	var opts []auditdao.Option
+	if req.App != "" {
+		opts = append(opts, auditdao.WithApp(req.App))
+	}
+	if req.TimerID != 0 {
+		opts = append(opts, auditdao.WithTimerID(req.TimerID))
+	}
+	if !req.Start.IsZero() {
+		opts = append(opts, auditdao.WithStartTime(req.Start))
+	}
+	if !req.End.IsZero() {
+		opts = append(opts, auditdao.WithEndTime(req.End))
+	}
+
+	total, err := a.dao.Count(ctx, opts...)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	offset, limit := req.Get()
+	if total <= int64(offset) {
+		return []*vo.AuditLog{}, total, nil
+	}
+	logs, err := a.dao.GetAuditLogs(ctx, append(opts, auditdao.WithPageLimit(offset, limit), auditdao.WithDesc())...)
+	if err != nil {
+		return nil, -1, err
+	}
+	return vo.NewAuditLogs(logs), total, nil
+}
+
+type auditDAO interface {
+	GetAuditLogs(ctx context.Context, opts ...auditdao.Option) ([]*po.AuditLog, error)
+	Count(ctx context.Context, opts ...auditdao.Option) (int64, error)
+}
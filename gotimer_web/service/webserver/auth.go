@@ -0,0 +1,126 @@
+package webserver
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gotimer_web/common/conf"
+	"gotimer_web/common/consts"
+	"gotimer_web/common/model/po"
+	"gotimer_web/common/model/vo"
+	authdao "gotimer_web/dao/auth"
+)
+
+// 凭证随机字节数，十六进制编码后为 64 个字符
+const apiKeyBytes = 32
+
+var ErrUnauthenticated = errors.New("invalid or missing api key")
+
+type AuthService struct {
+	dao          apiKeyDAO
+	confProvider confProvider
+}
+
+func NewAuthService(dao *authdao.APIKeyDAO, confProvider *conf.WebServerAppConfProvider) *AuthService {
+	return &AuthService{
+		dao:          dao,
+		confProvider: confProvider,
+	}
+}
+
+// Enabled 是否开启接口认证，关闭时所有调用方均视为管理员
+func (a *AuthService) Enabled() bool {
+	return a.confProvider.Get().EnableAuth
+}
+
+// Authenticate 校验凭证，配置中的 adminToken 用于初始化时签发其他凭证
+func (a *AuthService) Authenticate(ctx context.Context, token string) (*vo.Principal, error) {
+	if token == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	if adminToken := a.confProvider.Get().AdminToken; adminToken != "" &&
+		subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1 {
+		return &vo.Principal{Role: consts.AdminRole, KeyName: "admin-token"}, nil
+	}
+
+	key, err := a.dao.GetAPIKeyByHash(ctx, hashAPIKey(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUnauthenticated
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &vo.Principal{App: key.App, Role: consts.Role(key.Role), KeyID: key.ID, KeyName: key.Name}, nil
+}
+
+// CreateAPIKey 签发凭证，明文仅在此时返回一次
+func (a *AuthService) CreateAPIKey(ctx context.Context, req *vo.CreateAPIKeyReq) (*vo.APIKey, error) {
+	if req.Role == 0 {
+		req.Role = consts.AppRole
+	}
+	switch req.Role {
+	case consts.AppRole:
+		if req.App == "" {
+			return nil, errors.New("empty app of app api key")
+		}
+	case consts.AdminRole:
+	default:
+		return nil, fmt.Errorf("invalid role: %d", req.Role)
+	}
+
+	raw := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	plain := hex.EncodeToString(raw)
+
+	key := po.APIKey{
+		App:     req.App,
+		Name:    req.Name,
+		KeyHash: hashAPIKey(plain),
+		Role:    req.Role.ToInt(),
+	}
+	if err := a.dao.CreateAPIKey(ctx, &key); err != nil {
+		return nil, err
+	}
+
+	vKey := vo.NewAPIKey(&key)
+	vKey.Key = plain
+	return vKey, nil
+}
+
+func (a *AuthService) GetAPIKeys(ctx context.Context, app string) ([]*vo.APIKey, error) {
+	keys, err := a.dao.GetAPIKeys(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+
+	vKeys := make([]*vo.APIKey, 0, len(keys))
+	for _, key := range keys {
+		vKeys = append(vKeys, vo.NewAPIKey(key))
+	}
+	return vKeys, nil
+}
+
+func (a *AuthService) DeleteAPIKey(ctx context.Context, id uint) error {
+	return a.dao.DeleteAPIKey(ctx, id)
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+type apiKeyDAO interface {
+	CreateAPIKey(ctx context.Context, key *po.APIKey) error
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*po.APIKey, error)
+	GetAPIKeys(ctx context.Context, app string) ([]*po.APIKey, error)
+	DeleteAPIKey(ctx context.Context, id uint) error
+}
@@ -0,0 +1,165 @@
+package webserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gotimer_web/common/consts"
+	"gotimer_web/common/model/po"
+	"gotimer_web/common/model/vo"
+	"gotimer_web/common/utils"
+	timerdao "gotimer_web/dao/timer"
+	"gotimer_web/pkg/log"
+	"gotimer_web/pkg/redis"
+	"gotimer_web/pkg/selector"
+)
+
+const (
+	// 每批从库中读取的定时器个数
+	bulkBatchSize = 100
+	// 单次批量操作最多处理的定时器个数，超出时需缩小筛选范围
+	maxBulkTimers = 10000
+	// 批量操作锁的过期时间，单位：s. 处理期间每批续期一次，单批的耗时需小于该时间
+	bulkLockSeconds = 60
+)
+
+// BulkTimers 对命中筛选条件的定时器批量执行激活、去激活或删除，按 id 分批处理并逐个返回结果.
+// 批量操作不受单个定时器激活、创建接口的频次限制，同一应用同时只允许一个批量操作
+func (t *TimerService) BulkTimers(ctx context.Context, action consts.BulkAction, req *vo.BulkTimersReq) (*vo.BulkTimersReport, error) {
+	if err := req.Check(); err != nil {
+		return nil, err
+	}
+	opts, err := bulkOptions(req)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := t.dao.Count(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if total > maxBulkTimers {
+		return nil, fmt.Errorf("%d timers matched, more than the max: %d of a bulk operation, please narrow the filter", total, maxBulkTimers)
+	}
+
+	var lock redis.DistributeLocker
+	if !req.DryRun {
+		if lock, err = t.lockBulk(ctx, req.App); err != nil {
+			return nil, err
+		}
+		defer unlockBulk(ctx, req.App, lock)
+	}
+
+	report := vo.NewBulkTimersReport(action, req.DryRun)
+	apps := make(map[string]*po.App)
+	var lastID uint
+	for {
+		timers, err := t.dao.GetTimers(ctx, append(opts, timerdao.WithIDAfter(lastID), timerdao.WithIDAsc(), timerdao.WithPageLimit(0, bulkBatchSize))...)
+		if err != nil {
+			return nil, err
+		}
+		for _, timer := range timers {
+			if !vo.MatchCallbackHost(timer, req.CallbackHost) {
+				continue
+			}
+			report.Add(t.bulkTimer(ctx, action, req.DryRun, apps, timer))
+		}
+		if len(timers) < bulkBatchSize {
+			return report, nil
+		}
+		if err := renewBulk(ctx, lock); err != nil {
+			return nil, err
+		}
+		lastID = timers[len(timers)-1].ID
+	}
+}
+
+// lockBulk 获取应用的批量操作锁，批量操作与声明式同步共用
+func (t *TimerService) lockBulk(ctx context.Context, app string) (redis.DistributeLocker, error) {
+	lock := t.lockService.GetDistributionLock(utils.GetBulkLockKey(app))
+	if err := lock.Lock(ctx, bulkLockSeconds); err != nil {
+		return nil, errors.New("批量操作正在进行中，请稍后再试！")
+	}
+	return lock, nil
+}
+
+// renewBulk 处理完一批后为批量操作锁续期，lock 为 nil 表示试运行未加锁
+func renewBulk(ctx context.Context, lock redis.DistributeLocker) error {
+	if lock == nil {
+		return nil
+	}
+	if err := lock.ExpireLock(ctx, bulkLockSeconds); err != nil {
+		return fmt.Errorf("renew bulk lock failed, err: %w", err)
+	}
+	return nil
+}
+
+func unlockBulk(ctx context.Context, app string, lock redis.DistributeLocker) {
+	if err := lock.Unlock(ctx); err != nil {
+		log.ErrorContextf(ctx, "unlock bulk lock failed, app: %s, err: %v", app, err)
+	}
+}
+
+// bulkTimer 处理单个定时器，失败只记录在结果中，不影响其余定时器
+func (t *TimerService) bulkTimer(ctx context.Context, action consts.BulkAction, dryRun bool, apps map[string]*po.App, timer *po.Timer) *vo.BulkTimerResult {
+	result := vo.BulkTimerResult{
+		ID:   timer.ID,
+		App:  timer.App,
+		Name: timer.Name,
+	}
+	if reason := vo.BulkSkipReason(action, timer); reason != "" {
+		result.Result, result.Reason = consts.BulkSkipped, reason
+		return &result
+	}
+	if dryRun {
+		result.Result = consts.BulkPlanned
+		return &result
+	}
+
+	var err error
+	switch action {
+	case consts.BulkEnable:
+		pApp, ok := apps[timer.App]
+		if !ok {
+			if pApp, err = t.getApp(ctx, timer.App); err == nil {
+				apps[timer.App] = pApp
+			}
+		}
+		if err == nil {
+			err = t.enableTimer(ctx, pApp, timer.App, timer.ID)
+		}
+	case consts.BulkDisable:
+		err = t.unableTimer(ctx, timer.App, timer.ID)
+	case consts.BulkDelete:
+		err = t.deleteTimer(ctx, timer.App, timer.ID)
+	default:
+		err = fmt.Errorf("invalid bulk action: %s", action)
+	}
+
+	if err != nil {
+		result.Result, result.Reason = consts.BulkFailed, err.Error()
+		return &result
+	}
+	result.Result = consts.BulkSucceeded
+	return &result
+}
+
+func bulkOptions(req *vo.BulkTimersReq) ([]timerdao.Option, error) {
+	labelSelector, err := selector.Parse(req.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []timerdao.Option{timerdao.WithLabelSelector(labelSelector)}
+	if req.App != "" {
+		opts = append(opts, timerdao.WithApp(req.App))
+	}
+	if len(req.IDs) > 0 {
+		opts = append(opts, timerdao.WithIDs(req.IDs))
+	}
+	if req.CallbackHost != "" {
+		opts = append(opts, timerdao.WithCallbackHost(req.CallbackHost))
+	}
+	return opts, nil
+}
@@ -0,0 +1,89 @@
+package webserver
+
+import (
+	"context"
+	"fmt"
+
+	"gotimer_web/common/model/po"
+	"gotimer_web/common/model/vo"
+	calendardao "gotimer_web/dao/calendar"
+	timerdao "gotimer_web/dao/timer"
+)
+
+// CalendarService 节假日/停机窗口日历的管理.
+// 日历修改后只影响之后生成的执行时机，已迁移到 mysql 的执行时机在定时器更新或重新激活时才会重新过滤
+type CalendarService struct {
+	dao      calendarManageDAO
+	timerDAO timerDAO
+}
+
+func NewCalendarService(dao *calendardao.CalendarDAO, timerDAO *timerdao.TimerDAO) *CalendarService {
+	return &CalendarService{
+		dao:      dao,
+		timerDAO: timerDAO,
+	}
+}
+
+func (c *CalendarService) CreateCalendar(ctx context.Context, calendar *vo.Calendar) error {
+	pCalendar, err := calendar.ToPO()
+	if err != nil {
+		return err
+	}
+	return c.dao.CreateCalendar(ctx, pCalendar)
+}
+
+func (c *CalendarService) UpdateCalendar(ctx context.Context, calendar *vo.Calendar) error {
+	pCalendar, err := calendar.ToPO()
+	if err != nil {
+		return err
+	}
+	if _, err := c.dao.GetCalendar(ctx, calendar.App, calendar.Name); err != nil {
+		return err
+	}
+	return c.dao.UpdateCalendar(ctx, pCalendar)
+}
+
+// DeleteCalendar 仍被定时器引用的日历不允许删除
+func (c *CalendarService) DeleteCalendar(ctx context.Context, app, name string) error {
+	cnt, err := c.timerDAO.Count(ctx, timerdao.WithApp(app), timerdao.WithCalendar(name))
+	if err != nil {
+		return err
+	}
+	if cnt > 0 {
+		return fmt.Errorf("calendar: %s is still referenced by %d timers", name, cnt)
+	}
+	return c.dao.DeleteCalendar(ctx, app, name)
+}
+
+func (c *CalendarService) GetCalendar(ctx context.Context, app, name string) (*vo.Calendar, error) {
+	calendar, err := c.dao.GetCalendar(ctx, app, name)
+	if err != nil {
+		return nil, err
+	}
+	return vo.NewCalendar(calendar)
+}
+
+func (c *CalendarService) GetCalendars(ctx context.Context, app string) ([]*vo.Calendar, error) {
+	calendars, err := c.dao.GetCalendars(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+
+	vCalendars := make([]*vo.Calendar, 0, len(calendars))
+	for _, calendar := range calendars {
+		vCalendar, err := vo.NewCalendar(calendar)
+		if err != nil {
+			return nil, err
+		}
+		vCalendars = append(vCalendars, vCalendar)
+	}
+	return vCalendars, nil
+}
+
+type calendarManageDAO interface {
+	CreateCalendar(ctx context.Context, calendar *po.Calendar) error
+	UpdateCalendar(ctx context.Context, calendar *po.Calendar) error
+	DeleteCalendar(ctx context.Context, app, name string) error
+	GetCalendar(ctx context.Context, app, name string) (*po.Calendar, error)
+	GetCalendars(ctx context.Context, app string, names ...string) ([]*po.Calendar, error)
+}
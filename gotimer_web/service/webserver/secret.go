@@ -0,0 +1,66 @@
+package webserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"gotimer_web/common/model/po"
+	"gotimer_web/common/model/vo"
+	secretdao "gotimer_web/dao/secret"
+)
+
+// 密钥随机字节数，十六进制编码后为 64 个字符
+const secretBytes = 32
+
+type SecretService struct {
+	dao secretDAO
+}
+
+func NewSecretService(dao *secretdao.SecretDAO) *SecretService {
+	return &SecretService{
+		dao: dao,
+	}
+}
+
+// RotateSecret 为 app 生成新的签名密钥，上一个密钥继续生效直至下一次轮换，返回新密钥明文
+func (s *SecretService) RotateSecret(ctx context.Context, app string) (*vo.AppSecret, error) {
+	raw := make([]byte, secretBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+
+	secret := po.AppSecret{
+		App:    app,
+		Secret: hex.EncodeToString(raw),
+	}
+	if err := s.dao.RotateSecret(ctx, &secret); err != nil {
+		return nil, err
+	}
+	return vo.NewAppSecret(&secret, false), nil
+}
+
+// GetSecrets 获取 app 下生效的密钥，密钥内容脱敏
+func (s *SecretService) GetSecrets(ctx context.Context, app string) ([]*vo.AppSecret, error) {
+	secrets, err := s.dao.GetActiveSecrets(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+
+	vSecrets := make([]*vo.AppSecret, 0, len(secrets))
+	for _, secret := range secrets {
+		vSecrets = append(vSecrets, vo.NewAppSecret(secret, true))
+	}
+	return vSecrets, nil
+}
+
+// RevokeSecret 吊销密钥，用于密钥泄露或轮换完成后提前下线旧密钥
+func (s *SecretService) RevokeSecret(ctx context.Context, app string, id uint) error {
+	return s.dao.DeleteSecret(ctx, app, id)
+}
+
+type secretDAO interface {
+	GetActiveSecrets(ctx context.Context, app string) ([]*po.AppSecret, error)
+	RotateSecret(ctx context.Context, secret *po.AppSecret) error
+	DeleteSecret(ctx context.Context, app string, id uint) error
+}
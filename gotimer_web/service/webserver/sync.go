@@ -0,0 +1,254 @@
+package webserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"gotimer_web/common/consts"
+	"gotimer_web/common/model/po"
+	"gotimer_web/common/model/vo"
+	timerdao "gotimer_web/dao/timer"
+	"gotimer_web/pkg/redis"
+	"gotimer_web/pkg/selector"
+)
+
+// ExportTimers 导出应用下定时器的声明式定义，按名称排序保证多次导出的结果稳定
+func (t *TimerService) ExportTimers(ctx context.Context, req *vo.ExportTimersReq) (*vo.TimerManifest, error) {
+	labelSelector, err := selector.Parse(req.Selector)
+	if err != nil {
+		return nil, err
+	}
+	timers, err := t.getAllTimers(ctx, timerdao.WithApp(req.App), timerdao.WithLabelSelector(labelSelector))
+	if err != nil {
+		return nil, err
+	}
+
+	// id、应用和版本号由服务端维护，不属于声明内容
+	for _, timer := range timers {
+		timer.ID, timer.App, timer.Version = 0, "", 0
+	}
+	manifest := vo.TimerManifest{App: req.App, Timers: timers}
+	sort.Slice(manifest.Timers, func(i, j int) bool {
+		return manifest.Timers[i].Name < manifest.Timers[j].Name
+	})
+	return &manifest, nil
+}
+
+// SyncTimers 以声明式定义为准调整库中的定时器：创建缺失的、更新有差异的，prune 时删除声明中不存在的.
+// 定时器以 (app, name) 标识；声明的 status 为空或为已结束时不调整激活状态。
+// 同步与批量操作共用一把锁，不受单个定时器创建、激活接口的频次限制
+func (t *TimerService) SyncTimers(ctx context.Context, req *vo.SyncTimersReq) (*vo.SyncTimersReport, error) {
+	if err := req.Check(); err != nil {
+		return nil, err
+	}
+	labelSelector, err := selector.Parse(req.Selector)
+	if err != nil {
+		return nil, err
+	}
+	app, err := t.getApp(ctx, req.App)
+	if err != nil {
+		return nil, err
+	}
+
+	var lock redis.DistributeLocker
+	if !req.DryRun {
+		if lock, err = t.lockBulk(ctx, req.App); err != nil {
+			return nil, err
+		}
+		defer unlockBulk(ctx, req.App, lock)
+	}
+
+	// 查询全部定时器，prune 时再按选择器过滤，避免声明中的定时器因标签变化被当作新建
+	timers, err := t.getAllTimers(ctx, timerdao.WithApp(req.App))
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]*vo.Timer, len(timers))
+	for _, timer := range timers {
+		existing[timer.Name] = timer
+	}
+
+	declared := make([]*vo.Timer, len(req.Timers))
+	copy(declared, req.Timers)
+	sort.Slice(declared, func(i, j int) bool {
+		return declared[i].Name < declared[j].Name
+	})
+
+	report := vo.NewSyncTimersReport(req)
+	for i, timer := range declared {
+		if err := renewSyncBulk(ctx, lock, i); err != nil {
+			return nil, err
+		}
+		cur := existing[timer.Name]
+		delete(existing, timer.Name)
+		report.Add(t.syncTimer(ctx, app, req, timer, cur))
+	}
+
+	if !req.Prune {
+		return report, nil
+	}
+	stale := make([]*vo.Timer, 0, len(existing))
+	for _, timer := range existing {
+		if labelSelector.Matches(timer.Labels) {
+			stale = append(stale, timer)
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].Name < stale[j].Name
+	})
+	for i, timer := range stale {
+		if err := renewSyncBulk(ctx, lock, len(declared)+i); err != nil {
+			return nil, err
+		}
+		result := vo.SyncTimerResult{Name: timer.Name, ID: timer.ID, Action: consts.SyncDelete, Result: consts.BulkPlanned}
+		if !req.DryRun {
+			setSyncResult(&result, t.deleteTimer(ctx, req.App, timer.ID))
+		}
+		report.Add(&result)
+	}
+	return report, nil
+}
+
+// syncTimer 比对单个声明与库中的定时器并按需创建或更新，cur 为 nil 表示库中不存在
+func (t *TimerService) syncTimer(ctx context.Context, app *po.App, req *vo.SyncTimersReq, timer, cur *vo.Timer) *vo.SyncTimerResult {
+	result := vo.SyncTimerResult{Name: timer.Name, Action: consts.SyncCreate, Result: consts.BulkPlanned}
+	if cur != nil {
+		result.ID, result.Action = cur.ID, consts.SyncUpdate
+	}
+
+	desired, err := t.normalizeSpec(app, req.App, timer)
+	if err != nil {
+		setSyncResult(&result, err)
+		return &result
+	}
+	plan, err := vo.NewSyncTimerPlan(cur, desired)
+	if err != nil {
+		setSyncResult(&result, err)
+		return &result
+	}
+
+	if cur == nil {
+		if req.DryRun {
+			return &result
+		}
+		id, err := t.createTimer(ctx, syncTimerReq(req.App, 0, timer))
+		if err == nil && plan.Status == consts.Enabled {
+			err = t.enableTimer(ctx, app, req.App, id)
+		}
+		result.ID = id
+		setSyncResult(&result, err)
+		return &result
+	}
+
+	result.Diff = plan.Diff
+	if len(plan.Diff) == 0 {
+		result.Action, result.Result = consts.SyncUnchanged, consts.BulkSkipped
+		return &result
+	}
+	if req.DryRun {
+		return &result
+	}
+
+	if plan.Update {
+		if err := t.updateTimer(ctx, syncTimerReq(req.App, cur.ID, timer), consts.AuditSync); err != nil {
+			setSyncResult(&result, err)
+			return &result
+		}
+	}
+	switch plan.Status {
+	case consts.Enabled:
+		err = t.enableTimer(ctx, app, req.App, cur.ID)
+	case consts.Unabled:
+		err = t.unableTimer(ctx, req.App, cur.ID)
+	}
+	setSyncResult(&result, err)
+	return &result
+}
+
+// normalizeSpec 按创建时的规则补全声明中的默认值，保证与库中的定义可以直接比对
+func (t *TimerService) normalizeSpec(app *po.App, appName string, timer *vo.Timer) (*vo.Timer, error) {
+	req := syncTimerReq(appName, 0, timer)
+	applyAppDefaults(app, req)
+	pTimer, err := req.ToPO()
+	if err != nil {
+		return nil, err
+	}
+	if err := t.checkSchedule(pTimer); err != nil {
+		return nil, err
+	}
+	spec, err := vo.NewTimerSpec(pTimer, req.Labels)
+	if err != nil {
+		return nil, err
+	}
+	spec.Status = timer.Status
+	return spec, nil
+}
+
+// syncTimerReq 复制声明作为创建或更新的请求，避免补全默认值时修改声明本身.
+// 声明中没有标签表示清空标签，激活状态由同步单独调整
+func syncTimerReq(app string, id uint, timer *vo.Timer) *vo.Timer {
+	req := *timer
+	req.ID, req.App, req.Status, req.Version = id, app, 0, 0
+	if timer.NotifyHTTPParam != nil {
+		param := *timer.NotifyHTTPParam
+		param.Header = make(map[string]string, len(timer.NotifyHTTPParam.Header))
+		for k, v := range timer.NotifyHTTPParam.Header {
+			param.Header[k] = v
+		}
+		req.NotifyHTTPParam = &param
+	}
+	req.Labels = make(map[string]string, len(timer.Labels))
+	for k, v := range timer.Labels {
+		req.Labels[k] = v
+	}
+	return &req
+}
+
+// renewSyncBulk 处理第 i 个定时器之前，每处理完 bulkBatchSize 个为批量操作锁续期一次. 声明中的和待删除的定时器连续计数
+func renewSyncBulk(ctx context.Context, lock redis.DistributeLocker, i int) error {
+	if i == 0 || i%bulkBatchSize != 0 {
+		return nil
+	}
+	return renewBulk(ctx, lock)
+}
+
+func setSyncResult(result *vo.SyncTimerResult, err error) {
+	if err != nil {
+		result.Result, result.Reason = consts.BulkFailed, err.Error()
+		return
+	}
+	result.Result = consts.BulkSucceeded
+}
+
+// getAllTimers 按 id 分批读取命中条件的全部定时器并补充标签
+func (t *TimerService) getAllTimers(ctx context.Context, opts ...timerdao.Option) ([]*vo.Timer, error) {
+	total, err := t.dao.Count(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if total > maxBulkTimers {
+		return nil, fmt.Errorf("%d timers matched, more than the max: %d, please narrow the filter", total, maxBulkTimers)
+	}
+
+	var vTimers []*vo.Timer
+	var lastID uint
+	for {
+		timers, err := t.dao.GetTimers(ctx, append(opts, timerdao.WithIDAfter(lastID), timerdao.WithIDAsc(), timerdao.WithPageLimit(0, bulkBatchSize))...)
+		if err != nil {
+			return nil, err
+		}
+		batch, err := vo.NewTimers(timers)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.withLabels(ctx, batch); err != nil {
+			return nil, err
+		}
+		vTimers = append(vTimers, batch...)
+		if len(timers) < bulkBatchSize {
+			return vTimers, nil
+		}
+		lastID = timers[len(timers)-1].ID
+	}
+}
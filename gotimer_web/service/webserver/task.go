@@ -28,11 +28,20 @@ func (t *TaskService) GetTask(ctx context.Context, id uint) (*vo.Task, error) {
 
 // 输入一个包含timerID的结构体，结合分页逻辑,返回这个timer对应的task
 func (t *TaskService) GetTasks(ctx context.Context, req *vo.GetTasksReq) ([]*vo.Task, int64, error) {
-	total, err := t.dao.Count(ctx, dao.WithTimerID(req.TimerID), dao.WithStatuses([]int32{
+	opts := []dao.Option{dao.WithTimerID(req.TimerID)}
+	if req.App != "" {
+		opts = append(opts, dao.WithApp(req.App))
+	}
+
+	total, err := t.dao.Count(ctx, append(opts, dao.WithStatuses([]int32{
 		int32(consts.Running),
 		int32(consts.Successed),
 		int32(consts.Failed),
-	}))
+		int32(consts.Cancelled),
+		int32(consts.Retrying),
+		int32(consts.Missed),
+		int32(consts.Skipped),
+	}))...)
 	if err != nil {
 		return nil, -1, err
 	}
@@ -41,14 +50,52 @@ func (t *TaskService) GetTasks(ctx context.Context, req *vo.GetTasksReq) ([]*vo.
 	if total <= int64(offset) {
 		return []*vo.Task{}, total, nil
 	}
-	tasks, err := t.dao.GetTasks(ctx, dao.WithTimerID(req.TimerID), dao.WithPageLimit(offset, limit), dao.WithStatuses([]int32{
+	tasks, err := t.dao.GetTasks(ctx, append(opts, dao.WithPageLimit(offset, limit), dao.WithStatuses([]int32{
 		int32(consts.Running),
 		int32(consts.Successed),
 		int32(consts.Failed),
-	}), dao.WithDesc())
+		int32(consts.Cancelled),
+		int32(consts.Retrying),
+		int32(consts.Missed),
+		int32(consts.Skipped),
+	}), dao.WithDesc())...)
 	if err != nil {
 		return nil, -1, err
 	}
 
-	return vo.NewTasks(tasks), total, nil
+	vTasks := vo.NewTasks(tasks)
+	if req.WithFullOutput {
+		if err := t.fillFullOutput(ctx, vTasks); err != nil {
+			return nil, -1, err
+		}
+	}
+	return vTasks, total, nil
+}
+
+// fillFullOutput 为执行结果被截断的任务补充完整响应体
+func (t *TaskService) fillFullOutput(ctx context.Context, tasks []*vo.Task) error {
+	var taskIDs []uint
+	for _, task := range tasks {
+		if task.Truncated {
+			taskIDs = append(taskIDs, task.ID)
+		}
+	}
+	if len(taskIDs) == 0 {
+		return nil
+	}
+
+	outputs, err := t.dao.GetTaskOutputs(ctx, taskIDs)
+	if err != nil {
+		return err
+	}
+	bodies := make(map[uint]string, len(outputs))
+	for _, output := range outputs {
+		bodies[output.TaskID] = output.Body
+	}
+	for _, task := range tasks {
+		if task.Truncated {
+			task.FullOutput = bodies[task.ID]
+		}
+	}
+	return nil
 }
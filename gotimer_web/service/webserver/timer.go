@@ -2,44 +2,71 @@ package webserver
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sort"
 	"time"
 
+	"gorm.io/gorm"
 	"gotimer_web/common/conf"
 	"gotimer_web/common/consts"
 	"gotimer_web/common/model/po"
 	"gotimer_web/common/model/vo"
 	"gotimer_web/common/utils"
+	appdao "gotimer_web/dao/app"
+	calendardao "gotimer_web/dao/calendar"
 	taskdao "gotimer_web/dao/task"
 	timerdao "gotimer_web/dao/timer"
+	versiondao "gotimer_web/dao/version"
+	"gotimer_web/mq"
 	"gotimer_web/pkg/cron"
 	"gotimer_web/pkg/log"
 	"gotimer_web/pkg/mysql"
 	"gotimer_web/pkg/redis"
+	"gotimer_web/pkg/selector"
 )
 
-const defaultEnableGapSeconds = 3
+const (
+	defaultEnableGapSeconds = 3
+	// 同一定时器每秒至多手动触发一次
+	defaultRunGapSeconds = 1
+	// 手动触发的 task 执行时间冲突时最多尝试的次数
+	maxManualRunAttempts = 3
+	// 推算 cron 最小触发间隔时采样的触发次数
+	cronIntervalSamples = 1000
+	// cron 试算默认及最多返回的触发时机个数
+	defaultPreviewN = 10
+	maxPreviewN     = 100
+)
 
 type TimerService struct {
 	dao                 timerDAO
+	appDAO              appDAO
+	calendarDAO         calendarDAO
+	versionDAO          versionDAO
 	confProvider        confProvider
 	migrateConfProvider *conf.MigratorAppConfProvider
 	cronParser          cronParser
 	taskCache           taskCache
 	lockService         *redis.Client
+	producer            triggerProducer
 }
 
-func NewTimerService(dao *timerdao.TimerDAO, taskCache *taskdao.TaskCache, lockService *redis.Client,
-	confProvider *conf.WebServerAppConfProvider, migrateConfProvider *conf.MigratorAppConfProvider, parser *cron.CronParser) *TimerService {
+func NewTimerService(dao *timerdao.TimerDAO, appDAO *appdao.AppDAO, calendarDAO *calendardao.CalendarDAO, versionDAO *versiondao.VersionDAO, taskCache *taskdao.TaskCache, lockService *redis.Client,
+	confProvider *conf.WebServerAppConfProvider, migrateConfProvider *conf.MigratorAppConfProvider, parser *cron.CronParser,
+	producer *mq.TriggerProducer) *TimerService {
 	return &TimerService{
 		dao:                 dao,
+		appDAO:              appDAO,
+		calendarDAO:         calendarDAO,
+		versionDAO:          versionDAO,
 		confProvider:        confProvider,
 		migrateConfProvider: migrateConfProvider,
 		taskCache:           taskCache,
 		cronParser:          parser,
 		lockService:         lockService,
+		producer:            producer,
 	}
 }
 
@@ -49,16 +76,60 @@ func (t *TimerService) CreateTimer(ctx context.Context, timer *vo.Timer) (uint,
 	if err := lock.Lock(ctx, defaultEnableGapSeconds); err != nil {
 		return 0, errors.New("创建/删除操作过于频繁，请稍后再试！")
 	}
-	// 校验 cron 表达式
-	if !t.cronParser.IsValidCronExpr(timer.Cron) {
-		return 0, fmt.Errorf("非法的 cron 表达式: %s", timer.Cron)
+	return t.createTimer(ctx, timer)
+}
+
+// createTimer 不加频控锁的创建逻辑，供声明式同步等批量操作复用
+func (t *TimerService) createTimer(ctx context.Context, timer *vo.Timer) (uint, error) {
+	app, err := t.getApp(ctx, timer.App)
+	if err != nil {
+		return 0, err
 	}
+	applyAppDefaults(app, timer)
 
 	pTimer, err := timer.ToPO()
 	if err != nil {
 		return 0, err
 	}
-	return t.dao.CreateTimer(ctx, pTimer)
+	// 新建的定时器总是未激活，激活只能通过 enableTimer，保证激活配额校验和 task 生成
+	pTimer.Status = consts.Unabled.ToInt()
+	if err := t.checkSchedule(pTimer); err != nil {
+		return 0, err
+	}
+	if err := t.checkCronInterval(app, pTimer); err != nil {
+		return 0, err
+	}
+	if err := t.checkCalendars(ctx, pTimer); err != nil {
+		return 0, err
+	}
+
+	pTimer.Version = 1
+	err = t.dao.Transaction(ctx, func(ctx context.Context, dao *timerdao.TimerDAO) error {
+		// 锁定应用记录，同一应用的计数与插入串行执行，并发创建不会超出配额
+		if app != nil && app.MaxTimers > 0 {
+			if err := dao.LockApp(ctx, app.Name); err != nil {
+				return err
+			}
+			cnt, err := dao.Count(ctx, timerdao.WithApp(app.Name))
+			if err != nil {
+				return err
+			}
+			if cnt >= int64(app.MaxTimers) {
+				return fmt.Errorf("app: %s has reached the max timers quota: %d", app.Name, app.MaxTimers)
+			}
+		}
+		if _, err := dao.CreateTimer(ctx, pTimer); err != nil {
+			return err
+		}
+		if err := t.createVersion(ctx, dao, pTimer, vo.PrincipalFromContext(ctx).Actor()); err != nil {
+			return err
+		}
+		if err := dao.ReplaceTimerLabels(ctx, pTimer, timer.Labels); err != nil {
+			return err
+		}
+		return t.audit(ctx, dao, consts.AuditCreate, nil, pTimer)
+	})
+	return pTimer.ID, err
 }
 
 func (t *TimerService) DeleteTimer(ctx context.Context, app string, id uint) error {
@@ -66,15 +137,129 @@ func (t *TimerService) DeleteTimer(ctx context.Context, app string, id uint) err
 	if err := lock.Lock(ctx, defaultEnableGapSeconds); err != nil {
 		return errors.New("创建/删除操作过于频繁，请稍后再试！")
 	}
-	return t.dao.DeleteTimer(ctx, id)
+	return t.deleteTimer(ctx, app, id)
 }
 
+func (t *TimerService) deleteTimer(ctx context.Context, app string, id uint) error {
+	do := func(ctx context.Context, dao *timerdao.TimerDAO, timer *po.Timer) error {
+		if timer.App != app {
+			return fmt.Errorf("timer not belongs to app: %s, timer id: %d", app, id)
+		}
+
+		// 取消尚未执行的 task
+		if err := t.cancelTasks(ctx, dao, timer); err != nil {
+			return err
+		}
+		if err := dao.DeleteTimer(ctx, id); err != nil {
+			return err
+		}
+		if err := dao.ReplaceTimerLabels(ctx, timer, nil); err != nil {
+			return err
+		}
+		return t.audit(ctx, dao, consts.AuditDelete, timer, nil)
+	}
+
+	return t.dao.DoWithLock(ctx, id, do)
+}
+
+// UpdateTimer 以请求为完整的定义覆盖定时器，未指定的时区、重试策略、日历等可选配置被清空；标签为 nil 时保持不变
 func (t *TimerService) UpdateTimer(ctx context.Context, timer *vo.Timer) error {
+	return t.updateTimer(ctx, timer, consts.AuditUpdate)
+}
+
+// RollbackTimer 将定时器定义回滚到指定版本，回滚本身生成新的版本，尚未执行的执行时机按更新的方式重新生成
+func (t *TimerService) RollbackTimer(ctx context.Context, req *vo.RollbackTimerReq) error {
+	cur, err := t.dao.GetTimer(ctx, timerdao.WithID(req.ID))
+	if err != nil {
+		return err
+	}
+	if cur.App != req.App {
+		return fmt.Errorf("timer not belongs to app: %s, timer id: %d", req.App, req.ID)
+	}
+	version, err := t.dao.GetTimerVersion(ctx, req.ID, req.Version)
+	if err != nil {
+		return fmt.Errorf("get version: %d of timer: %d failed, err: %w", req.Version, req.ID, err)
+	}
+
+	var timer vo.Timer
+	if err := json.Unmarshal([]byte(version.Snapshot), &timer); err != nil {
+		return err
+	}
+	timer.ID, timer.App, timer.Name = cur.ID, cur.App, cur.Name
+	return t.updateTimer(ctx, &timer, consts.AuditRollback)
+}
+
+func (t *TimerService) updateTimer(ctx context.Context, timer *vo.Timer, action consts.AuditAction) error {
+	app, err := t.getApp(ctx, timer.App)
+	if err != nil {
+		return err
+	}
+	applyAppDefaults(app, timer)
+
 	pTimer, err := timer.ToPO()
 	if err != nil {
 		return err
 	}
-	return t.dao.UpdateTimer(ctx, pTimer)
+	if err := t.checkSchedule(pTimer); err != nil {
+		return err
+	}
+	if err := t.checkCronInterval(app, pTimer); err != nil {
+		return err
+	}
+	if err := t.checkCalendars(ctx, pTimer); err != nil {
+		return err
+	}
+
+	do := func(ctx context.Context, dao *timerdao.TimerDAO, old *po.Timer) error {
+		if old.App != pTimer.App {
+			return fmt.Errorf("timer not belongs to app: %s, timer id: %d", pTimer.App, old.ID)
+		}
+
+		// 状态只允许通过激活/去激活接口修改，已结束的一次性定时器修改执行时机后回到未激活态
+		pTimer.Status = old.Status
+		if old.Status == consts.Finished.ToInt() && vo.ScheduleChanged(old, pTimer) {
+			pTimer.Status = consts.Unabled.ToInt()
+		}
+		// 更新以请求为完整的定义，零值字段同样写入，未指定的时区、重试策略等配置被清空. 版本号由 saveVersion 维护
+		pTimer.Version = old.Version
+		if err := dao.ReplaceTimer(ctx, pTimer); err != nil {
+			return err
+		}
+		// 以库中的最新值记录版本和审计日志
+		cur, err := dao.GetTimer(ctx, timerdao.WithID(old.ID))
+		if err != nil {
+			return err
+		}
+		if err := t.saveVersion(ctx, dao, old, cur); err != nil {
+			return err
+		}
+		// 标签不属于版本内容，为 nil 时保持不变
+		if timer.Labels != nil {
+			if err := dao.ReplaceTimerLabels(ctx, cur, timer.Labels); err != nil {
+				return err
+			}
+		}
+		if err := t.audit(ctx, dao, action, old, cur); err != nil {
+			return err
+		}
+
+		if !vo.ScheduleChanged(old, cur) {
+			return nil
+		}
+
+		// 执行时机发生变化，旧的执行时机全部作废
+		if err := t.purgeTasks(ctx, dao, old); err != nil {
+			return err
+		}
+
+		// 未激活的定时器等到激活时再生成执行时机
+		if cur.Status != consts.Enabled.ToInt() {
+			return nil
+		}
+		return t.createTasks(ctx, dao, cur)
+	}
+
+	return t.dao.DoWithLock(ctx, timer.ID, do)
 }
 
 func (t *TimerService) GetTimer(ctx context.Context, id uint) (*vo.Timer, error) {
@@ -83,7 +268,14 @@ func (t *TimerService) GetTimer(ctx context.Context, id uint) (*vo.Timer, error)
 		return nil, err
 	}
 
-	return vo.NewTimer(pTimer)
+	vTimer, err := vo.NewTimer(pTimer)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.withLabels(ctx, []*vo.Timer{vTimer}); err != nil {
+		return nil, err
+	}
+	return vTimer, nil
 }
 
 func (t *TimerService) EnableTimer(ctx context.Context, app string, id uint) error {
@@ -93,65 +285,305 @@ func (t *TimerService) EnableTimer(ctx context.Context, app string, id uint) err
 		return errors.New("激活/去激活操作过于频繁，请稍后再试！")
 	}
 
+	pApp, err := t.getApp(ctx, app)
+	if err != nil {
+		return err
+	}
+	return t.enableTimer(ctx, pApp, app, id)
+}
+
+// enableTimer 激活定时器，pApp 为定时器所属应用的注册信息，未注册时为 nil
+func (t *TimerService) enableTimer(ctx context.Context, pApp *po.App, app string, id uint) error {
 	do := func(ctx context.Context, dao *timerdao.TimerDAO, timer *po.Timer) error {
+		if timer.App != app {
+			return fmt.Errorf("timer not belongs to app: %s, timer id: %d", app, id)
+		}
 		// 状态校验
 		if timer.Status != consts.Unabled.ToInt() {
 			return fmt.Errorf("not unabled status, enable failed, timer id: %d", id)
 		}
-
-		// 取得批量的执行时机
-		// end 为下两个切片的右边界
-		start := time.Now()
-		end := utils.GetForwardTwoMigrateStepEnd(start, 2*time.Duration(t.migrateConfProvider.Get().MigrateStepMinutes)*time.Minute)
-		executeTimes, err := t.cronParser.NextsBefore(timer.Cron, end)
-		if err != nil {
-			log.ErrorContextf(ctx, "get executeTimes failed, err: %v", err)
-			return err
+		if timer.IsOnce() && !timer.RunAt.After(time.Now()) {
+			return fmt.Errorf("runAt has passed, enable failed, timer id: %d", id)
 		}
 
-		// 执行时机加入数据库
-		tasks := timer.BatchTasksFromTimer(executeTimes)
-		// 基于 timer_id + run_timer 唯一键，保证任务不被重复插入
-		if err := dao.BatchCreateRecords(ctx, tasks); err != nil && !mysql.IsDuplicateEntryErr(err) {
+		// 配额可能在定时器创建后被调小，激活时重新校验
+		if err := t.checkCronInterval(pApp, timer); err != nil {
 			return err
 		}
+		if pApp != nil && pApp.MaxEnabledTimers > 0 {
+			cnt, err := dao.Count(ctx, timerdao.WithApp(app), timerdao.WithStatus(int32(consts.Enabled)))
+			if err != nil {
+				return err
+			}
+			if cnt >= int64(pApp.MaxEnabledTimers) {
+				return fmt.Errorf("app: %s has reached the max enabled timers quota: %d", app, pApp.MaxEnabledTimers)
+			}
+		}
 
-		// 执行时机加入 redis 跳表
-		if err := t.taskCache.BatchCreateTasks(ctx, tasks, start, end); err != nil {
+		if err := t.createTasks(ctx, dao, timer); err != nil {
 			return err
 		}
 
 		// 修改 timer 状态为激活态
+		before := *timer
 		timer.Status = consts.Enabled.ToInt()
-		return dao.UpdateTimer(ctx, timer)
+		if err := dao.UpdateTimer(ctx, timer); err != nil {
+			return err
+		}
+		return t.audit(ctx, dao, consts.AuditEnable, &before, timer)
 	}
 
 	return t.dao.DoWithLock(ctx, id, do)
 }
 
+// RunTimer 立即触发一次定时器，生成手动触发的 task 并直接投递到 trigger-topic，返回 task id
+func (t *TimerService) RunTimer(ctx context.Context, app string, id uint) (uint, error) {
+	lock := t.lockService.GetDistributionLock(utils.GetRunLockKey(id))
+	if err := lock.Lock(ctx, defaultRunGapSeconds); err != nil {
+		return 0, errors.New("手动触发操作过于频繁，请稍后再试！")
+	}
+
+	timer, err := t.dao.GetTimer(ctx, timerdao.WithID(id))
+	if err != nil {
+		return 0, err
+	}
+	if timer.App != app {
+		return 0, fmt.Errorf("timer not belongs to app: %s, timer id: %d", app, id)
+	}
+
+	task := po.Task{
+		App:      timer.App,
+		TimerID:  timer.ID,
+		RunTimer: manualRunTimer(time.Now()),
+		Status:   consts.NotRunned.ToInt(),
+		Source:   consts.ManualTrigger.ToInt(),
+		// 手动触发同样记录产生该 task 的版本
+		TimerVersion: timer.Version,
+	}
+	for attempt := 1; ; attempt++ {
+		err := t.dao.BatchCreateRecords(ctx, []*po.Task{&task})
+		if err == nil {
+			break
+		}
+		if !mysql.IsDuplicateEntryErr(err) || attempt >= maxManualRunAttempts {
+			return 0, fmt.Errorf("create manual task failed, timer id: %d, err: %w", id, err)
+		}
+		// 执行时间已被占用，顺延 1ms 重试
+		task.ID, task.RunTimer = 0, manualRunTimer(task.RunTimer.Add(time.Millisecond))
+	}
+
+	if err := t.producer.Send(ctx, utils.UnionTimerIDUnix(task.TimerID, task.RunTimer.UnixMilli())); err != nil {
+		if err := t.dao.BatchDeleteRecords(ctx, []*po.Task{&task}); err != nil {
+			log.ErrorContextf(ctx, "delete unsent manual task failed, task id: %d, err: %v", task.ID, err)
+		}
+		return 0, fmt.Errorf("send manual task failed, timer id: %d, err: %w", id, err)
+	}
+	return task.ID, nil
+}
+
+// manualRunTimer 手动触发的 task 的执行时间，精确到毫秒且毫秒部分不为 0.
+// 调度产生的 task 执行时间均为整秒，二者不会占用同一个 (timer_id, run_timer) 唯一键和 timerID_unix 去重 key
+func manualRunTimer(now time.Time) time.Time {
+	runTimer := now.Truncate(time.Millisecond)
+	if runTimer.Equal(runTimer.Truncate(time.Second)) {
+		runTimer = runTimer.Add(time.Millisecond)
+	}
+	return runTimer
+}
+
 func (t *TimerService) UnableTimer(ctx context.Context, app string, id uint) error {
 	// 限制激活和去激活频次
 	lock := t.lockService.GetDistributionLock(utils.GetEnableLockKey(app))
 	if err := lock.Lock(ctx, defaultEnableGapSeconds); err != nil {
 		return errors.New("激活/去激活操作过于频繁，请稍后再试！")
 	}
+	return t.unableTimer(ctx, app, id)
+}
 
+func (t *TimerService) unableTimer(ctx context.Context, app string, id uint) error {
 	do := func(ctx context.Context, dao *timerdao.TimerDAO, timer *po.Timer) error {
+		if timer.App != app {
+			return fmt.Errorf("timer not belongs to app: %s, timer id: %d", app, id)
+		}
 		// 状态校验
 		if timer.Status != consts.Enabled.ToInt() {
 			return fmt.Errorf("not enabled status, unable failed, timer id: %d", id)
 		}
 
-		// 修改 timer 状态为激活态
+		// 取消尚未执行的 task
+		if err := t.cancelTasks(ctx, dao, timer); err != nil {
+			return err
+		}
+
+		// 修改 timer 状态为去激活态
+		before := *timer
 		timer.Status = consts.Unabled.ToInt()
-		return dao.UpdateTimer(ctx, timer)
+		if err := dao.UpdateTimer(ctx, timer); err != nil {
+			return err
+		}
+		return t.audit(ctx, dao, consts.AuditDisable, &before, timer)
 	}
 
 	return t.dao.DoWithLock(ctx, id, do)
 }
 
+// 取得从当前时刻到下两个迁移切片右边界之间的执行时机，写入 mysql 和 redis
+func (t *TimerService) createTasks(ctx context.Context, dao *timerdao.TimerDAO, timer *po.Timer) error {
+	// end 为下两个切片的右边界
+	start := time.Now()
+	end := utils.GetForwardTwoMigrateStepEnd(start, 2*time.Duration(t.migrateConfProvider.Get().MigrateStepMinutes)*time.Minute)
+	executeTimes, err := t.getExecuteTimes(timer, start, end)
+	if err != nil {
+		log.ErrorContextf(ctx, "get executeTimes failed, err: %v", err)
+		return err
+	}
+
+	// 去激活时被取消的 task 以及被日历过滤的 task 占用 timer_id + run_timer 唯一键，同一执行时机沿用原记录
+	stale := make(map[int64]*po.Task)
+	for _, status := range []consts.TaskStatus{consts.Cancelled, consts.Skipped} {
+		records, err := dao.GetRecordsAfter(ctx, timer.ID, start, status)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			stale[record.RunTimer.UnixMilli()] = record
+		}
+	}
+
+	// 执行时机加入数据库，命中日历过滤的置为已跳过并记录原因
+	tasks := timer.BatchTasksFromTimer(executeTimes)
+	calendars, err := t.calendarDAO.GetTimerCalendars(ctx, timer)
+	if err != nil {
+		return err
+	}
+	if err := vo.ApplyCalendars(timer, tasks, calendars); err != nil {
+		return err
+	}
+	fresh := make([]*po.Task, 0, len(tasks))
+	for _, task := range tasks {
+		record, ok := stale[task.RunTimer.UnixMilli()]
+		if !ok {
+			fresh = append(fresh, task)
+			continue
+		}
+		task.ID = record.ID
+		if err := dao.ReviveRecord(ctx, task); err != nil {
+			return err
+		}
+	}
+	// 基于 timer_id + run_timer 唯一键，保证任务不被重复插入
+	if len(fresh) > 0 {
+		if err := dao.BatchCreateRecords(ctx, fresh); err != nil && !mysql.IsDuplicateEntryErr(err) {
+			return err
+		}
+	}
+
+	// 未被过滤的执行时机加入 redis 跳表
+	pending := make([]*po.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if task.Status == consts.NotRunned.ToInt() {
+			pending = append(pending, task)
+		}
+	}
+	return t.taskCache.BatchCreateTasks(ctx, pending, start, end)
+}
+
+// 一次性定时器只在 runAt 时刻执行，cron 定时器按所属时区由 cron 表达式推算
+func (t *TimerService) getExecuteTimes(timer *po.Timer, start, end time.Time) ([]time.Time, error) {
+	if timer.IsOnce() {
+		return timer.RunAtBetween(start, end), nil
+	}
+
+	loc, err := timer.Location()
+	if err != nil {
+		return nil, err
+	}
+	// cron 中的 H 按定时器 id 哈希为固定取值
+	expr, err := t.cronParser.ResolveHash(timer.Cron, uint64(timer.ID))
+	if err != nil {
+		return nil, err
+	}
+	nexts, err := t.cronParser.NextsBetweenIn(expr, loc, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return timer.JitteredTimes(nexts), nil
+}
+
+func (t *TimerService) checkSchedule(timer *po.Timer) error {
+	if _, err := timer.Location(); err != nil {
+		return fmt.Errorf("非法的时区: %s", timer.Timezone)
+	}
+	if timer.IsOnce() {
+		return nil
+	}
+	// 校验 cron 表达式
+	if !t.cronParser.IsValidCronExpr(timer.Cron) {
+		return fmt.Errorf("非法的 cron 表达式: %s", timer.Cron)
+	}
+	return t.checkJitter(timer)
+}
+
+// checkJitter 抖动窗口需小于相邻两次触发的最小间隔，否则后移后的执行时机可能重合或乱序，
+// 与 timer_id + run_timer 唯一键冲突导致整批执行记录写入失败
+func (t *TimerService) checkJitter(timer *po.Timer) error {
+	if timer.JitterSeconds <= 0 {
+		return nil
+	}
+
+	expr, err := t.cronParser.ResolveHash(timer.Cron, uint64(timer.ID))
+	if err != nil {
+		return err
+	}
+	interval, err := t.cronParser.MinInterval(expr, cronIntervalSamples)
+	if err != nil {
+		return err
+	}
+	if interval > 0 && time.Duration(timer.JitterSeconds)*time.Second >= interval {
+		return fmt.Errorf("jitterSeconds: %d should be less than the min cron interval: %v", timer.JitterSeconds, interval)
+	}
+	return nil
+}
+
+// 清理定时器尚未执行的执行时机，包括 mysql 中的 task 和 redis 跳表中的成员
+func (t *TimerService) purgeTasks(ctx context.Context, dao *timerdao.TimerDAO, timer *po.Timer) error {
+	tasks, err := dao.GetRecordsAfter(ctx, timer.ID, time.Now(), consts.NotRunned)
+	if err != nil {
+		return err
+	}
+
+	if err := dao.BatchDeleteRecords(ctx, tasks); err != nil {
+		return err
+	}
+	return t.taskCache.BatchDeleteTasks(ctx, tasks)
+}
+
+// 取消定时器尚未执行的执行时机，mysql 中的 task 置为已取消，redis 跳表中的成员直接移除.
+// 等待重试的 task 同样取消，重试消息到达时 executor 因定时器未激活而跳过；已过期的未执行 task 由补偿任务取消
+func (t *TimerService) cancelTasks(ctx context.Context, dao *timerdao.TimerDAO, timer *po.Timer) error {
+	tasks, err := dao.GetRecordsAfter(ctx, timer.ID, time.Now(), consts.NotRunned)
+	if err != nil {
+		return err
+	}
+	retrying, err := dao.GetRecords(ctx, timer.ID, consts.Retrying)
+	if err != nil {
+		return err
+	}
+
+	if err := dao.BatchCancelRecords(ctx, append(retrying, tasks...)); err != nil {
+		return err
+	}
+	return t.taskCache.BatchDeleteTasks(ctx, tasks)
+}
+
 func (t *TimerService) GetAppTimers(ctx context.Context, req *vo.GetAppTimersReq) ([]*vo.Timer, int64, error) {
-	total, err := t.dao.Count(ctx, timerdao.WithApp(req.App))
+	labelSelector, err := selector.Parse(req.Selector)
+	if err != nil {
+		return nil, -1, err
+	}
+	opts := []timerdao.Option{timerdao.WithApp(req.App), timerdao.WithLabelSelector(labelSelector)}
+
+	total, err := t.dao.Count(ctx, opts...)
 	if err != nil {
 		return nil, -1, err
 	}
@@ -161,7 +593,7 @@ func (t *TimerService) GetAppTimers(ctx context.Context, req *vo.GetAppTimersReq
 		return []*vo.Timer{}, total, nil
 	}
 
-	timers, err := t.dao.GetTimers(ctx, timerdao.WithApp(req.App), timerdao.WithPageLimit(offset, limit), timerdao.WithDesc())
+	timers, err := t.dao.GetTimers(ctx, append(opts, timerdao.WithPageLimit(offset, limit), timerdao.WithDesc())...)
 	if err != nil {
 		return nil, -1, err
 	}
@@ -171,11 +603,20 @@ func (t *TimerService) GetAppTimers(ctx context.Context, req *vo.GetAppTimersReq
 	})
 
 	vTimers, err := vo.NewTimers(timers)
-	return vTimers, total, err
+	if err != nil {
+		return nil, -1, err
+	}
+	return vTimers, total, t.withLabels(ctx, vTimers)
 }
 
 func (t *TimerService) GetTimersByName(ctx context.Context, req *vo.GetTimersByNameReq) ([]*vo.Timer, int64, error) {
-	total, err := t.dao.Count(ctx, timerdao.WithApp(req.App), timerdao.WithFuzzyName(req.FuzzyName))
+	labelSelector, err := selector.Parse(req.Selector)
+	if err != nil {
+		return nil, -1, err
+	}
+	opts := []timerdao.Option{timerdao.WithApp(req.App), timerdao.WithFuzzyName(req.FuzzyName), timerdao.WithLabelSelector(labelSelector)}
+
+	total, err := t.dao.Count(ctx, opts...)
 	if err != nil {
 		return nil, -1, err
 	}
@@ -185,7 +626,7 @@ func (t *TimerService) GetTimersByName(ctx context.Context, req *vo.GetTimersByN
 		return []*vo.Timer{}, total, nil
 	}
 
-	timers, err := t.dao.GetTimers(ctx, timerdao.WithApp(req.App), timerdao.WithPageLimit(offset, limit), timerdao.WithFuzzyName(req.FuzzyName))
+	timers, err := t.dao.GetTimers(ctx, append(opts, timerdao.WithPageLimit(offset, limit))...)
 	if err != nil {
 		return nil, -1, err
 	}
@@ -195,29 +636,346 @@ func (t *TimerService) GetTimersByName(ctx context.Context, req *vo.GetTimersByN
 	})
 
 	vTimers, err := vo.NewTimers(timers)
-	return vTimers, total, err
+	if err != nil {
+		return nil, -1, err
+	}
+	return vTimers, total, t.withLabels(ctx, vTimers)
+}
+
+// PreviewCron 试算 cron 表达式接下来的触发时机，并给出可读描述和可能不符合预期的告警，供创建定时器前校验
+func (t *TimerService) PreviewCron(ctx context.Context, req *vo.CronPreviewReq) (*vo.CronPreview, error) {
+	n := req.N
+	if n <= 0 {
+		n = defaultPreviewN
+	}
+	if n > maxPreviewN {
+		n = maxPreviewN
+	}
+
+	loc := time.Local
+	if req.TZ != "" {
+		var err error
+		if loc, err = time.LoadLocation(req.TZ); err != nil {
+			return nil, fmt.Errorf("非法的时区: %s", req.TZ)
+		}
+	}
+	if !t.cronParser.IsValidCronExpr(req.Cron) {
+		return nil, fmt.Errorf("invalid cron expr: %s", req.Cron)
+	}
+
+	expr, err := t.cronParser.ResolveHash(req.Cron, uint64(req.TimerID))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	nexts, err := t.cronParser.NextNIn(expr, loc, now, n)
+	if err != nil {
+		return nil, err
+	}
+	description, err := t.cronParser.Describe(req.Cron)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := t.cronParser.MinInterval(expr, cronIntervalSamples)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := make([]string, 0)
+	switch {
+	case len(nexts) == 0:
+		warnings = append(warnings, "never fires after now")
+	case nexts[0].After(now.AddDate(1, 0, 0)):
+		warnings = append(warnings, fmt.Sprintf("never fires before year %d", nexts[0].Year()))
+	}
+	if len(nexts) > 0 && len(nexts) < n {
+		warnings = append(warnings, fmt.Sprintf("fires only %d more times", len(nexts)))
+	}
+	if interval > 0 && interval < time.Minute {
+		warnings = append(warnings, "fires more than once per minute")
+	}
+	if t.cronParser.DomAndDowRestricted(req.Cron) {
+		warnings = append(warnings, "both day-of-month and day-of-week are restricted, fires when either matches")
+	}
+
+	for i := range nexts {
+		nexts[i] = nexts[i].In(loc)
+	}
+	return &vo.CronPreview{
+		Nexts:       nexts,
+		Description: description,
+		Warnings:    warnings,
+	}, nil
+}
+
+// saveVersion 定时器定义发生变化时版本号加一并写入新版本，在变更所在的事务中调用.
+// 版本记录上线前创建的定时器没有初始版本，先补录变更前的定义
+func (t *TimerService) saveVersion(ctx context.Context, dao *timerdao.TimerDAO, old, cur *po.Timer) error {
+	before, err := vo.NewTimerSnapshot(old)
+	if err != nil {
+		return err
+	}
+	after, err := vo.NewTimerSnapshot(cur)
+	if err != nil {
+		return err
+	}
+	if before == after {
+		return nil
+	}
+
+	_, err = dao.GetTimerVersion(ctx, old.ID, old.Version)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = t.createVersion(ctx, dao, old, (*vo.Principal)(nil).Actor())
+	}
+	if err != nil {
+		return err
+	}
+
+	cur.Version = old.Version + 1
+	if err := dao.UpdateTimer(ctx, &po.Timer{Model: gorm.Model{ID: cur.ID}, Version: cur.Version}); err != nil {
+		return err
+	}
+	return t.createVersion(ctx, dao, cur, vo.PrincipalFromContext(ctx).Actor())
+}
+
+func (t *TimerService) createVersion(ctx context.Context, dao *timerdao.TimerDAO, timer *po.Timer, actor string) error {
+	snapshot, err := vo.NewTimerSnapshot(timer)
+	if err != nil {
+		return err
+	}
+	return dao.CreateTimerVersion(ctx, &po.TimerVersion{
+		App:      timer.App,
+		TimerID:  timer.ID,
+		Version:  timer.Version,
+		Snapshot: snapshot,
+		Actor:    actor,
+	})
+}
+
+// GetTimerVersions 按版本号倒序查询定时器的历史版本
+func (t *TimerService) GetTimerVersions(ctx context.Context, req *vo.GetTimerVersionsReq) ([]*vo.TimerVersion, int64, error) {
+	if err := t.checkTimerApp(ctx, req.App, req.ID); err != nil {
+		return nil, -1, err
+	}
+
+	total, err := t.versionDAO.Count(ctx, versiondao.WithTimerID(req.ID))
+	if err != nil {
+		return nil, -1, err
+	}
+
+	offset, limit := req.Get()
+	if total <= int64(offset) {
+		return []*vo.TimerVersion{}, total, nil
+	}
+	versions, err := t.versionDAO.GetTimerVersions(ctx, versiondao.WithTimerID(req.ID), versiondao.WithPageLimit(offset, limit), versiondao.WithDesc())
+	if err != nil {
+		return nil, -1, err
+	}
+	return vo.NewTimerVersions(versions), total, nil
+}
+
+// DiffTimerVersions 比对定时器两个版本间有差异的字段
+func (t *TimerService) DiffTimerVersions(ctx context.Context, req *vo.DiffTimerVersionsReq) (*vo.TimerVersionDiff, error) {
+	if err := t.checkTimerApp(ctx, req.App, req.ID); err != nil {
+		return nil, err
+	}
+
+	versions, err := t.versionDAO.GetTimerVersions(ctx, versiondao.WithTimerID(req.ID), versiondao.WithVersions(req.From, req.To))
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int]*po.TimerVersion, len(versions))
+	for _, version := range versions {
+		byVersion[version.Version] = version
+	}
+	for _, version := range []int{req.From, req.To} {
+		if byVersion[version] == nil {
+			return nil, fmt.Errorf("version: %d of timer: %d not found", version, req.ID)
+		}
+	}
+	return vo.NewTimerVersionDiff(byVersion[req.From], byVersion[req.To])
+}
+
+// withLabels 从标签表中补充定时器的标签
+func (t *TimerService) withLabels(ctx context.Context, timers []*vo.Timer) error {
+	byID := make(map[uint]*vo.Timer, len(timers))
+	ids := make([]uint, 0, len(timers))
+	for _, timer := range timers {
+		byID[timer.ID] = timer
+		ids = append(ids, timer.ID)
+	}
+
+	labels, err := t.dao.GetTimerLabels(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, label := range labels {
+		timer, ok := byID[label.TimerID]
+		if !ok {
+			continue
+		}
+		if timer.Labels == nil {
+			timer.Labels = make(map[string]string)
+		}
+		timer.Labels[label.Key] = label.Value
+	}
+	return nil
+}
+
+// checkTimerApp 校验定时器属于指定应用
+func (t *TimerService) checkTimerApp(ctx context.Context, app string, id uint) error {
+	timer, err := t.dao.GetTimer(ctx, timerdao.WithID(id))
+	if err != nil {
+		return err
+	}
+	if timer.App != app {
+		return fmt.Errorf("timer not belongs to app: %s, timer id: %d", app, id)
+	}
+	return nil
+}
+
+// audit 在定时器变更所在的事务中写入审计日志，操作人取自 ctx 中的调用方身份.
+// before 为 nil 表示新建，after 为 nil 表示删除
+func (t *TimerService) audit(ctx context.Context, dao *timerdao.TimerDAO, action consts.AuditAction, before, after *po.Timer) error {
+	timer := after
+	if timer == nil {
+		timer = before
+	}
+
+	diff, err := vo.NewAuditDiff(before, after)
+	if err != nil {
+		return err
+	}
+	return dao.CreateAuditLog(ctx, &po.AuditLog{
+		App:     timer.App,
+		TimerID: timer.ID,
+		Actor:   vo.PrincipalFromContext(ctx).Actor(),
+		Action:  string(action),
+		Diff:    diff,
+	})
+}
+
+// getApp 获取应用的注册信息，未注册的应用不受配额限制，返回 nil
+func (t *TimerService) getApp(ctx context.Context, name string) (*po.App, error) {
+	app, err := t.appDAO.GetApp(ctx, name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return app, err
+}
+
+// checkCalendars 校验定时器引用的日历均已存在
+func (t *TimerService) checkCalendars(ctx context.Context, timer *po.Timer) error {
+	names := timer.CalendarNames()
+	if len(names) == 0 {
+		return nil
+	}
+
+	calendars, err := t.calendarDAO.GetTimerCalendars(ctx, timer)
+	if err != nil {
+		return err
+	}
+	existed := make(map[string]bool, len(calendars))
+	for _, calendar := range calendars {
+		existed[calendar.Name] = true
+	}
+	for _, name := range names {
+		if !existed[name] {
+			return fmt.Errorf("calendar: %s not found in app: %s", name, timer.App)
+		}
+	}
+	return nil
+}
+
+// checkCronInterval 校验 cron 定时器相邻两次触发的间隔不小于应用允许的最小间隔
+func (t *TimerService) checkCronInterval(app *po.App, timer *po.Timer) error {
+	if app == nil || app.MinCronIntervalSeconds <= 0 || timer.IsOnce() {
+		return nil
+	}
+
+	expr, err := t.cronParser.ResolveHash(timer.Cron, uint64(timer.ID))
+	if err != nil {
+		return err
+	}
+	interval, err := t.cronParser.MinInterval(expr, cronIntervalSamples)
+	if err != nil {
+		return err
+	}
+	if interval > 0 && interval < time.Duration(app.MinCronIntervalSeconds)*time.Second {
+		return fmt.Errorf("cron interval %v is less than the min interval of app: %s, min: %ds", interval, app.Name, app.MinCronIntervalSeconds)
+	}
+	return nil
+}
+
+// applyAppDefaults 为回调参数补充应用级别的默认请求头和超时时间，定时器自身的配置优先
+func applyAppDefaults(app *po.App, timer *vo.Timer) {
+	if app == nil || timer.NotifyHTTPParam == nil {
+		return
+	}
+
+	if timer.NotifyHTTPParam.TimeoutSeconds == 0 {
+		timer.NotifyHTTPParam.TimeoutSeconds = app.DefaultTimeoutSeconds
+	}
+	if app.DefaultHeaders == "" {
+		return
+	}
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(app.DefaultHeaders), &headers); err != nil {
+		return
+	}
+	if timer.NotifyHTTPParam.Header == nil {
+		timer.NotifyHTTPParam.Header = make(map[string]string, len(headers))
+	}
+	for k, v := range headers {
+		if _, ok := timer.NotifyHTTPParam.Header[k]; !ok {
+			timer.NotifyHTTPParam.Header[k] = v
+		}
+	}
 }
 
 type timerDAO interface {
 	CreateTimer(ctx context.Context, timer *po.Timer) (uint, error)
 	DeleteTimer(ctx context.Context, id uint) error
 	UpdateTimer(ctx context.Context, timer *po.Timer) error
+	GetTimerVersion(ctx context.Context, timerID uint, version int) (*po.TimerVersion, error)
+	GetTimerLabels(ctx context.Context, timerIDs []uint) ([]*po.TimerLabel, error)
 	GetTimer(ctx context.Context, opts ...timerdao.Option) (*po.Timer, error)
 	BatchCreateRecords(ctx context.Context, tasks []*po.Task) error
+	BatchDeleteRecords(ctx context.Context, tasks []*po.Task) error
 	DoWithLock(ctx context.Context, id uint, do func(ctx context.Context, dao *timerdao.TimerDAO, timer *po.Timer) error) error
+	Transaction(ctx context.Context, do func(ctx context.Context, dao *timerdao.TimerDAO) error) error
 	GetTimers(ctx context.Context, opts ...timerdao.Option) ([]*po.Timer, error)
 	Count(ctx context.Context, opts ...timerdao.Option) (int64, error)
 }
 
+type versionDAO interface {
+	GetTimerVersions(ctx context.Context, opts ...versiondao.Option) ([]*po.TimerVersion, error)
+	Count(ctx context.Context, opts ...versiondao.Option) (int64, error)
+}
+
+type calendarDAO interface {
+	GetTimerCalendars(ctx context.Context, timer *po.Timer) ([]*po.Calendar, error)
+}
+
 type confProvider interface {
 	Get() *conf.WebServerAppConf
 }
 
 type taskCache interface {
 	BatchCreateTasks(ctx context.Context, tasks []*po.Task, start, end time.Time) error
+	BatchDeleteTasks(ctx context.Context, tasks []*po.Task) error
+}
+
+type triggerProducer interface {
+	Send(ctx context.Context, timerIDUnixKey string) error
 }
 
 type cronParser interface {
-	NextsBefore(cron string, end time.Time) ([]time.Time, error)
+	NextsBetweenIn(cron string, loc *time.Location, start, end time.Time) ([]time.Time, error)
 	IsValidCronExpr(cron string) bool
+	MinInterval(cron string, n int) (time.Duration, error)
+	ResolveHash(cron string, seed uint64) (string, error)
+	NextNIn(cron string, loc *time.Location, start time.Time, n int) ([]time.Time, error)
+	Describe(cron string) (string, error)
+	DomAndDowRestricted(cron string) bool
 }